@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"learning-management-system/problems"
+	"os"
+)
+
+// Roles a token can grant
+
+const ROLE_TEACHER = "teacher"
+const ROLE_ADMIN = "admin"
+
+// Supported signing algorithms
+
+const HS256 = "HS256"
+const RS256 = "RS256"
+
+// Principal is the caller identified by a token, a teacher is identified by their email
+type Principal struct {
+	Email string
+	Role  string
+}
+
+func (principal *Principal) IsAdmin() bool {
+	return principal.Role == ROLE_ADMIN
+}
+
+// Claims are the claims read from a token, the subject is the email of the caller
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Authenticator validates tokens signed with a single algorithm and key, and optionally checks their issuer and audience
+type Authenticator struct {
+	algorithm string
+	key       interface{}
+	issuer    string
+	audience  string
+}
+
+func NewHmacAuthenticator(secret []byte, issuer string, audience string) *Authenticator {
+	return &Authenticator{algorithm: HS256, key: secret, issuer: issuer, audience: audience}
+}
+
+func NewRsaAuthenticator(publicKey *rsa.PublicKey, issuer string, audience string) *Authenticator {
+	return &Authenticator{algorithm: RS256, key: publicKey, issuer: issuer, audience: audience}
+}
+
+// LoadRsaPublicKey reads a PEM encoded RSA public key
+func LoadRsaPublicKey(filePath string) (*rsa.PublicKey, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read public key file %s: %v", filePath, err)
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(content)
+}
+
+// Authenticate validates the signature, expiry, issuer and audience of the token and returns its principal. Tokens
+// without an expiry are rejected, as they could never be revoked.
+func (authenticator *Authenticator) Authenticate(token string) (*Principal, error) {
+	claims := &Claims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{authenticator.algorithm}))
+
+	if _, err := parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return authenticator.key, nil
+	}); err != nil {
+		return nil, problems.Newf(problems.UNAUTHORIZED, "invalid", err)
+	}
+
+	if claims.ExpiresAt == nil {
+		return nil, problems.Newf(problems.UNAUTHORIZED, "expiry")
+	}
+
+	if authenticator.issuer != "" && !claims.VerifyIssuer(authenticator.issuer, true) {
+		return nil, problems.Newf(problems.UNAUTHORIZED, "issuer", authenticator.issuer)
+	}
+
+	if authenticator.audience != "" && !claims.VerifyAudience(authenticator.audience, true) {
+		return nil, problems.Newf(problems.UNAUTHORIZED, "audience", authenticator.audience)
+	}
+
+	if claims.Subject == "" {
+		return nil, problems.Newf(problems.UNAUTHORIZED, "subject")
+	}
+
+	if claims.Role != ROLE_TEACHER && claims.Role != ROLE_ADMIN {
+		return nil, problems.Newf(problems.UNAUTHORIZED, "role", ROLE_TEACHER, ROLE_ADMIN)
+	}
+
+	return &Principal{Email: claims.Subject, Role: claims.Role}, nil
+}
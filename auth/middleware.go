@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/problems"
+	"net/http"
+	"strings"
+)
+
+const principalContextKey = "principal"
+
+// Middleware rejects requests without a valid bearer token and stores the principal of the token in the context
+func (authenticator *Authenticator) Middleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		header := context.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+
+		if header == "" || token == header {
+			abortUnauthorized(context, problems.Newf(problems.UNAUTHORIZED, ""))
+			return
+		}
+
+		principal, err := authenticator.Authenticate(token)
+		if err != nil {
+			abortUnauthorized(context, err)
+			return
+		}
+
+		context.Set(principalContextKey, principal)
+		context.Next()
+	}
+}
+
+// RequireAdmin rejects requests whose principal is not an admin, it must run after Middleware
+func RequireAdmin() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if principal := GetPrincipal(context); principal == nil || !principal.IsAdmin() {
+			problems.Respond(context, http.StatusForbidden, problems.FORBIDDEN, problems.Newf(problems.FORBIDDEN, ""))
+			return
+		}
+		context.Next()
+	}
+}
+
+// GetPrincipal returns the principal authenticated by Middleware, or nil when authentication is disabled
+func GetPrincipal(context *gin.Context) *Principal {
+	if principal, ok := context.Get(principalContextKey); ok {
+		return principal.(*Principal)
+	}
+	return nil
+}
+
+// AuthorizeTeacher only lets teachers act as themselves, admins may act as any teacher. Every request is authorized
+// when authentication is disabled.
+func AuthorizeTeacher(context *gin.Context, teacherEmail string) error {
+	principal := GetPrincipal(context)
+
+	if principal == nil || principal.IsAdmin() || principal.Email == teacherEmail {
+		return nil
+	}
+	return problems.Newf(problems.FORBIDDEN, "teacher", principal.Email, teacherEmail).WithEmails(teacherEmail)
+}
+
+// AuthorizeStudent only lets the teachers the student is registered to act on the student, admins may act on any
+// student. Every request is authorized when authentication is disabled.
+func AuthorizeStudent(context *gin.Context, studentEmail string, registeredTeacherEmails []string) error {
+	principal := GetPrincipal(context)
+
+	if principal == nil || principal.IsAdmin() {
+		return nil
+	}
+	for _, teacherEmail := range registeredTeacherEmails {
+		if principal.Email == teacherEmail {
+			return nil
+		}
+	}
+	return problems.Newf(problems.FORBIDDEN, "student", principal.Email, studentEmail).WithEmails(studentEmail)
+}
+
+// abortUnauthorized reports the error with the UNAUTHORIZED code, the errors of Authenticate are plain errors
+func abortUnauthorized(context *gin.Context, err error) {
+	context.Header("WWW-Authenticate", `Bearer realm="lms"`)
+	problems.Respond(context, http.StatusUnauthorized, problems.UNAUTHORIZED, err)
+}
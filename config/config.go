@@ -0,0 +1,442 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"learning-management-system/auth"
+	"learning-management-system/database"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ENV_PREFIX prefixes the environment variables read by the server, e.g. LMS_SERVER_PORT
+const ENV_PREFIX = "LMS"
+
+// TEST_ENV_PREFIX prefixes the environment variables read by the test harness, e.g. LMS_TEST_DB_NAME, so that
+// tests can never pick up the production database by accident
+const TEST_ENV_PREFIX = "LMS_TEST"
+
+type Config struct {
+	Server        ServerConfig        `json:"server" yaml:"server"`
+	Database      DatabaseConfig      `json:"database" yaml:"database"`
+	Features      FeaturesConfig      `json:"features" yaml:"features"`
+	Scheduler     SchedulerConfig     `json:"scheduler" yaml:"scheduler"`
+	Notifications NotificationsConfig `json:"notifications" yaml:"notifications"`
+	Webhooks      WebhooksConfig      `json:"webhooks" yaml:"webhooks"`
+	Auth          AuthConfig          `json:"auth" yaml:"auth"`
+}
+
+type ServerConfig struct {
+	Port  string `json:"port" yaml:"port"`
+	Debug bool   `json:"debug" yaml:"debug"`
+}
+
+type DatabaseConfig struct {
+	Driver   string `json:"driver" yaml:"driver"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+	Name     string `json:"name" yaml:"name"`
+	Host     string `json:"host" yaml:"host"`
+	// Port defaults to the port of the driver when empty
+	Port string `json:"port" yaml:"port"`
+	// SslMode is the sslmode of postgres connections, such as require or verify-full, libpq's prefer when empty
+	SslMode string `json:"ssl_mode" yaml:"ssl_mode"`
+	Debug   bool   `json:"debug" yaml:"debug"`
+}
+
+type FeaturesConfig struct {
+	// TestingEndpoints enables the populate and clear endpoints when auth is disabled, they are always served to admins
+	// when auth is enabled
+	TestingEndpoints bool `json:"testing_endpoints" yaml:"testing_endpoints"`
+}
+
+type SchedulerConfig struct {
+	// SuspensionExpiryInterval is how often expired suspensions are lifted, as a duration such as "1m", "0" disables it
+	SuspensionExpiryInterval string `json:"suspension_expiry_interval" yaml:"suspension_expiry_interval"`
+}
+
+// Supported notifiers
+
+const LOG_NOTIFIER = "log"
+const SMTP_NOTIFIER = "smtp"
+
+type NotificationsConfig struct {
+	// Notifier delivers notifications, either LOG_NOTIFIER or SMTP_NOTIFIER
+	Notifier     string `json:"notifier" yaml:"notifier"`
+	SmtpHost     string `json:"smtp_host" yaml:"smtp_host"`
+	SmtpPort     string `json:"smtp_port" yaml:"smtp_port"`
+	SmtpUsername string `json:"smtp_username" yaml:"smtp_username"`
+	SmtpPassword string `json:"smtp_password" yaml:"smtp_password"`
+	SmtpFrom     string `json:"smtp_from" yaml:"smtp_from"`
+	// DeliveryInterval is how often failed deliveries are retried, as a duration such as "30s"
+	DeliveryInterval string `json:"delivery_interval" yaml:"delivery_interval"`
+	// RetryBackoff is the delay before retrying a failed delivery, doubling after every further failure
+	RetryBackoff string `json:"retry_backoff" yaml:"retry_backoff"`
+	MaxAttempts  int    `json:"max_attempts" yaml:"max_attempts"`
+}
+
+type WebhooksConfig struct {
+	// DeliveryInterval is how often failed webhook deliveries are retried, as a duration such as "30s"
+	DeliveryInterval string `json:"delivery_interval" yaml:"delivery_interval"`
+	// RetryBackoff is the delay before retrying a failed delivery, doubling after every further failure
+	RetryBackoff string `json:"retry_backoff" yaml:"retry_backoff"`
+	MaxAttempts  int    `json:"max_attempts" yaml:"max_attempts"`
+	// Timeout is how long to wait for a subscriber to respond before the delivery is considered failed
+	Timeout string `json:"timeout" yaml:"timeout"`
+}
+
+// MIN_HMAC_SECRET_LENGTH is the minimum length of an HS256 secret, shorter secrets can be brute forced
+const MIN_HMAC_SECRET_LENGTH = 32
+
+type AuthConfig struct {
+	// Enabled requires a bearer token on every api route
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Algorithm is the algorithm tokens are signed with, either HS256 with Secret or RS256 with PublicKeyFile
+	Algorithm     string `json:"algorithm" yaml:"algorithm"`
+	Secret        string `json:"secret" yaml:"secret"`
+	PublicKeyFile string `json:"public_key_file" yaml:"public_key_file"`
+	// Issuer and Audience are only checked when they are set
+	Issuer   string `json:"issuer" yaml:"issuer"`
+	Audience string `json:"audience" yaml:"audience"`
+}
+
+// Default returns the configuration used when neither a file nor environment variables override it
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:  "8080",
+			Debug: true,
+		},
+		Database: DatabaseConfig{
+			Driver:   database.MYSQL,
+			Username: "root",
+			Password: "",
+			Name:     "production_lms_db",
+			Host:     "localhost",
+		},
+		Features: FeaturesConfig{
+			TestingEndpoints: true,
+		},
+		Scheduler: SchedulerConfig{
+			SuspensionExpiryInterval: "1m",
+		},
+		Notifications: NotificationsConfig{
+			Notifier:         LOG_NOTIFIER,
+			SmtpPort:         "25",
+			DeliveryInterval: "30s",
+			RetryBackoff:     "1m",
+			MaxAttempts:      5,
+		},
+		Webhooks: WebhooksConfig{
+			DeliveryInterval: "30s",
+			RetryBackoff:     "1m",
+			MaxAttempts:      8,
+			Timeout:          "10s",
+		},
+		Auth: AuthConfig{
+			Enabled:   false,
+			Algorithm: auth.HS256,
+		},
+	}
+}
+
+// Load starts from the given defaults, applies the JSON or YAML file named by <prefix>_CONFIG_FILE if set, then
+// applies the <prefix>_* environment variables and finally validates the result
+func Load(prefix string, defaults *Config) (*Config, error) {
+	config := *defaults
+
+	if filePath, ok := os.LookupEnv(prefix + "_CONFIG_FILE"); ok && filePath != "" {
+		if err := loadFile(filePath, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(prefix, &config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func (config *Config) Validate() error {
+	if err := validatePort("server.port", config.Server.Port); err != nil {
+		return err
+	}
+
+	switch config.Database.Driver {
+	case database.MYSQL, database.POSTGRES:
+		if config.Database.Host == "" {
+			return fmt.Errorf("database.host must be provided for the %s driver", config.Database.Driver)
+		}
+		if config.Database.Port != "" {
+			if err := validatePort("database.port", config.Database.Port); err != nil {
+				return err
+			}
+		}
+	case database.SQLITE:
+	default:
+		return fmt.Errorf("database.driver must be one of %s, %s or %s, got %q", database.MYSQL, database.POSTGRES, database.SQLITE, config.Database.Driver)
+	}
+
+	switch config.Database.SslMode {
+	case "", database.SSL_MODE_DISABLE, database.SSL_MODE_ALLOW, database.SSL_MODE_PREFER, database.SSL_MODE_REQUIRE,
+		database.SSL_MODE_VERIFY_CA, database.SSL_MODE_VERIFY_FULL:
+	default:
+		return fmt.Errorf("database.ssl_mode must be one of %s, %s, %s, %s, %s or %s, got %q", database.SSL_MODE_DISABLE,
+			database.SSL_MODE_ALLOW, database.SSL_MODE_PREFER, database.SSL_MODE_REQUIRE, database.SSL_MODE_VERIFY_CA,
+			database.SSL_MODE_VERIFY_FULL, config.Database.SslMode)
+	}
+	if config.Database.SslMode != "" && config.Database.Driver != database.POSTGRES {
+		return fmt.Errorf("database.ssl_mode is only supported by the %s driver", database.POSTGRES)
+	}
+
+	if config.Database.Name == "" {
+		return fmt.Errorf("database.name must be provided")
+	}
+
+	if interval, err := time.ParseDuration(config.Scheduler.SuspensionExpiryInterval); err != nil || interval < 0 {
+		return fmt.Errorf("scheduler.suspension_expiry_interval must be a non-negative duration such as 1m, got %q", config.Scheduler.SuspensionExpiryInterval)
+	}
+
+	if err := config.Notifications.validate(); err != nil {
+		return err
+	}
+
+	if err := config.Webhooks.validate(); err != nil {
+		return err
+	}
+
+	return config.Auth.validate()
+}
+
+func (authConfig *AuthConfig) validate() error {
+	if !authConfig.Enabled {
+		return nil
+	}
+
+	switch authConfig.Algorithm {
+	case auth.HS256:
+		if len(authConfig.Secret) < MIN_HMAC_SECRET_LENGTH {
+			return fmt.Errorf("auth.secret must be at least %d characters long for %s", MIN_HMAC_SECRET_LENGTH, auth.HS256)
+		}
+	case auth.RS256:
+		if authConfig.PublicKeyFile == "" {
+			return fmt.Errorf("auth.public_key_file must be provided for %s", auth.RS256)
+		}
+	default:
+		return fmt.Errorf("auth.algorithm must be one of %s or %s, got %q", auth.HS256, auth.RS256, authConfig.Algorithm)
+	}
+
+	return nil
+}
+
+func (notificationsConfig *NotificationsConfig) validate() error {
+	switch notificationsConfig.Notifier {
+	case LOG_NOTIFIER:
+	case SMTP_NOTIFIER:
+		if notificationsConfig.SmtpHost == "" || notificationsConfig.SmtpFrom == "" {
+			return fmt.Errorf("notifications.smtp_host and notifications.smtp_from must be provided for the %s notifier", SMTP_NOTIFIER)
+		}
+		if err := validatePort("notifications.smtp_port", notificationsConfig.SmtpPort); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("notifications.notifier must be one of %s or %s, got %q", LOG_NOTIFIER, SMTP_NOTIFIER, notificationsConfig.Notifier)
+	}
+
+	if interval, err := time.ParseDuration(notificationsConfig.DeliveryInterval); err != nil || interval <= 0 {
+		return fmt.Errorf("notifications.delivery_interval must be a positive duration such as 30s, got %q", notificationsConfig.DeliveryInterval)
+	}
+
+	if backoff, err := time.ParseDuration(notificationsConfig.RetryBackoff); err != nil || backoff <= 0 {
+		return fmt.Errorf("notifications.retry_backoff must be a positive duration such as 1m, got %q", notificationsConfig.RetryBackoff)
+	}
+
+	if notificationsConfig.MaxAttempts < 1 {
+		return fmt.Errorf("notifications.max_attempts must be at least 1, got %d", notificationsConfig.MaxAttempts)
+	}
+
+	return nil
+}
+
+func (webhooksConfig *WebhooksConfig) validate() error {
+	durations := []struct {
+		field string
+		value string
+	}{
+		{"webhooks.delivery_interval", webhooksConfig.DeliveryInterval},
+		{"webhooks.retry_backoff", webhooksConfig.RetryBackoff},
+		{"webhooks.timeout", webhooksConfig.Timeout},
+	}
+	for _, duration := range durations {
+		if parsed, err := time.ParseDuration(duration.value); err != nil || parsed <= 0 {
+			return fmt.Errorf("%s must be a positive duration such as 30s, got %q", duration.field, duration.value)
+		}
+	}
+
+	if webhooksConfig.MaxAttempts < 1 {
+		return fmt.Errorf("webhooks.max_attempts must be at least 1, got %d", webhooksConfig.MaxAttempts)
+	}
+
+	return nil
+}
+
+func (databaseConfig *DatabaseConfig) Credentials() *database.Credentials {
+	return &database.Credentials{
+		Driver:   databaseConfig.Driver,
+		Username: databaseConfig.Username,
+		Password: databaseConfig.Password,
+		Name:     databaseConfig.Name,
+		Host:     databaseConfig.Host,
+		Port:     databaseConfig.Port,
+		SslMode:  databaseConfig.SslMode,
+		Debug:    databaseConfig.Debug,
+	}
+}
+
+// SuspensionExpiryIntervalDuration parses the interval, which Validate has already checked
+func (schedulerConfig *SchedulerConfig) SuspensionExpiryIntervalDuration() time.Duration {
+	interval, _ := time.ParseDuration(schedulerConfig.SuspensionExpiryInterval)
+	return interval
+}
+
+// DeliveryIntervalDuration parses the interval, which Validate has already checked
+func (notificationsConfig *NotificationsConfig) DeliveryIntervalDuration() time.Duration {
+	interval, _ := time.ParseDuration(notificationsConfig.DeliveryInterval)
+	return interval
+}
+
+// RetryBackoffDuration parses the backoff, which Validate has already checked
+func (notificationsConfig *NotificationsConfig) RetryBackoffDuration() time.Duration {
+	backoff, _ := time.ParseDuration(notificationsConfig.RetryBackoff)
+	return backoff
+}
+
+// DeliveryIntervalDuration parses the interval, which Validate has already checked
+func (webhooksConfig *WebhooksConfig) DeliveryIntervalDuration() time.Duration {
+	interval, _ := time.ParseDuration(webhooksConfig.DeliveryInterval)
+	return interval
+}
+
+// RetryBackoffDuration parses the backoff, which Validate has already checked
+func (webhooksConfig *WebhooksConfig) RetryBackoffDuration() time.Duration {
+	backoff, _ := time.ParseDuration(webhooksConfig.RetryBackoff)
+	return backoff
+}
+
+// TimeoutDuration parses the timeout, which Validate has already checked
+func (webhooksConfig *WebhooksConfig) TimeoutDuration() time.Duration {
+	timeout, _ := time.ParseDuration(webhooksConfig.Timeout)
+	return timeout
+}
+
+func validatePort(field string, port string) error {
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		return fmt.Errorf("%s must be a number between 1 and 65535, got %q", field, port)
+	}
+	return nil
+}
+
+func loadFile(filePath string, config *Config) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", filePath, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(content, config)
+	default:
+		return fmt.Errorf("config file %s must have a .json, .yaml or .yml extension", filePath)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not parse config file %s: %v", filePath, err)
+	}
+	return nil
+}
+
+func loadEnv(prefix string, config *Config) error {
+	stringVariables := map[string]*string{
+		"SERVER_PORT": &config.Server.Port,
+		"DB_DRIVER":   &config.Database.Driver,
+		"DB_USERNAME": &config.Database.Username,
+		"DB_PASSWORD": &config.Database.Password,
+		"DB_NAME":     &config.Database.Name,
+		"DB_HOST":     &config.Database.Host,
+		"DB_PORT":     &config.Database.Port,
+		"DB_SSL_MODE": &config.Database.SslMode,
+
+		"SCHEDULER_SUSPENSION_EXPIRY_INTERVAL": &config.Scheduler.SuspensionExpiryInterval,
+
+		"NOTIFICATIONS_NOTIFIER":          &config.Notifications.Notifier,
+		"NOTIFICATIONS_SMTP_HOST":         &config.Notifications.SmtpHost,
+		"NOTIFICATIONS_SMTP_PORT":         &config.Notifications.SmtpPort,
+		"NOTIFICATIONS_SMTP_USERNAME":     &config.Notifications.SmtpUsername,
+		"NOTIFICATIONS_SMTP_PASSWORD":     &config.Notifications.SmtpPassword,
+		"NOTIFICATIONS_SMTP_FROM":         &config.Notifications.SmtpFrom,
+		"NOTIFICATIONS_DELIVERY_INTERVAL": &config.Notifications.DeliveryInterval,
+		"NOTIFICATIONS_RETRY_BACKOFF":     &config.Notifications.RetryBackoff,
+
+		"WEBHOOKS_DELIVERY_INTERVAL": &config.Webhooks.DeliveryInterval,
+		"WEBHOOKS_RETRY_BACKOFF":     &config.Webhooks.RetryBackoff,
+		"WEBHOOKS_TIMEOUT":           &config.Webhooks.Timeout,
+
+		"AUTH_ALGORITHM":       &config.Auth.Algorithm,
+		"AUTH_SECRET":          &config.Auth.Secret,
+		"AUTH_PUBLIC_KEY_FILE": &config.Auth.PublicKeyFile,
+		"AUTH_ISSUER":          &config.Auth.Issuer,
+		"AUTH_AUDIENCE":        &config.Auth.Audience,
+	}
+	boolVariables := map[string]*bool{
+		"SERVER_DEBUG":              &config.Server.Debug,
+		"DB_DEBUG":                  &config.Database.Debug,
+		"FEATURE_TESTING_ENDPOINTS": &config.Features.TestingEndpoints,
+		"AUTH_ENABLED":              &config.Auth.Enabled,
+	}
+	intVariables := map[string]*int{
+		"NOTIFICATIONS_MAX_ATTEMPTS": &config.Notifications.MaxAttempts,
+		"WEBHOOKS_MAX_ATTEMPTS":      &config.Webhooks.MaxAttempts,
+	}
+
+	for name, field := range stringVariables {
+		if value, ok := os.LookupEnv(prefix + "_" + name); ok {
+			*field = value
+		}
+	}
+
+	for name, field := range boolVariables {
+		if value, ok := os.LookupEnv(prefix + "_" + name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s_%s must be a boolean, got %q", prefix, name, value)
+			}
+			*field = parsed
+		}
+	}
+
+	for name, field := range intVariables {
+		if value, ok := os.LookupEnv(prefix + "_" + name); ok {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s_%s must be an integer, got %q", prefix, name, value)
+			}
+			*field = parsed
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPrefix = "LMS_CONFIG_UNIT_TEST"
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	filePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+		t.Fatalf("could not write config file: %v", err)
+	}
+	return filePath
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := Load(testPrefix, Default())
+
+	if err != nil {
+		t.Errorf("defaults should be valid: %v", err)
+	}
+
+	if config.Server.Port != "8080" || config.Database.Name != "production_lms_db" || !config.Features.TestingEndpoints {
+		t.Errorf("wrong defaults: %+v", config)
+	}
+}
+
+func TestLoadEnvOverridesDefaults(t *testing.T) {
+	t.Setenv(testPrefix+"_SERVER_PORT", "9090")
+	t.Setenv(testPrefix+"_DB_DRIVER", "sqlite")
+	t.Setenv(testPrefix+"_DB_NAME", ":memory:")
+	t.Setenv(testPrefix+"_DB_DEBUG", "true")
+	t.Setenv(testPrefix+"_FEATURE_TESTING_ENDPOINTS", "false")
+
+	config, err := Load(testPrefix, Default())
+
+	if err != nil {
+		t.Fatalf("config should be valid: %v", err)
+	}
+
+	if config.Server.Port != "9090" || config.Database.Driver != "sqlite" || config.Database.Name != ":memory:" ||
+		!config.Database.Debug || config.Features.TestingEndpoints {
+		t.Errorf("wrong config: %+v", config)
+	}
+}
+
+func TestLoadSmtpNotifierRequiresHost(t *testing.T) {
+	t.Setenv(testPrefix+"_NOTIFICATIONS_NOTIFIER", "smtp")
+	t.Setenv(testPrefix+"_NOTIFICATIONS_SMTP_FROM", "lms@school.com")
+
+	if _, err := Load(testPrefix, Default()); err == nil {
+		t.Errorf("smtp notifier without a host should be rejected")
+	}
+
+	t.Setenv(testPrefix+"_NOTIFICATIONS_SMTP_HOST", "smtp.school.com")
+	t.Setenv(testPrefix+"_NOTIFICATIONS_MAX_ATTEMPTS", "3")
+
+	config, err := Load(testPrefix, Default())
+
+	if err != nil {
+		t.Fatalf("config should be valid: %v", err)
+	}
+
+	if config.Notifications.SmtpHost != "smtp.school.com" || config.Notifications.SmtpPort != "25" || config.Notifications.MaxAttempts != 3 {
+		t.Errorf("wrong config: %+v", config.Notifications)
+	}
+}
+
+func TestLoadAuthRequiresKeys(t *testing.T) {
+	t.Setenv(testPrefix+"_AUTH_ENABLED", "true")
+
+	if _, err := Load(testPrefix, Default()); err == nil {
+		t.Errorf("HS256 without a secret should be rejected")
+	}
+
+	t.Setenv(testPrefix+"_AUTH_SECRET", "too short")
+
+	if _, err := Load(testPrefix, Default()); err == nil {
+		t.Errorf("short HS256 secrets should be rejected")
+	}
+
+	t.Setenv(testPrefix+"_AUTH_ALGORITHM", "RS256")
+	t.Setenv(testPrefix+"_AUTH_PUBLIC_KEY_FILE", "/etc/lms/jwt.pub")
+
+	config, err := Load(testPrefix, Default())
+
+	if err != nil {
+		t.Fatalf("config should be valid: %v", err)
+	}
+
+	if !config.Auth.Enabled || config.Auth.PublicKeyFile != "/etc/lms/jwt.pub" {
+		t.Errorf("wrong config: %+v", config.Auth)
+	}
+}
+
+func TestLoadYamlFile(t *testing.T) {
+	filePath := writeConfigFile(t, "config.yaml", "server:\n  port: \"9091\"\ndatabase:\n  driver: postgres\n  port: \"5432\"\n")
+	t.Setenv(testPrefix+"_CONFIG_FILE", filePath)
+
+	config, err := Load(testPrefix, Default())
+
+	if err != nil {
+		t.Fatalf("config should be valid: %v", err)
+	}
+
+	if config.Server.Port != "9091" || config.Database.Driver != "postgres" || config.Database.Port != "5432" {
+		t.Errorf("wrong config: %+v", config)
+	}
+
+	if config.Database.Host != "localhost" {
+		t.Errorf("fields missing from the file should keep their defaults")
+	}
+}
+
+func TestLoadJsonFileIsOverriddenByEnv(t *testing.T) {
+	filePath := writeConfigFile(t, "config.json", `{"database": {"name": "from_file", "username": "file_user"}}`)
+	t.Setenv(testPrefix+"_CONFIG_FILE", filePath)
+	t.Setenv(testPrefix+"_DB_NAME", "from_env")
+
+	config, err := Load(testPrefix, Default())
+
+	if err != nil {
+		t.Fatalf("config should be valid: %v", err)
+	}
+
+	if config.Database.Name != "from_env" || config.Database.Username != "file_user" {
+		t.Errorf("wrong config: %+v", config)
+	}
+}
+
+func TestLoadRejectsUnknownFileFields(t *testing.T) {
+	filePath := writeConfigFile(t, "config.json", `{"database": {"nmae": "typo"}}`)
+	t.Setenv(testPrefix+"_CONFIG_FILE", filePath)
+
+	if _, err := Load(testPrefix, Default()); err == nil {
+		t.Errorf("unknown fields should be rejected")
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	invalidEnvs := map[string]string{
+		"_DB_DRIVER":   "oracle",
+		"_SERVER_PORT": "eighty",
+		"_DB_DEBUG":    "maybe",
+		"_DB_NAME":     "",
+		"_DB_SSL_MODE": "require",
+
+		"_NOTIFICATIONS_NOTIFIER":          "pigeon",
+		"_NOTIFICATIONS_DELIVERY_INTERVAL": "0s",
+		"_NOTIFICATIONS_MAX_ATTEMPTS":      "many",
+		"_WEBHOOKS_TIMEOUT":                "-1s",
+		"_WEBHOOKS_MAX_ATTEMPTS":           "0",
+	}
+
+	for name, value := range invalidEnvs {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(testPrefix+name, value)
+
+			if _, err := Load(testPrefix, Default()); err == nil {
+				t.Errorf("%s=%s should be rejected", name, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"net/http"
+)
+
+func (controller *Controller) CreateAssignment(context *gin.Context) {
+	createAssignmentRequest := &types.CreateAssignmentRequest{}
+
+	if contextErr := helpers.BindCreateAssignmentRequest(context, createAssignmentRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := createAssignmentRequest.TeacherEmail
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField("email", teacherEmail), helpers.ValidateMaxScore(createAssignmentRequest.MaxScore)); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	assignment := &models.Assignment{
+		TeacherEmail: teacherEmail,
+		Title:        createAssignmentRequest.Title,
+		Description:  createAssignmentRequest.Description,
+		DueAt:        createAssignmentRequest.DueAt,
+		MaxScore:     createAssignmentRequest.MaxScore,
+	}
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateTeachersExists([]string{teacherEmail}, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		return nil, controller.transactionManager.CreateAssignment(assignment, tx)
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, generateAssignmentResponse(assignment))
+}
+
+func (controller *Controller) RetrieveTeacherAssignments(context *gin.Context) {
+	retrieveTeacherAssignmentsRequest := &types.RetrieveTeacherAssignmentsRequest{}
+
+	if contextErr := helpers.BindRetrieveTeacherAssignmentsRequest(context, retrieveTeacherAssignmentsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := retrieveTeacherAssignmentsRequest.TeacherEmail
+	if !controller.authorizeExistingTeacher(context, teacherEmail) {
+		return
+	}
+
+	assignments, err := controller.transactionManager.RetrieveTeacherAssignments(teacherEmail, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveAssignmentsResponse{
+		TeacherEmail: teacherEmail,
+		Assignments:  helpers.Map(assignments, generateAssignmentResponse),
+	})
+}
+
+func (controller *Controller) RetrieveAssignment(context *gin.Context) {
+	retrieveAssignmentRequest := &types.RetrieveAssignmentRequest{}
+
+	if contextErr := helpers.BindRetrieveAssignmentRequest(context, retrieveAssignmentRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	assignment, authorized := controller.authorizeAssignmentTeacher(context, retrieveAssignmentRequest.AssignmentId)
+	if !authorized {
+		return
+	}
+
+	context.JSON(http.StatusOK, generateAssignmentResponse(assignment))
+}
+
+// SubmitAssignment stores the work of the student in the path on behalf of the teacher of the assignment, submitting
+// again replaces the previous work
+func (controller *Controller) SubmitAssignment(context *gin.Context) {
+	submitAssignmentRequest := &types.SubmitAssignmentRequest{}
+
+	if contextErr := helpers.BindSubmitAssignmentRequest(context, submitAssignmentRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	studentEmail := submitAssignmentRequest.StudentEmail
+
+	if validationErr := problems.Collect(
+		helpers.ValidateEmailField("email", studentEmail),
+		helpers.ValidateSubmission(submitAssignmentRequest.Text, submitAssignmentRequest.FileReference),
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if _, authorized := controller.authorizeAssignmentTeacher(context, submitAssignmentRequest.AssignmentId); !authorized {
+		return
+	}
+
+	var work *transaction_managers.AssignmentWork
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateStudentsExists([]string{studentEmail}, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		work, userError, dbError = controller.transactionManager.SubmitAssignment(submitAssignmentRequest.AssignmentId, studentEmail,
+			submitAssignmentRequest.Text, submitAssignmentRequest.FileReference, tx)
+		return userError, dbError
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusOK, generateSubmissionResponse(work))
+}
+
+func (controller *Controller) RetrieveAssignmentSubmissions(context *gin.Context) {
+	retrieveAssignmentSubmissionsRequest := &types.RetrieveAssignmentSubmissionsRequest{}
+
+	if contextErr := helpers.BindRetrieveAssignmentSubmissionsRequest(context, retrieveAssignmentSubmissionsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateWorkStatus(retrieveAssignmentSubmissionsRequest.Status); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if _, authorized := controller.authorizeAssignmentTeacher(context, retrieveAssignmentSubmissionsRequest.AssignmentId); !authorized {
+		return
+	}
+
+	works, userError, dbError := controller.transactionManager.RetrieveAssignmentWork(retrieveAssignmentSubmissionsRequest.AssignmentId,
+		retrieveAssignmentSubmissionsRequest.Status, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveSubmissionsResponse{Submissions: helpers.Map(works, generateSubmissionResponse)})
+}
+
+// RetrieveTeacherSubmissions lists the work of the students of the teacher on all of their assignments, the status
+// picks out for instance the pending or the late work
+func (controller *Controller) RetrieveTeacherSubmissions(context *gin.Context) {
+	retrieveTeacherSubmissionsRequest := &types.RetrieveTeacherSubmissionsRequest{}
+
+	if contextErr := helpers.BindRetrieveTeacherSubmissionsRequest(context, retrieveTeacherSubmissionsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateWorkStatus(retrieveTeacherSubmissionsRequest.Status); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	teacherEmail := retrieveTeacherSubmissionsRequest.TeacherEmail
+	if !controller.authorizeExistingTeacher(context, teacherEmail) {
+		return
+	}
+
+	works, err := controller.transactionManager.RetrieveTeacherWork(teacherEmail, retrieveTeacherSubmissionsRequest.Status, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveSubmissionsResponse{Submissions: helpers.Map(works, generateSubmissionResponse)})
+}
+
+// authorizeExistingTeacher responds with the problem and returns false when the email in the path is not the teacher
+// of the token or not a teacher. The teacher is authorized first, so that other teachers cannot tell which emails exist.
+func (controller *Controller) authorizeExistingTeacher(context *gin.Context, teacherEmail string) bool {
+	if validationErr := helpers.ValidateEmailField("email", teacherEmail); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return false
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return false
+	}
+
+	if userError, dbError := controller.transactionManager.ValidateTeachersExists([]string{teacherEmail}, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return false
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return false
+	}
+	return true
+}
+
+// authorizeAssignmentTeacher only lets admins and the teacher who set the assignment act on it, it responds with an
+// error and returns false otherwise
+func (controller *Controller) authorizeAssignmentTeacher(context *gin.Context, assignmentId uint) (*models.Assignment, bool) {
+	assignment, userError, dbError := controller.transactionManager.RetrieveAssignment(assignmentId, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return nil, false
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return nil, false
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, assignment.TeacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return nil, false
+	}
+	return assignment, true
+}
+
+func generateAssignmentResponse(assignment *models.Assignment) *types.AssignmentResponse {
+	return &types.AssignmentResponse{
+		AssignmentId: assignment.ID,
+		TeacherEmail: assignment.TeacherEmail,
+		Title:        assignment.Title,
+		Description:  assignment.Description,
+		DueAt:        assignment.DueAt,
+		MaxScore:     assignment.MaxScore,
+		CreatedAt:    assignment.CreatedAt,
+	}
+}
+
+func generateSubmissionResponse(work *transaction_managers.AssignmentWork) *types.SubmissionResponse {
+	response := &types.SubmissionResponse{
+		AssignmentId: work.Assignment.ID,
+		StudentEmail: work.StudentEmail,
+		Status:       work.Status,
+	}
+	if work.Submission != nil {
+		response.Text = work.Submission.Text
+		response.FileReference = work.Submission.FileReference
+		submittedAt := work.Submission.SubmittedAt
+		response.SubmittedAt = &submittedAt
+	}
+	return response
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"net/http"
+)
+
+// RecordRollCall records the attendance of the students registered to the teacher on the date of the path
+func (controller *Controller) RecordRollCall(context *gin.Context) {
+	recordRollCallRequest := &types.RecordRollCallRequest{}
+
+	if contextErr := helpers.BindRecordRollCallRequest(context, recordRollCallRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := recordRollCallRequest.TeacherEmail
+	studentEmails := helpers.SortedKeys(recordRollCallRequest.Statuses)
+
+	if validationErr := problems.Collect(
+		helpers.ValidateEmailField("email", teacherEmail),
+		helpers.ValidateDate("date", recordRollCallRequest.Date),
+		helpers.ValidateEmailField("students", studentEmails...),
+		helpers.ValidateAttendanceStatuses(recordRollCallRequest.Statuses),
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	var rollCall *transaction_managers.RollCall
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.validateTeacherAndStudentsExist(teacherEmail, studentEmails, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		rollCall, userError, dbError = controller.transactionManager.RecordRollCall(teacherEmail, recordRollCallRequest.Date, recordRollCallRequest.Statuses, tx)
+		return userError, dbError
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusOK, generateRollCallResponse(rollCall))
+}
+
+func (controller *Controller) RetrieveRollCall(context *gin.Context) {
+	retrieveRollCallRequest := &types.RetrieveRollCallRequest{}
+
+	if contextErr := helpers.BindRetrieveRollCallRequest(context, retrieveRollCallRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateDate("date", retrieveRollCallRequest.Date); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	teacherEmail := retrieveRollCallRequest.TeacherEmail
+	if !controller.authorizeExistingTeacher(context, teacherEmail) {
+		return
+	}
+
+	rollCall, err := controller.transactionManager.RetrieveRollCall(teacherEmail, retrieveRollCallRequest.Date, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateRollCallResponse(rollCall))
+}
+
+// RetrieveStudentAttendance lists the attendances of the student with the teacher, optionally between the from and to
+// dates, together with their rate
+func (controller *Controller) RetrieveStudentAttendance(context *gin.Context) {
+	retrieveStudentAttendanceRequest := &types.RetrieveStudentAttendanceRequest{}
+
+	if contextErr := helpers.BindRetrieveStudentAttendanceRequest(context, retrieveStudentAttendanceRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := retrieveStudentAttendanceRequest.TeacherEmail
+	studentEmail := retrieveStudentAttendanceRequest.StudentEmail
+
+	if validationErr := problems.Collect(
+		helpers.ValidateEmailField("email", teacherEmail),
+		helpers.ValidateEmailField("student", studentEmail),
+		helpers.ValidateDate("from", retrieveStudentAttendanceRequest.FromDate),
+		helpers.ValidateDate("to", retrieveStudentAttendanceRequest.ToDate),
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	if userError, dbError := controller.validateTeacherAndStudentsExist(teacherEmail, []string{studentEmail}, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	summary, err := controller.transactionManager.RetrieveStudentAttendance(teacherEmail, studentEmail,
+		retrieveStudentAttendanceRequest.FromDate, retrieveStudentAttendanceRequest.ToDate, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	rate := generateAttendanceRateResponse(summary)
+	context.JSON(http.StatusOK, &types.StudentAttendanceResponse{
+		TeacherEmail: teacherEmail,
+		StudentEmail: studentEmail,
+		Present:      rate.Present,
+		Absent:       rate.Absent,
+		Late:         rate.Late,
+		Excused:      rate.Excused,
+		Rate:         rate.Rate,
+		Records: helpers.Map(summary.Attendances, func(attendance *models.Attendance) *types.AttendanceRecordResponse {
+			return &types.AttendanceRecordResponse{Date: attendance.Date, Status: attendance.Status}
+		}),
+	})
+}
+
+// RetrieveAttendanceRates lists the attendance rates of the students of the teacher, optionally between the from and
+// to dates, and only of the students below the percentage of the below query parameter when it is given
+func (controller *Controller) RetrieveAttendanceRates(context *gin.Context) {
+	retrieveAttendanceRatesRequest := &types.RetrieveAttendanceRatesRequest{}
+
+	if contextErr := helpers.BindRetrieveAttendanceRatesRequest(context, retrieveAttendanceRatesRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := problems.Collect(
+		helpers.ValidateDate("from", retrieveAttendanceRatesRequest.FromDate),
+		helpers.ValidateDate("to", retrieveAttendanceRatesRequest.ToDate),
+		helpers.ValidateAttendanceThreshold(retrieveAttendanceRatesRequest.Below),
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	teacherEmail := retrieveAttendanceRatesRequest.TeacherEmail
+	if !controller.authorizeExistingTeacher(context, teacherEmail) {
+		return
+	}
+
+	summaries, err := controller.transactionManager.RetrieveAttendanceRates(teacherEmail, retrieveAttendanceRatesRequest.FromDate,
+		retrieveAttendanceRatesRequest.ToDate, retrieveAttendanceRatesRequest.Below, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveAttendanceRatesResponse{
+		TeacherEmail: teacherEmail,
+		Students:     helpers.Map(summaries, generateAttendanceRateResponse),
+	})
+}
+
+func generateRollCallResponse(rollCall *transaction_managers.RollCall) *types.RollCallResponse {
+	return &types.RollCallResponse{
+		TeacherEmail: rollCall.TeacherEmail,
+		Date:         rollCall.Date,
+		Students: helpers.Map(rollCall.Attendances, func(attendance *models.Attendance) *types.AttendanceResponse {
+			return &types.AttendanceResponse{StudentEmail: attendance.StudentEmail, Status: attendance.Status}
+		}),
+		UnrecordedStudentEmails: rollCall.UnrecordedStudentEmails,
+	}
+}
+
+func generateAttendanceRateResponse(summary *transaction_managers.AttendanceSummary) *types.AttendanceRateResponse {
+	return &types.AttendanceRateResponse{
+		StudentEmail: summary.StudentEmail,
+		Present:      summary.Counts[models.ATTENDANCE_PRESENT],
+		Absent:       summary.Counts[models.ATTENDANCE_ABSENT],
+		Late:         summary.Counts[models.ATTENDANCE_LATE],
+		Excused:      summary.Counts[models.ATTENDANCE_EXCUSED],
+		Rate:         summary.Rate,
+	}
+}
@@ -2,11 +2,16 @@ package controllers
 
 import (
 	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
 	"learning-management-system/database"
 	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
 	"learning-management-system/transaction_managers"
 	"learning-management-system/types"
+	"log"
 	"net/http"
+	"time"
 )
 
 type Controller struct {
@@ -14,10 +19,10 @@ type Controller struct {
 	transactionManager *transaction_managers.TransactionManager
 }
 
-func NewController(connection *database.Connection) *Controller {
+func NewController(connection *database.Connection, transactionManager *transaction_managers.TransactionManager) *Controller {
 	return &Controller{
 		connection:         connection,
-		transactionManager: transaction_managers.NewTransactionManager(),
+		transactionManager: transactionManager,
 	}
 }
 
@@ -30,49 +35,79 @@ func (controller *Controller) RegisterStudentsToTeacher(context *gin.Context) {
 		return
 	}
 
-	if validationErr := helpers.ValidateEmailAddresses(append(registerStudentsToTeacherRequest.StudentEmails, registerStudentsToTeacherRequest.TeacherEmail)); validationErr != nil {
-		generateBadRequestErrorResponse(context, validationErr)
+	if !controller.registerStudentsToTeacher(context, "teacher", registerStudentsToTeacherRequest.TeacherEmail, registerStudentsToTeacherRequest.StudentEmails,
+		registerStudentsToTeacherRequest.CourseCode, registerStudentsToTeacherRequest.SectionName) {
 		return
 	}
 
-	if userError, dbError := controller.transactionManager.ValidateTeachersExists([]string{registerStudentsToTeacherRequest.TeacherEmail}, controller.connection); dbError != nil {
-		generateInternalServerErrorResponse(context, dbError)
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func (controller *Controller) UnregisterStudentsFromTeacher(context *gin.Context) {
+
+	unregisterStudentsFromTeacherRequest := &types.UnregisterStudentsFromTeacherRequest{}
+
+	if contextErr := helpers.BindUnregisterStudentsFromTeacherRequest(context, unregisterStudentsFromTeacherRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
 		return
-	} else if userError != nil {
-		generateBadRequestErrorResponse(context, userError)
+	}
+
+	unregisteredStudentEmails, notRegisteredStudentEmails, ok := controller.unregisterStudentsFromTeacher(context, "teacher",
+		unregisterStudentsFromTeacherRequest.TeacherEmail, unregisterStudentsFromTeacherRequest.StudentEmails)
+	if !ok {
 		return
 	}
 
-	if userError, dbError := controller.transactionManager.ValidateStudentsExists(registerStudentsToTeacherRequest.StudentEmails, controller.connection); dbError != nil {
-		generateInternalServerErrorResponse(context, dbError)
+	context.JSON(http.StatusOK, &types.UnregisterStudentsFromTeacherResponse{
+		UnregisteredStudentEmails:  unregisteredStudentEmails,
+		NotRegisteredStudentEmails: notRegisteredStudentEmails,
+	})
+}
+
+func (controller *Controller) SuspendStudent(context *gin.Context) {
+	studentSuspension := &types.StudentSuspensionRequest{}
+
+	if contextErr := helpers.BindSuspendStudentRequest(context, studentSuspension); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
 		return
-	} else if userError != nil {
-		generateBadRequestErrorResponse(context, userError)
+	}
+
+	if !controller.suspendStudent(context, "student", studentSuspension.StudentEmail, studentSuspension.SuspendedBy, studentSuspension.Reason, studentSuspension.Until) {
 		return
 	}
 
-	if err := controller.transactionManager.RegisterStudentsToTeacher(registerStudentsToTeacherRequest.TeacherEmail, registerStudentsToTeacherRequest.StudentEmails, controller.connection); err != nil {
-		generateInternalServerErrorResponse(context, err)
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func (controller *Controller) UnsuspendStudent(context *gin.Context) {
+	studentUnsuspension := &types.StudentUnsuspensionRequest{}
+
+	if contextErr := helpers.BindUnsuspendStudentRequest(context, studentUnsuspension); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if !controller.unsuspendStudent(context, "student", studentUnsuspension.StudentEmail) {
 		return
 	}
 
 	context.JSON(http.StatusNoContent, nil)
 }
 
-func (controller *Controller) SuspendStudent(context *gin.Context) {
-	studentSuspension := &types.StudentSuspensionRequest{}
+func (controller *Controller) RetrieveStudentSuspensions(context *gin.Context) {
+	retrieveStudentSuspensionsRequest := &types.RetrieveStudentSuspensionsRequest{}
 
-	if contextErr := helpers.BindSuspendStudentRequest(context, studentSuspension); contextErr != nil {
+	if contextErr := helpers.BindRetrieveStudentSuspensionsRequest(context, retrieveStudentSuspensionsRequest); contextErr != nil {
 		generateBadRequestErrorResponse(context, contextErr)
 		return
 	}
 
-	if validationErr := helpers.ValidateEmailFormat(studentSuspension.StudentEmail); validationErr != nil {
+	if validationErr := helpers.ValidateEmailField("email", retrieveStudentSuspensionsRequest.StudentEmail); validationErr != nil {
 		generateBadRequestErrorResponse(context, validationErr)
 		return
 	}
 
-	if userError, dbError := controller.transactionManager.ValidateStudentsExists([]string{studentSuspension.StudentEmail}, controller.connection); dbError != nil {
+	if userError, dbError := controller.transactionManager.ValidateStudentsExists([]string{retrieveStudentSuspensionsRequest.StudentEmail}, controller.connection); dbError != nil {
 		generateInternalServerErrorResponse(context, dbError)
 		return
 	} else if userError != nil {
@@ -80,14 +115,28 @@ func (controller *Controller) SuspendStudent(context *gin.Context) {
 		return
 	}
 
-	if dbError := controller.transactionManager.SuspendStudent(studentSuspension.StudentEmail, controller.connection); dbError != nil {
-		generateInternalServerErrorResponse(context, dbError)
+	suspensions, dbErr := controller.transactionManager.RetrieveStudentSuspensions(retrieveStudentSuspensionsRequest.StudentEmail, controller.connection)
+	if dbErr != nil {
+		generateInternalServerErrorResponse(context, dbErr)
 		return
 	}
 
-	context.JSON(http.StatusNoContent, nil)
+	context.JSON(http.StatusOK, &types.RetrieveStudentSuspensionsResponse{
+		StudentEmail: retrieveStudentSuspensionsRequest.StudentEmail,
+		Suspensions: helpers.Map(suspensions, func(suspension *models.StudentSuspension) *types.StudentSuspensionResponse {
+			return &types.StudentSuspensionResponse{
+				SuspendedBy: suspension.SuspendedBy,
+				Reason:      suspension.Reason,
+				SuspendedAt: suspension.SuspendedAt,
+				Until:       suspension.Until,
+				LiftedAt:    suspension.LiftedAt,
+			}
+		}),
+	})
 }
 
+// RetrieveCommonStudents keeps the wire format of v1, whose clients get every common student unless they page them with
+// a limit or a cursor
 func (controller *Controller) RetrieveCommonStudents(context *gin.Context) {
 	retrieveCommonStudentsRequest := &types.RetrieveCommonStudentsRequest{}
 
@@ -96,14 +145,85 @@ func (controller *Controller) RetrieveCommonStudents(context *gin.Context) {
 		return
 	}
 
-	nonDuplicateTeacherEmails := helpers.RemoveDuplicatesInStringSlice(retrieveCommonStudentsRequest.TeacherEmails)
+	paged := retrieveCommonStudentsRequest.Limit != 0 || retrieveCommonStudentsRequest.Cursor != ""
+	controller.retrieveCommonStudents(context, retrieveCommonStudentsRequest, paged)
+}
+
+func (controller *Controller) RetrieveStudentRecipients(context *gin.Context) {
+	retrieveStudentRecipientsRequest := &types.RetrieveStudentRecipientsRequest{}
+
+	if contextErr := helpers.BindRetrieveStudentRecipientsRequest(context, retrieveStudentRecipientsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	// v1 clients that do not page get every recipient, as they did before the recipients were paged
+	var limit *int
+	if retrieveStudentRecipientsRequest.Limit != 0 {
+		limit = &retrieveStudentRecipientsRequest.Limit
+	}
+
+	_, recipientEmails, nextCursor, ok := controller.sendNotification(context, "teacher", retrieveStudentRecipientsRequest.TeacherEmail,
+		retrieveStudentRecipientsRequest.NotificationMessage, limit)
+	if !ok {
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveCommonStudentsResponse{
+		StudentEmails: recipientEmails,
+		NextCursor:    nextCursor,
+	})
+}
 
-	if validationErr := helpers.ValidateEmailAddresses(nonDuplicateTeacherEmails); validationErr != nil {
+// RetrieveMoreStudentRecipients continues the recipients returned by RetrieveStudentRecipients from a next_cursor
+func (controller *Controller) RetrieveMoreStudentRecipients(context *gin.Context) {
+	retrieveMoreStudentRecipientsRequest := &types.RetrieveMoreStudentRecipientsRequest{}
+
+	if contextErr := helpers.BindRetrieveMoreStudentRecipientsRequest(context, retrieveMoreStudentRecipientsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	cursor, validationErr := helpers.ValidateCursorPagination(&retrieveMoreStudentRecipientsRequest.Limit, retrieveMoreStudentRecipientsRequest.Cursor)
+	if validationErr == nil && cursor.NotificationId == 0 {
+		validationErr = problems.Newf(problems.INVALID_CURSOR, "", retrieveMoreStudentRecipientsRequest.Cursor).WithField("cursor")
+	}
+	if validationErr != nil {
 		generateBadRequestErrorResponse(context, validationErr)
 		return
 	}
 
-	if userError, dbError := controller.transactionManager.ValidateTeachersExists(nonDuplicateTeacherEmails, controller.connection); dbError != nil {
+	recipientEmails, nextCursor, ok := controller.retrieveNotificationRecipients(context, cursor.NotificationId, cursor.After, retrieveMoreStudentRecipientsRequest.Limit)
+	if !ok {
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveCommonStudentsResponse{
+		StudentEmails: recipientEmails,
+		NextCursor:    nextCursor,
+	})
+}
+
+func (controller *Controller) RetrieveStudentNotifications(context *gin.Context) {
+	retrieveStudentNotificationsRequest := &types.RetrieveStudentNotificationsRequest{}
+
+	if contextErr := helpers.BindRetrieveStudentNotificationsRequest(context, retrieveStudentNotificationsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	cursor, cursorErr := helpers.ValidateIdCursorPagination(&retrieveStudentNotificationsRequest.Limit, retrieveStudentNotificationsRequest.Cursor)
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField("email", retrieveStudentNotificationsRequest.StudentEmail), cursorErr); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if !controller.authorizeStudent(context, retrieveStudentNotificationsRequest.StudentEmail) {
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.ValidateStudentsExists([]string{retrieveStudentNotificationsRequest.StudentEmail}, controller.connection); dbError != nil {
 		generateInternalServerErrorResponse(context, dbError)
 		return
 	} else if userError != nil {
@@ -111,31 +231,51 @@ func (controller *Controller) RetrieveCommonStudents(context *gin.Context) {
 		return
 	}
 
-	studentEmails, dbErr := controller.transactionManager.RetrieveCommonStudentEmails(nonDuplicateTeacherEmails, controller.connection)
+	inbox, hasMore, total, unread, dbErr := controller.transactionManager.RetrieveStudentInbox(retrieveStudentNotificationsRequest.StudentEmail, retrieveStudentNotificationsRequest.UnreadOnly,
+		cursor.BeforeId, retrieveStudentNotificationsRequest.Limit, controller.connection)
 	if dbErr != nil {
 		generateInternalServerErrorResponse(context, dbErr)
 		return
 	}
 
-	context.JSON(http.StatusOK, &types.RetrieveRegisteredStudentsResponse{
-		StudentEmails: studentEmails,
+	context.JSON(http.StatusOK, &types.RetrieveStudentNotificationsResponse{
+		StudentEmail: retrieveStudentNotificationsRequest.StudentEmail,
+		Notifications: helpers.Map(inbox, func(entry *models.NotificationRecipient) *types.NotificationResponse {
+			return &types.NotificationResponse{
+				NotificationId: entry.NotificationID,
+				TeacherEmail:   entry.Notification.TeacherEmail,
+				Message:        entry.Notification.Message,
+				SentAt:         entry.Notification.SentAt,
+				Read:           entry.ReadAt != nil,
+				ReadAt:         entry.ReadAt,
+			}
+		}),
+		Total:  total,
+		Unread: unread,
+		NextCursor: helpers.NextIdCursor(inbox, hasMore, func(entry *models.NotificationRecipient) uint {
+			return entry.NotificationID
+		}),
 	})
 }
 
-func (controller *Controller) RetrieveStudentRecipients(context *gin.Context) {
-	retrieveStudentRecipientsRequest := &types.RetrieveStudentRecipientsRequest{}
+func (controller *Controller) MarkNotificationAsRead(context *gin.Context) {
+	markNotificationAsReadRequest := &types.MarkNotificationAsReadRequest{}
 
-	if contextErr := helpers.BindRetrieveStudentRecipientsRequest(context, retrieveStudentRecipientsRequest); contextErr != nil {
+	if contextErr := helpers.BindMarkNotificationAsReadRequest(context, markNotificationAsReadRequest); contextErr != nil {
 		generateBadRequestErrorResponse(context, contextErr)
 		return
 	}
 
-	if validationErr := helpers.ValidateEmailFormat(retrieveStudentRecipientsRequest.TeacherEmail); validationErr != nil {
+	if validationErr := helpers.ValidateEmailField("email", markNotificationAsReadRequest.StudentEmail); validationErr != nil {
 		generateBadRequestErrorResponse(context, validationErr)
 		return
 	}
 
-	if userError, dbError := controller.transactionManager.ValidateTeachersExists([]string{retrieveStudentRecipientsRequest.TeacherEmail}, controller.connection); dbError != nil {
+	if !controller.authorizeStudent(context, markNotificationAsReadRequest.StudentEmail) {
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.MarkNotificationAsRead(markNotificationAsReadRequest.NotificationId, markNotificationAsReadRequest.StudentEmail, controller.connection); dbError != nil {
 		generateInternalServerErrorResponse(context, dbError)
 		return
 	} else if userError != nil {
@@ -143,24 +283,41 @@ func (controller *Controller) RetrieveStudentRecipients(context *gin.Context) {
 		return
 	}
 
-	mentionedStudents := helpers.RemoveDuplicatesInStringSlice(helpers.FindValidEmailsInText(retrieveStudentRecipientsRequest.NotificationMessage))
+	context.JSON(http.StatusNoContent, nil)
+}
 
-	if userError, dbError := controller.transactionManager.ValidateStudentsExists(mentionedStudents, controller.connection); dbError != nil {
-		generateInternalServerErrorResponse(context, dbError)
+func (controller *Controller) RetrieveNotificationDeliveries(context *gin.Context) {
+	retrieveNotificationDeliveriesRequest := &types.RetrieveNotificationDeliveriesRequest{}
+
+	if contextErr := helpers.BindRetrieveNotificationDeliveriesRequest(context, retrieveNotificationDeliveriesRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
 		return
-	} else if userError != nil {
-		generateBadRequestErrorResponse(context, userError)
+	}
+
+	if !controller.authorizeNotificationTeacher(context, retrieveNotificationDeliveriesRequest.NotificationId) {
 		return
 	}
 
-	recipientEmails, dbErr := controller.transactionManager.RetrieveStudentRecipients(retrieveStudentRecipientsRequest.TeacherEmail, mentionedStudents, controller.connection)
-	if dbErr != nil {
-		generateInternalServerErrorResponse(context, dbErr)
+	deliveries, userError, dbError := controller.transactionManager.RetrieveNotificationDeliveries(retrieveNotificationDeliveriesRequest.NotificationId, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
 		return
 	}
 
-	context.JSON(http.StatusOK, &types.RetrieveCommonStudentsResponse{
-		StudentEmails: recipientEmails,
+	context.JSON(http.StatusOK, &types.RetrieveNotificationDeliveriesResponse{
+		NotificationId: retrieveNotificationDeliveriesRequest.NotificationId,
+		Deliveries: helpers.Map(deliveries, func(delivery *models.NotificationDelivery) *types.NotificationDeliveryResponse {
+			return &types.NotificationDeliveryResponse{
+				StudentEmail: delivery.StudentEmail,
+				Status:       delivery.Status,
+				Attempts:     delivery.Attempts,
+				LastError:    delivery.LastError,
+				DeliveredAt:  delivery.DeliveredAt,
+			}
+		}),
 	})
 }
 
@@ -181,13 +338,18 @@ func (controller *Controller) PopulateStudents(context *gin.Context) {
 		return
 	}
 
-	if validationErr := helpers.ValidateEmailAddresses(populateStudentsRequest.StudentEmails); validationErr != nil {
+	studentEmails, profiles := populateEntryProfiles(populateStudentsRequest.Students)
+	if validationErr := validatePopulateEntries("students", populateStudentsRequest.Students); validationErr != nil {
 		generateBadRequestErrorResponse(context, validationErr)
 		return
 	}
 
-	if err := controller.transactionManager.PopulateStudents(populateStudentsRequest.StudentEmails, controller.connection); err != nil {
-		generateInternalServerErrorResponse(context, err)
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if dbError = controller.transactionManager.PopulateStudents(studentEmails, tx); dbError != nil {
+			return nil, dbError
+		}
+		return nil, controller.transactionManager.SaveStudentProfiles(profiles, tx)
+	}) {
 		return
 	}
 
@@ -202,23 +364,411 @@ func (controller *Controller) PopulateTeachers(context *gin.Context) {
 		return
 	}
 
-	if validationErr := helpers.ValidateEmailAddresses(populateTeachersRequest.TeacherEmails); validationErr != nil {
+	teacherEmails, profiles := populateEntryProfiles(populateTeachersRequest.Teachers)
+	if validationErr := validatePopulateEntries("teachers", populateTeachersRequest.Teachers); validationErr != nil {
 		generateBadRequestErrorResponse(context, validationErr)
 		return
 	}
 
-	if err := controller.transactionManager.PopulateTeachers(populateTeachersRequest.TeacherEmails, controller.connection); err != nil {
-		generateInternalServerErrorResponse(context, err)
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if dbError = controller.transactionManager.PopulateTeachers(teacherEmails, tx); dbError != nil {
+			return nil, dbError
+		}
+		return nil, controller.transactionManager.SaveTeacherProfiles(profiles, tx)
+	}) {
 		return
 	}
 
 	context.JSON(http.StatusNoContent, nil)
 }
 
+// ImportRoster imports a CSV roster of teachers, students and registrations, answering with a report of the import.
+// The report lists the invalid lines with a 400 status, in which case nothing is imported.
+func (controller *Controller) ImportRoster(context *gin.Context) {
+	importRosterRequest := &types.ImportRosterRequest{}
+
+	if contextErr := helpers.BindImportRosterRequest(context, importRosterRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	file, err := importRosterRequest.File.Open()
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+	defer file.Close()
+
+	rows, validationErr := helpers.NewRosterCsvReader(file)
+	if validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	rosterImport, dbErr := controller.transactionManager.ImportRoster(rows, importRosterRequest.DryRun, controller.connection)
+	if dbErr != nil {
+		generateInternalServerErrorResponse(context, dbErr)
+		return
+	}
+
+	status := http.StatusOK
+	if rosterImport.InvalidRows > 0 {
+		status = http.StatusBadRequest
+	}
+	locale := problems.NegotiateLocale(context.GetHeader("Accept-Language"))
+
+	context.JSON(status, &types.ImportRosterResponse{
+		DryRun:                importRosterRequest.DryRun,
+		Committed:             rosterImport.Committed,
+		Rows:                  rosterImport.Rows,
+		Teachers:              rosterImport.Teachers,
+		Students:              rosterImport.Students,
+		Registrations:         rosterImport.Registrations,
+		ExistingRegistrations: rosterImport.ExistingRegistrations,
+		InvalidRows:           rosterImport.InvalidRows,
+		Errors: helpers.Map(rosterImport.RowErrors, func(rowError *helpers.RowError) *types.ImportRowErrorResponse {
+			return &types.ImportRowErrorResponse{Line: rowError.Line, Code: rowError.Problem.Code, Message: rowError.Problem.Localize(locale)}
+		}),
+	})
+}
+
+// ExportRoster streams every teacher, student and registration in the requested format. An error once the export has
+// started can only cut the response short, so it is logged.
+func (controller *Controller) ExportRoster(context *gin.Context) {
+	exportRosterRequest := &types.ExportRosterRequest{}
+
+	if contextErr := helpers.BindExportRosterRequest(context, exportRosterRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	writer, validationErr := helpers.NewRosterWriter(exportRosterRequest.Format, context.Writer)
+	if validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	context.Header("Content-Type", writer.ContentType())
+	context.Status(http.StatusOK)
+
+	if err := controller.transactionManager.ExportRoster(writer, controller.connection); err != nil {
+		log.Printf("Error exporting roster : error=%v", err)
+		context.Error(err)
+	}
+}
+
+// runInTransaction runs the validations and the action of a handler as a single unit of work, which returns a user
+// error to reject the request. Either error rolls the whole unit of work back and is answered with a 400 or a 500,
+// in which case runInTransaction returns false and the handler must not respond.
+func (controller *Controller) runInTransaction(context *gin.Context, unitOfWork func(tx *database.Connection) (userError error, dbError error)) bool {
+	var userError error
+	dbError := controller.transactionManager.WithTx(controller.connection, func(tx *database.Connection) error {
+		var dbError error
+		if userError, dbError = unitOfWork(tx); dbError != nil {
+			return dbError
+		}
+		return userError
+	})
+
+	if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return false
+	} else if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return false
+	}
+	return true
+}
+
+// The following methods hold the logic shared by the versions of an endpoint, which only differ in where the fields
+// of the request come from and in the shape of the response. They respond with an error and return false when the
+// request is rejected, the field names are those of the version for the problems of the response.
+
+// registerStudentsToTeacher also enrolls the students into the section of the course the teacher is assigned to when a
+// course is given, the section may only be left out when the teacher is assigned to a single section of the course
+func (controller *Controller) registerStudentsToTeacher(context *gin.Context, teacherField string, teacherEmail string, studentEmails []string, courseCode string, sectionName string) bool {
+	var courseErr error
+	if courseCode == "" && sectionName != "" {
+		courseErr = problems.Newf(problems.MISSING_FIELD, "", "course").WithField("course")
+	}
+
+	if validationErr := problems.Collect(
+		helpers.ValidateEmailField("students", studentEmails...),
+		helpers.ValidateEmailField(teacherField, teacherEmail),
+		courseErr,
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return false
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return false
+	}
+
+	return controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.validateTeacherAndStudentsExist(teacherEmail, studentEmails, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		var section *models.Section
+		if courseCode != "" {
+			if section, userError, dbError = controller.transactionManager.ResolveTeacherSection(teacherEmail, courseCode, sectionName, tx); userError != nil || dbError != nil {
+				return userError, dbError
+			}
+		}
+
+		if dbError = controller.transactionManager.RegisterStudentsToTeacher(teacherEmail, studentEmails, tx); dbError != nil || section == nil {
+			return nil, dbError
+		}
+		return nil, controller.transactionManager.EnrollStudentsInSection(section.ID, studentEmails, tx)
+	})
+}
+
+func (controller *Controller) unregisterStudentsFromTeacher(context *gin.Context, teacherField string, teacherEmail string, studentEmails []string) (unregisteredStudentEmails []string, notRegisteredStudentEmails []string, ok bool) {
+	studentEmails = helpers.RemoveDuplicatesInStringSlice(studentEmails)
+
+	if validationErr := problems.Collect(
+		helpers.ValidateEmailField(teacherField, teacherEmail),
+		helpers.ValidateEmailField("students", studentEmails...),
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return nil, nil, false
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return nil, nil, false
+	}
+
+	ok = controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.validateTeacherAndStudentsExist(teacherEmail, studentEmails, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		unregisteredStudentEmails, notRegisteredStudentEmails, dbError = controller.transactionManager.UnregisterStudentsFromTeacher(teacherEmail, studentEmails, tx)
+		return nil, dbError
+	})
+	return unregisteredStudentEmails, notRegisteredStudentEmails, ok
+}
+
+func (controller *Controller) suspendStudent(context *gin.Context, studentField string, studentEmail string, suspendedBy string, reason string, until *time.Time) bool {
+	var suspendedByErr, untilErr error
+	if suspendedBy != "" {
+		suspendedByErr = helpers.ValidateEmailField("suspended_by", suspendedBy)
+	}
+
+	if until != nil && !until.After(time.Now()) {
+		untilErr = problems.Newf(problems.INVALID_FIELD, "future", "until").WithField("until")
+	}
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField(studentField, studentEmail), suspendedByErr, untilErr); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return false
+	}
+
+	// when auth is enabled the suspension is made by the caller, who cannot record it in the name of someone else
+	if principal := auth.GetPrincipal(context); principal != nil {
+		if suspendedBy != "" && suspendedBy != principal.Email {
+			generateForbiddenErrorResponse(context, problems.Newf(problems.FORBIDDEN, "suspended_by", principal.Email, suspendedBy).WithField("suspended_by"))
+			return false
+		}
+		suspendedBy = principal.Email
+	}
+
+	return controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateStudentsExists([]string{studentEmail}, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		return nil, controller.transactionManager.SuspendStudent(studentEmail, suspendedBy, reason, until, tx)
+	})
+}
+
+func (controller *Controller) unsuspendStudent(context *gin.Context, studentField string, studentEmail string) bool {
+	if validationErr := helpers.ValidateEmailField(studentField, studentEmail); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return false
+	}
+
+	return controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateStudentsExists([]string{studentEmail}, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		return nil, controller.transactionManager.UnsuspendStudent(studentEmail, tx)
+	})
+}
+
+// retrieveCommonStudents responds with the common students of the request, a page of them when paged and all of them
+// otherwise
+func (controller *Controller) retrieveCommonStudents(context *gin.Context, request *types.RetrieveCommonStudentsRequest, paged bool) {
+	nonDuplicateTeacherEmails := helpers.RemoveDuplicatesInStringSlice(request.TeacherEmails)
+
+	mode, modeErr := helpers.ParseCommonStudentsMode(request.Mode, len(nonDuplicateTeacherEmails))
+
+	cursor := &helpers.Cursor{}
+	var cursorErr error
+	if paged {
+		cursor, cursorErr = helpers.ValidateCursorPagination(&request.Limit, request.Cursor)
+	}
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField("teacher", nonDuplicateTeacherEmails...), modeErr, cursorErr); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.ValidateTeachersExists(nonDuplicateTeacherEmails, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	if request.CourseCode != "" {
+		if userError, dbError := controller.transactionManager.ValidateCourseExists(request.CourseCode, controller.connection); dbError != nil {
+			generateInternalServerErrorResponse(context, dbError)
+			return
+		} else if userError != nil {
+			generateBadRequestErrorResponse(context, userError)
+			return
+		}
+	}
+
+	studentEmails, hasMore, dbErr := controller.transactionManager.RetrieveCommonStudentEmails(nonDuplicateTeacherEmails, request.CourseCode, mode, cursor.After, request.Limit, controller.connection)
+	if dbErr != nil {
+		generateInternalServerErrorResponse(context, dbErr)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveRegisteredStudentsResponse{
+		StudentEmails: studentEmails,
+		NextCursor:    helpers.NextCursor(studentEmails, hasMore, 0),
+	})
+}
+
+// sendNotification stores the notification of the teacher and returns the first page of its recipients, or all of them
+// when limit is nil
+func (controller *Controller) sendNotification(context *gin.Context, teacherField string, teacherEmail string, message string, limit *int) (notificationId uint, recipientEmails []string, nextCursor string, ok bool) {
+	var limitErr error
+	if limit != nil {
+		_, limitErr = helpers.ValidateCursorPagination(limit, "")
+	}
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField(teacherField, teacherEmail), limitErr); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return 0, nil, "", false
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return 0, nil, "", false
+	}
+
+	mentionedStudents := helpers.RemoveDuplicatesInStringSlice(helpers.FindValidEmailsInText(message))
+
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.validateTeacherAndStudentsExist(teacherEmail, mentionedStudents, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		notificationId, recipientEmails, dbError = controller.transactionManager.RetrieveStudentRecipients(teacherEmail, message, mentionedStudents, tx)
+		return nil, dbError
+	}) {
+		return 0, nil, "", false
+	}
+
+	if limit == nil {
+		return notificationId, recipientEmails, "", true
+	}
+
+	// every recipient is resolved to store the notification, only the first page is returned
+	hasMore := len(recipientEmails) > *limit
+	if hasMore {
+		recipientEmails = recipientEmails[:*limit]
+	}
+
+	return notificationId, recipientEmails, helpers.NextCursor(recipientEmails, hasMore, notificationId), true
+}
+
+// retrieveNotificationRecipients returns the page of the recipients of a notification after an email, the cursors of
+// the pages are not signed so the teacher of the notification is authorized again for every page
+func (controller *Controller) retrieveNotificationRecipients(context *gin.Context, notificationId uint, after string, limit int) (recipientEmails []string, nextCursor string, ok bool) {
+	if !controller.authorizeNotificationTeacher(context, notificationId) {
+		return nil, "", false
+	}
+
+	recipientEmails, hasMore, userError, dbError := controller.transactionManager.RetrieveNotificationRecipientEmails(notificationId, after, limit, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return nil, "", false
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return nil, "", false
+	}
+
+	return recipientEmails, helpers.NextCursor(recipientEmails, hasMore, notificationId), true
+}
+
+// validateTeacherAndStudentsExist reports a missing teacher and missing students together
+func (controller *Controller) validateTeacherAndStudentsExist(teacherEmail string, studentEmails []string, tx *database.Connection) (userError error, dbError error) {
+	teacherError, dbError := controller.transactionManager.ValidateTeachersExists([]string{teacherEmail}, tx)
+	if dbError != nil {
+		return nil, dbError
+	}
+
+	studentsError, dbError := controller.transactionManager.ValidateStudentsExists(studentEmails, tx)
+	if dbError != nil {
+		return nil, dbError
+	}
+
+	return problems.Collect(teacherError, studentsError), nil
+}
+
+// authorizeNotificationTeacher only lets admins and the teacher who sent the notification act on it, it responds with an
+// error and returns false otherwise
+func (controller *Controller) authorizeNotificationTeacher(context *gin.Context, notificationId uint) bool {
+	notification, userError, dbError := controller.transactionManager.RetrieveNotification(notificationId, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return false
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return false
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, notification.TeacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return false
+	}
+	return true
+}
+
+// authorizeStudent only lets admins and the teachers the student is registered to act on the student, it responds with
+// an error and returns false otherwise
+func (controller *Controller) authorizeStudent(context *gin.Context, studentEmail string) bool {
+	teacherEmails, dbErr := controller.transactionManager.RetrieveStudentTeacherEmails(studentEmail, controller.connection)
+	if dbErr != nil {
+		generateInternalServerErrorResponse(context, dbErr)
+		return false
+	}
+
+	if authErr := auth.AuthorizeStudent(context, studentEmail, teacherEmails); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return false
+	}
+	return true
+}
+
 func generateBadRequestErrorResponse(context *gin.Context, err error) {
-	context.AbortWithStatusJSON(400, types.ErrorResponse{Message: err.Error()})
+	problems.Respond(context, http.StatusBadRequest, problems.BAD_REQUEST, err)
+}
+
+func generateForbiddenErrorResponse(context *gin.Context, err error) {
+	problems.Respond(context, http.StatusForbidden, problems.FORBIDDEN, err)
 }
 
 func generateInternalServerErrorResponse(context *gin.Context, err error) {
-	context.AbortWithStatusJSON(500, types.ErrorResponse{Message: err.Error()})
+	problems.Respond(context, http.StatusInternalServerError, problems.INTERNAL_ERROR, err)
 }
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"net/http"
+)
+
+// SaveCourse creates the course of the code in the path, or renames it if it already exists
+func (controller *Controller) SaveCourse(context *gin.Context) {
+	saveCourseRequest := &types.SaveCourseRequest{}
+
+	if contextErr := helpers.BindSaveCourseRequest(context, saveCourseRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	course, err := controller.transactionManager.SaveCourse(saveCourseRequest.CourseCode, saveCourseRequest.Name, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateCourseResponse(course, nil))
+}
+
+func (controller *Controller) RetrieveCourses(context *gin.Context) {
+	courses, err := controller.transactionManager.RetrieveCourses(controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveCoursesResponse{
+		Courses: helpers.Map(courses, func(course *models.Course) *types.CourseResponse {
+			return generateCourseResponse(course, nil)
+		}),
+	})
+}
+
+func (controller *Controller) RetrieveCourse(context *gin.Context) {
+	retrieveCourseRequest := &types.RetrieveCourseRequest{}
+
+	if contextErr := helpers.BindRetrieveCourseRequest(context, retrieveCourseRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	course, sectionRosters, userError, dbError := controller.transactionManager.RetrieveCourse(retrieveCourseRequest.CourseCode, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateCourseResponse(course, sectionRosters))
+}
+
+// SaveSection creates the section of the course if it does not exist yet and assigns the teachers to it
+func (controller *Controller) SaveSection(context *gin.Context) {
+	saveSectionRequest := &types.SaveSectionRequest{}
+
+	if contextErr := helpers.BindSaveSectionRequest(context, saveSectionRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmails := helpers.RemoveDuplicatesInStringSlice(saveSectionRequest.TeacherEmails)
+
+	if validationErr := helpers.ValidateEmailField("teachers", teacherEmails...); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	var sectionRoster *transaction_managers.SectionRoster
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateTeachersExists(teacherEmails, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		sectionRoster, userError, dbError = controller.transactionManager.SaveSection(saveSectionRequest.CourseCode, saveSectionRequest.SectionName, teacherEmails, tx)
+		return userError, dbError
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusOK, generateSectionResponse(sectionRoster))
+}
+
+func (controller *Controller) RetrieveSection(context *gin.Context) {
+	retrieveSectionRequest := &types.RetrieveSectionRequest{}
+
+	if contextErr := helpers.BindRetrieveSectionRequest(context, retrieveSectionRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	sectionRoster, userError, dbError := controller.transactionManager.RetrieveSection(retrieveSectionRequest.CourseCode, retrieveSectionRequest.SectionName, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateSectionResponse(sectionRoster))
+}
+
+// EnrollStudents enrolls the students into the section, unlike a registration with a course it does not register them
+// to the teachers of the section
+func (controller *Controller) EnrollStudents(context *gin.Context) {
+	enrollStudentsRequest := &types.EnrollStudentsRequest{}
+
+	if contextErr := helpers.BindEnrollStudentsRequest(context, enrollStudentsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	studentEmails := helpers.RemoveDuplicatesInStringSlice(enrollStudentsRequest.StudentEmails)
+
+	if validationErr := helpers.ValidateEmailField("students", studentEmails...); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateStudentsExists(studentEmails, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		section, userError, dbError := controller.transactionManager.ResolveSection(enrollStudentsRequest.CourseCode, enrollStudentsRequest.SectionName, tx)
+		if userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		return nil, controller.transactionManager.EnrollStudentsInSection(section.ID, studentEmails, tx)
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func generateCourseResponse(course *models.Course, sectionRosters []*transaction_managers.SectionRoster) *types.CourseResponse {
+	response := &types.CourseResponse{Code: course.Code, Name: course.Name}
+	if sectionRosters != nil {
+		response.Sections = helpers.Map(sectionRosters, generateSectionResponse)
+	}
+	return response
+}
+
+func generateSectionResponse(sectionRoster *transaction_managers.SectionRoster) *types.SectionResponse {
+	return &types.SectionResponse{
+		CourseCode:    sectionRoster.Section.CourseCode,
+		Name:          sectionRoster.Section.Name,
+		TeacherEmails: sectionRoster.TeacherEmails,
+		StudentEmails: sectionRoster.StudentEmails,
+	}
+}
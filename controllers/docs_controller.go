@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"io/fs"
+	"learning-management-system/helpers"
+	"learning-management-system/openapi"
+	"learning-management-system/types"
+	"net/http"
+)
+
+// swaggerUiPage loads Swagger UI from the assets served with it and points it at the OpenAPI document
+const swaggerUiPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Learning Management System API</title>
+  <link rel="stylesheet" href="/api/docs/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/api/docs/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/api/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// swaggerUiAssets are the content types of the Swagger UI files the page loads, they are embedded in the binary with
+// the version of Swagger UI pinned in go.mod, so the docs neither depend on nor trust a CDN
+var swaggerUiAssets = map[string]string{
+	"swagger-ui.css":       "text/css; charset=utf-8",
+	"swagger-ui-bundle.js": "application/javascript; charset=utf-8",
+}
+
+func (controller *Controller) RetrieveOpenApiDocument(context *gin.Context) {
+	context.JSON(http.StatusOK, openapi.Build())
+}
+
+func (controller *Controller) RetrieveSwaggerUi(context *gin.Context) {
+	context.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUiPage))
+}
+
+func (controller *Controller) RetrieveSwaggerUiAsset(context *gin.Context) {
+	retrieveSwaggerUiAssetRequest := &types.RetrieveSwaggerUiAssetRequest{}
+
+	if contextErr := helpers.BindRetrieveSwaggerUiAssetRequest(context, retrieveSwaggerUiAssetRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	contentType, ok := swaggerUiAssets[retrieveSwaggerUiAssetRequest.File]
+	if !ok {
+		context.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	asset, err := fs.ReadFile(swaggerFiles.FS, retrieveSwaggerUiAssetRequest.File)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.Data(http.StatusOK, contentType, asset)
+}
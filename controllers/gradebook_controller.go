@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"net/http"
+)
+
+// SaveGradeCategory creates the category of the teacher in the path, or changes its weight if it already exists
+func (controller *Controller) SaveGradeCategory(context *gin.Context) {
+	saveGradeCategoryRequest := &types.SaveGradeCategoryRequest{}
+
+	if contextErr := helpers.BindSaveGradeCategoryRequest(context, saveGradeCategoryRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := saveGradeCategoryRequest.TeacherEmail
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField("email", teacherEmail), helpers.ValidatePositive("weight", saveGradeCategoryRequest.Weight)); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	var category *models.GradeCategory
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.transactionManager.ValidateTeachersExists([]string{teacherEmail}, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		category, dbError = controller.transactionManager.SaveGradeCategory(teacherEmail, saveGradeCategoryRequest.Name, saveGradeCategoryRequest.Weight, tx)
+		return nil, dbError
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusOK, generateGradeCategoryResponse(category))
+}
+
+func (controller *Controller) RetrieveGradeCategories(context *gin.Context) {
+	retrieveGradeCategoriesRequest := &types.RetrieveGradeCategoriesRequest{}
+
+	if contextErr := helpers.BindRetrieveGradeCategoriesRequest(context, retrieveGradeCategoriesRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := retrieveGradeCategoriesRequest.TeacherEmail
+	if !controller.authorizeExistingTeacher(context, teacherEmail) {
+		return
+	}
+
+	categories, err := controller.transactionManager.RetrieveGradeCategories(teacherEmail, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveGradeCategoriesResponse{
+		TeacherEmail: teacherEmail,
+		Categories:   helpers.Map(categories, generateGradeCategoryResponse),
+	})
+}
+
+// GradeStudent stores a grade the teacher gives a student registered to them
+func (controller *Controller) GradeStudent(context *gin.Context) {
+	gradeStudentRequest := &types.GradeStudentRequest{}
+
+	if contextErr := helpers.BindGradeStudentRequest(context, gradeStudentRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := gradeStudentRequest.TeacherEmail
+	studentEmail := gradeStudentRequest.StudentEmail
+
+	var maxScoreErr error
+	if gradeStudentRequest.MaxScore != 0 {
+		maxScoreErr = helpers.ValidatePositive("max_score", gradeStudentRequest.MaxScore)
+	}
+
+	if validationErr := problems.Collect(
+		helpers.ValidateEmailField("email", teacherEmail),
+		helpers.ValidateEmailField("student", studentEmail),
+		helpers.ValidateScore(*gradeStudentRequest.Score),
+		maxScoreErr,
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	grade := &models.Grade{
+		TeacherEmail: teacherEmail,
+		StudentEmail: studentEmail,
+		AssignmentID: gradeStudentRequest.AssignmentId,
+		Title:        gradeStudentRequest.Title,
+		Score:        *gradeStudentRequest.Score,
+		MaxScore:     gradeStudentRequest.MaxScore,
+	}
+	if !controller.runInTransaction(context, func(tx *database.Connection) (userError error, dbError error) {
+		if userError, dbError = controller.validateTeacherAndStudentsExist(teacherEmail, []string{studentEmail}, tx); userError != nil || dbError != nil {
+			return userError, dbError
+		}
+
+		return controller.transactionManager.GradeStudent(grade, gradeStudentRequest.CategoryName, tx)
+	}) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, generateGradeResponse(grade, gradeStudentRequest.CategoryName))
+}
+
+func (controller *Controller) RetrieveGradebook(context *gin.Context) {
+	retrieveGradebookRequest := &types.RetrieveGradebookRequest{}
+
+	if contextErr := helpers.BindRetrieveGradebookRequest(context, retrieveGradebookRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	teacherEmail := retrieveGradebookRequest.TeacherEmail
+	studentEmail := retrieveGradebookRequest.StudentEmail
+
+	if validationErr := problems.Collect(helpers.ValidateEmailField("email", teacherEmail), helpers.ValidateEmailField("student", studentEmail)); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, teacherEmail); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	if userError, dbError := controller.validateTeacherAndStudentsExist(teacherEmail, []string{studentEmail}, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	gradebook, userError, dbError := controller.transactionManager.RetrieveGradebook(teacherEmail, studentEmail, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateGradebookResponse(gradebook))
+}
+
+// RetrieveReportCard sums up the grades of the student with every teacher they are registered to, which only those
+// teachers and admins may read
+func (controller *Controller) RetrieveReportCard(context *gin.Context) {
+	retrieveReportCardRequest := &types.RetrieveReportCardRequest{}
+
+	if contextErr := helpers.BindRetrieveReportCardRequest(context, retrieveReportCardRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	studentEmail := retrieveReportCardRequest.StudentEmail
+
+	if validationErr := helpers.ValidateEmailField("email", studentEmail); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if !controller.authorizeStudent(context, studentEmail) {
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.ValidateStudentsExists([]string{studentEmail}, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	gradebooks, average, err := controller.transactionManager.RetrieveReportCard(studentEmail, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.ReportCardResponse{
+		StudentEmail: studentEmail,
+		Teachers: helpers.Map(gradebooks, func(gradebook *transaction_managers.Gradebook) *types.ReportCardEntryResponse {
+			return &types.ReportCardEntryResponse{
+				TeacherEmail: gradebook.TeacherEmail,
+				Average:      gradebook.Average,
+				LetterGrade:  letterGradeOf(gradebook.Average),
+			}
+		}),
+		Average:     average,
+		LetterGrade: letterGradeOf(average),
+	})
+}
+
+func generateGradeCategoryResponse(category *models.GradeCategory) *types.GradeCategoryResponse {
+	return &types.GradeCategoryResponse{
+		TeacherEmail: category.TeacherEmail,
+		Name:         category.Name,
+		Weight:       category.Weight,
+	}
+}
+
+func generateGradeResponse(grade *models.Grade, categoryName string) *types.GradeResponse {
+	return &types.GradeResponse{
+		GradeId:      grade.ID,
+		TeacherEmail: grade.TeacherEmail,
+		StudentEmail: grade.StudentEmail,
+		CategoryName: categoryName,
+		AssignmentId: grade.AssignmentID,
+		Title:        grade.Title,
+		Score:        grade.Score,
+		MaxScore:     grade.MaxScore,
+		GradedAt:     grade.GradedAt,
+	}
+}
+
+func generateGradebookResponse(gradebook *transaction_managers.Gradebook) *types.GradebookResponse {
+	return &types.GradebookResponse{
+		TeacherEmail: gradebook.TeacherEmail,
+		StudentEmail: gradebook.StudentEmail,
+		Categories: helpers.Map(gradebook.Categories, func(categoryGrades *transaction_managers.CategoryGrades) *types.CategoryGradesResponse {
+			return &types.CategoryGradesResponse{
+				Name:    categoryGrades.Category.Name,
+				Weight:  categoryGrades.Category.Weight,
+				Average: categoryGrades.Average,
+				Grades: helpers.Map(categoryGrades.Grades, func(grade *models.Grade) *types.GradeResponse {
+					return generateGradeResponse(grade, categoryGrades.Category.Name)
+				}),
+			}
+		}),
+		Average:     gradebook.Average,
+		LetterGrade: letterGradeOf(gradebook.Average),
+	}
+}
+
+// letterGradeOf returns the letter grade of the average, or no letter grade when there is no average
+func letterGradeOf(average *float64) string {
+	if average == nil {
+		return ""
+	}
+	return helpers.LetterGrade(*average)
+}
@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"net/http"
+)
+
+func (controller *Controller) RetrieveStudent(context *gin.Context) {
+	retrieveProfileRequest := &types.RetrieveProfileRequest{}
+
+	if contextErr := helpers.BindRetrieveProfileRequest(context, retrieveProfileRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateEmailField("email", retrieveProfileRequest.Email); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if !controller.authorizeStudent(context, retrieveProfileRequest.Email) {
+		return
+	}
+
+	student, userError, dbError := controller.transactionManager.RetrieveStudent(retrieveProfileRequest.Email, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateStudentResponse(student))
+}
+
+// UpdateStudent changes the profile fields in the body and keeps the others
+func (controller *Controller) UpdateStudent(context *gin.Context) {
+	updateProfileRequest := &types.UpdateProfileRequest{}
+
+	if contextErr := helpers.BindUpdateProfileRequest(context, updateProfileRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := validateProfileUpdate(updateProfileRequest); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	student, userError, dbError := controller.transactionManager.UpdateStudentProfile(updateProfileRequest.Email, generateProfileUpdate(updateProfileRequest), controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateStudentResponse(student))
+}
+
+// DeleteStudent deletes the student together with their registrations, suspensions, notifications, enrollments,
+// submissions, grades and attendances
+func (controller *Controller) DeleteStudent(context *gin.Context) {
+	deleteProfileRequest := &types.DeleteProfileRequest{}
+
+	if contextErr := helpers.BindDeleteProfileRequest(context, deleteProfileRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateEmailField("email", deleteProfileRequest.Email); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.DeleteStudent(deleteProfileRequest.Email, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func (controller *Controller) RetrieveTeacher(context *gin.Context) {
+	retrieveProfileRequest := &types.RetrieveProfileRequest{}
+
+	if contextErr := helpers.BindRetrieveProfileRequest(context, retrieveProfileRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateEmailField("email", retrieveProfileRequest.Email); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	teacher, userError, dbError := controller.transactionManager.RetrieveTeacher(retrieveProfileRequest.Email, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateTeacherResponse(teacher))
+}
+
+// UpdateTeacher changes the profile fields in the body and keeps the others, teachers can update their own profile
+func (controller *Controller) UpdateTeacher(context *gin.Context) {
+	updateProfileRequest := &types.UpdateProfileRequest{}
+
+	if contextErr := helpers.BindUpdateProfileRequest(context, updateProfileRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := validateProfileUpdate(updateProfileRequest); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if authErr := auth.AuthorizeTeacher(context, updateProfileRequest.Email); authErr != nil {
+		generateForbiddenErrorResponse(context, authErr)
+		return
+	}
+
+	teacher, userError, dbError := controller.transactionManager.UpdateTeacherProfile(updateProfileRequest.Email, generateProfileUpdate(updateProfileRequest), controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, generateTeacherResponse(teacher))
+}
+
+// DeleteTeacher deletes the teacher together with their notifications, assignments, grades, attendances,
+// registrations and assignments to sections
+func (controller *Controller) DeleteTeacher(context *gin.Context) {
+	deleteProfileRequest := &types.DeleteProfileRequest{}
+
+	if contextErr := helpers.BindDeleteProfileRequest(context, deleteProfileRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := helpers.ValidateEmailField("email", deleteProfileRequest.Email); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.DeleteTeacher(deleteProfileRequest.Email, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func validateProfileUpdate(updateProfileRequest *types.UpdateProfileRequest) error {
+	var phoneErr error
+	if updateProfileRequest.Phone != nil {
+		phoneErr = helpers.ValidatePhone("phone", *updateProfileRequest.Phone)
+	}
+	return problems.Collect(helpers.ValidateEmailField("email", updateProfileRequest.Email), phoneErr)
+}
+
+// validatePopulateEntries validates the emails of the entries and the phones of their profiles
+func validatePopulateEntries(field string, entries []types.PopulateEntry) error {
+	errs := []error{helpers.ValidateEmailField(field, helpers.Map(entries, func(entry types.PopulateEntry) string { return entry.Email })...)}
+	for _, entry := range entries {
+		errs = append(errs, helpers.ValidatePhone("phone", entry.Phone, entry.Email))
+	}
+	return problems.Collect(errs...)
+}
+
+// populateEntryProfiles returns the emails of the entries, and the profiles of the entries given as objects keyed by
+// their email
+func populateEntryProfiles(entries []types.PopulateEntry) (emails []string, profiles map[string]*models.Profile) {
+	emails = make([]string, 0, len(entries))
+	profiles = make(map[string]*models.Profile)
+	for _, entry := range entries {
+		emails = append(emails, entry.Email)
+		if entry.HasProfile {
+			profile := &models.Profile{
+				Name:        entry.Name,
+				DisplayName: entry.DisplayName,
+				GradeLevel:  entry.GradeLevel,
+				Homeroom:    entry.Homeroom,
+				Phone:       entry.Phone,
+			}
+			profile.SetMetadata(entry.Metadata)
+			profiles[entry.Email] = profile
+		}
+	}
+	return emails, profiles
+}
+
+func generateProfileUpdate(updateProfileRequest *types.UpdateProfileRequest) *transaction_managers.ProfileUpdate {
+	return &transaction_managers.ProfileUpdate{
+		Name:        updateProfileRequest.Name,
+		DisplayName: updateProfileRequest.DisplayName,
+		GradeLevel:  updateProfileRequest.GradeLevel,
+		Homeroom:    updateProfileRequest.Homeroom,
+		Phone:       updateProfileRequest.Phone,
+		Metadata:    updateProfileRequest.Metadata,
+	}
+}
+
+func generateStudentResponse(student *models.Student) *types.StudentResponse {
+	return &types.StudentResponse{
+		Email:       student.Email,
+		IsSuspended: student.IsSuspended,
+		Name:        student.Profile.Name,
+		DisplayName: student.Profile.DisplayName,
+		GradeLevel:  student.Profile.GradeLevel,
+		Homeroom:    student.Profile.Homeroom,
+		Phone:       student.Profile.Phone,
+		Metadata:    student.Profile.MetadataMap(),
+	}
+}
+
+func generateTeacherResponse(teacher *models.Teacher) *types.TeacherResponse {
+	return &types.TeacherResponse{
+		Email:       teacher.Email,
+		Name:        teacher.Profile.Name,
+		DisplayName: teacher.Profile.DisplayName,
+		GradeLevel:  teacher.Profile.GradeLevel,
+		Homeroom:    teacher.Profile.Homeroom,
+		Phone:       teacher.Profile.Phone,
+		Metadata:    teacher.Profile.MetadataMap(),
+	}
+}
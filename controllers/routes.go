@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
+	"learning-management-system/problems"
+)
+
+const API_PATH = "/api"
+const API_V1_PATH = "/api/v1"
+const API_V2_PATH = "/api/v2"
+
+// RegisterRoutes registers the docs and every version of the api. When authenticate is not nil every api route requires
+// a token it accepts, and the admin routes additionally require the admin role. The testing endpoints are admin routes,
+// so they are always registered then, and may only be left out when authentication is disabled.
+func (controller *Controller) RegisterRoutes(router *gin.Engine, authenticate gin.HandlerFunc, testingEndpoints bool) {
+	testingEndpoints = testingEndpoints || authenticate != nil
+
+	// the docs are public, so they are registered outside of the api groups that require a token
+	docs := router.Group(API_PATH)
+	docs.GET("/openapi.json", controller.RetrieveOpenApiDocument)
+	docs.GET("/docs", controller.RetrieveSwaggerUi)
+	docs.GET("/docs/:file", controller.RetrieveSwaggerUiAsset)
+
+	// the unversioned routes predate versioning, they are kept as a deprecated alias of v1
+	api, admin := apiGroups(router, API_PATH, authenticate, deprecatedBy(API_V1_PATH), problems.WithMessage())
+	controller.registerV1Routes(api, admin, testingEndpoints)
+
+	api, admin = apiGroups(router, API_V1_PATH, authenticate, problems.WithMessage())
+	controller.registerV1Routes(api, admin, testingEndpoints)
+
+	api, admin = apiGroups(router, API_V2_PATH, authenticate)
+	controller.registerV2Routes(api, admin, testingEndpoints)
+}
+
+// registerV1Routes registers the routes whose requests and responses keep the wire format of the first api
+func (controller *Controller) registerV1Routes(api *gin.RouterGroup, admin *gin.RouterGroup, testingEndpoints bool) {
+	api.POST("/register", controller.RegisterStudentsToTeacher)
+	api.POST("/unregister", controller.UnregisterStudentsFromTeacher)
+	api.GET("/commonstudents", controller.RetrieveCommonStudents)
+	admin.POST("/suspend", controller.SuspendStudent)
+	admin.POST("/unsuspend", controller.UnsuspendStudent)
+	api.GET("/students/:email/suspensions", controller.RetrieveStudentSuspensions)
+	api.POST("/retrievefornotifications", controller.RetrieveStudentRecipients)
+	api.GET("/retrievefornotifications", controller.RetrieveMoreStudentRecipients)
+	api.GET("/students/:email/notifications", controller.RetrieveStudentNotifications)
+	api.POST("/students/:email/notifications/:id/read", controller.MarkNotificationAsRead)
+	api.GET("/notifications/:id/deliveries", controller.RetrieveNotificationDeliveries)
+	admin.POST("/webhooks", controller.CreateWebhookSubscription)
+	admin.GET("/webhooks", controller.RetrieveWebhookSubscriptions)
+	admin.DELETE("/webhooks/:id", controller.DeleteWebhookSubscription)
+	admin.GET("/webhooks/:id/deliveries", controller.RetrieveWebhookDeliveries)
+	admin.POST("/import", controller.ImportRoster)
+	admin.GET("/export", controller.ExportRoster)
+	controller.registerResourceRoutes(api, admin)
+
+	if testingEndpoints {
+		admin.DELETE("/clear", controller.ClearDatabase)
+		admin.POST("/populateteachers", controller.PopulateTeachers)
+		admin.POST("/populatestudents", controller.PopulateStudents)
+	}
+}
+
+// registerV2Routes registers the resource oriented routes, the emails and ids they act on are in their paths
+func (controller *Controller) registerV2Routes(api *gin.RouterGroup, admin *gin.RouterGroup, testingEndpoints bool) {
+	api.GET("/students", controller.RetrieveCommonStudentsV2)
+	api.POST("/teachers/:email/students", controller.RegisterStudentsToTeacherV2)
+	api.DELETE("/teachers/:email/students", controller.UnregisterStudentsFromTeacherV2)
+	api.POST("/teachers/:email/notifications", controller.SendNotificationV2)
+	api.GET("/notifications/:id/recipients", controller.RetrieveNotificationRecipientsV2)
+	api.GET("/notifications/:id/deliveries", controller.RetrieveNotificationDeliveries)
+	api.GET("/students/:email/suspensions", controller.RetrieveStudentSuspensions)
+	admin.POST("/students/:email/suspension", controller.SuspendStudentV2)
+	admin.DELETE("/students/:email/suspension", controller.UnsuspendStudentV2)
+	api.GET("/students/:email/notifications", controller.RetrieveStudentNotifications)
+	api.POST("/students/:email/notifications/:id/read", controller.MarkNotificationAsRead)
+	admin.POST("/webhooks", controller.CreateWebhookSubscription)
+	admin.GET("/webhooks", controller.RetrieveWebhookSubscriptions)
+	admin.DELETE("/webhooks/:id", controller.DeleteWebhookSubscription)
+	admin.GET("/webhooks/:id/deliveries", controller.RetrieveWebhookDeliveries)
+	admin.POST("/roster", controller.ImportRoster)
+	admin.GET("/roster", controller.ExportRoster)
+	controller.registerResourceRoutes(api, admin)
+
+	if testingEndpoints {
+		admin.DELETE("/database", controller.ClearDatabase)
+		admin.POST("/teachers", controller.PopulateTeachers)
+		admin.POST("/students", controller.PopulateStudents)
+	}
+}
+
+// registerResourceRoutes registers the routes of the courses, assignments, gradebooks, attendance and profiles, which
+// are resource oriented in every version
+func (controller *Controller) registerResourceRoutes(api *gin.RouterGroup, admin *gin.RouterGroup) {
+	api.GET("/courses", controller.RetrieveCourses)
+	api.GET("/courses/:code", controller.RetrieveCourse)
+	admin.PUT("/courses/:code", controller.SaveCourse)
+	api.GET("/courses/:code/sections/:section", controller.RetrieveSection)
+	admin.PUT("/courses/:code/sections/:section", controller.SaveSection)
+	admin.POST("/courses/:code/sections/:section/students", controller.EnrollStudents)
+
+	api.POST("/teachers/:email/assignments", controller.CreateAssignment)
+	api.GET("/teachers/:email/assignments", controller.RetrieveTeacherAssignments)
+	api.GET("/teachers/:email/submissions", controller.RetrieveTeacherSubmissions)
+	api.GET("/assignments/:id", controller.RetrieveAssignment)
+	api.GET("/assignments/:id/submissions", controller.RetrieveAssignmentSubmissions)
+	api.PUT("/assignments/:id/submissions/:email", controller.SubmitAssignment)
+
+	api.PUT("/teachers/:email/categories/:name", controller.SaveGradeCategory)
+	api.GET("/teachers/:email/categories", controller.RetrieveGradeCategories)
+	api.POST("/teachers/:email/students/:student/grades", controller.GradeStudent)
+	api.GET("/teachers/:email/students/:student/gradebook", controller.RetrieveGradebook)
+	api.GET("/students/:email/reportcard", controller.RetrieveReportCard)
+
+	api.GET("/teachers/:email/attendance", controller.RetrieveAttendanceRates)
+	api.PUT("/teachers/:email/attendance/:date", controller.RecordRollCall)
+	api.GET("/teachers/:email/attendance/:date", controller.RetrieveRollCall)
+	api.GET("/teachers/:email/students/:student/attendance", controller.RetrieveStudentAttendance)
+
+	api.GET("/students/:email", controller.RetrieveStudent)
+	admin.PATCH("/students/:email", controller.UpdateStudent)
+	admin.DELETE("/students/:email", controller.DeleteStudent)
+	api.GET("/teachers/:email", controller.RetrieveTeacher)
+	api.PATCH("/teachers/:email", controller.UpdateTeacher)
+	admin.DELETE("/teachers/:email", controller.DeleteTeacher)
+}
+
+// apiGroups returns the group of the routes under the path and its subgroup of admin routes
+func apiGroups(router *gin.Engine, path string, authenticate gin.HandlerFunc, handlers ...gin.HandlerFunc) (api *gin.RouterGroup, admin *gin.RouterGroup) {
+	api = router.Group(path, handlers...)
+	if authenticate != nil {
+		api.Use(authenticate)
+	}
+	admin = api.Group("")
+	if authenticate != nil {
+		admin.Use(auth.RequireAdmin())
+	}
+	return api, admin
+}
+
+// deprecatedBy marks the responses of deprecated routes and links to the path of the routes replacing them
+func deprecatedBy(successorPath string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Header("Deprecation", "true")
+		context.Header("Link", "<"+successorPath+">; rel=\"successor-version\"")
+		context.Next()
+	}
+}
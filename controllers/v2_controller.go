@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/helpers"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"net/http"
+)
+
+// RetrieveCommonStudentsV2 always pages the common students, DEFAULT_PAGE_LIMIT at a time unless a limit is given
+func (controller *Controller) RetrieveCommonStudentsV2(context *gin.Context) {
+	retrieveCommonStudentsRequest := &types.RetrieveCommonStudentsRequest{}
+
+	if contextErr := helpers.BindRetrieveCommonStudentsRequest(context, retrieveCommonStudentsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	controller.retrieveCommonStudents(context, retrieveCommonStudentsRequest, true)
+}
+
+func (controller *Controller) RegisterStudentsToTeacherV2(context *gin.Context) {
+	registerStudentsToTeacherRequest := &types.RegisterStudentsToTeacherV2Request{}
+
+	if contextErr := helpers.BindRegisterStudentsToTeacherV2Request(context, registerStudentsToTeacherRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if !controller.registerStudentsToTeacher(context, "email", registerStudentsToTeacherRequest.TeacherEmail, registerStudentsToTeacherRequest.StudentEmails,
+		registerStudentsToTeacherRequest.CourseCode, registerStudentsToTeacherRequest.SectionName) {
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func (controller *Controller) UnregisterStudentsFromTeacherV2(context *gin.Context) {
+	unregisterStudentsFromTeacherRequest := &types.UnregisterStudentsFromTeacherV2Request{}
+
+	if contextErr := helpers.BindUnregisterStudentsFromTeacherV2Request(context, unregisterStudentsFromTeacherRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	unregisteredStudentEmails, notRegisteredStudentEmails, ok := controller.unregisterStudentsFromTeacher(context, "email",
+		unregisterStudentsFromTeacherRequest.TeacherEmail, unregisterStudentsFromTeacherRequest.StudentEmails)
+	if !ok {
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.UnregisterStudentsFromTeacherResponse{
+		UnregisteredStudentEmails:  unregisteredStudentEmails,
+		NotRegisteredStudentEmails: notRegisteredStudentEmails,
+	})
+}
+
+func (controller *Controller) SuspendStudentV2(context *gin.Context) {
+	studentSuspension := &types.StudentSuspensionV2Request{}
+
+	if contextErr := helpers.BindSuspendStudentV2Request(context, studentSuspension); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if !controller.suspendStudent(context, "email", studentSuspension.StudentEmail, studentSuspension.SuspendedBy, studentSuspension.Reason, studentSuspension.Until) {
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func (controller *Controller) UnsuspendStudentV2(context *gin.Context) {
+	studentUnsuspension := &types.StudentUnsuspensionV2Request{}
+
+	if contextErr := helpers.BindUnsuspendStudentV2Request(context, studentUnsuspension); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if !controller.unsuspendStudent(context, "email", studentUnsuspension.StudentEmail) {
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+// SendNotificationV2 stores a notification and responds with its id, which pages its further recipients
+func (controller *Controller) SendNotificationV2(context *gin.Context) {
+	sendNotificationRequest := &types.SendNotificationV2Request{}
+
+	if contextErr := helpers.BindSendNotificationV2Request(context, sendNotificationRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	notificationId, recipientEmails, nextCursor, ok := controller.sendNotification(context, "email", sendNotificationRequest.TeacherEmail,
+		sendNotificationRequest.NotificationMessage, &sendNotificationRequest.Limit)
+	if !ok {
+		return
+	}
+
+	context.JSON(http.StatusCreated, &types.RetrieveNotificationRecipientsResponse{
+		NotificationId: notificationId,
+		StudentEmails:  recipientEmails,
+		NextCursor:     nextCursor,
+	})
+}
+
+// RetrieveNotificationRecipientsV2 pages the recipients of a notification from the first one, or from a next_cursor
+// returned for the same notification
+func (controller *Controller) RetrieveNotificationRecipientsV2(context *gin.Context) {
+	retrieveNotificationRecipientsRequest := &types.RetrieveNotificationRecipientsV2Request{}
+
+	if contextErr := helpers.BindRetrieveNotificationRecipientsV2Request(context, retrieveNotificationRecipientsRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	notificationId := retrieveNotificationRecipientsRequest.NotificationId
+	cursor, validationErr := helpers.ValidateCursorPagination(&retrieveNotificationRecipientsRequest.Limit, retrieveNotificationRecipientsRequest.Cursor)
+	if validationErr == nil && retrieveNotificationRecipientsRequest.Cursor != "" && cursor.NotificationId != notificationId {
+		validationErr = problems.Newf(problems.INVALID_CURSOR, "", retrieveNotificationRecipientsRequest.Cursor).WithField("cursor")
+	}
+	if validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	recipientEmails, nextCursor, ok := controller.retrieveNotificationRecipients(context, notificationId, cursor.After, retrieveNotificationRecipientsRequest.Limit)
+	if !ok {
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveNotificationRecipientsResponse{
+		NotificationId: notificationId,
+		StudentEmails:  recipientEmails,
+		NextCursor:     nextCursor,
+	})
+}
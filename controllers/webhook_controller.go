@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"net/http"
+)
+
+func (controller *Controller) CreateWebhookSubscription(context *gin.Context) {
+	createWebhookSubscriptionRequest := &types.CreateWebhookSubscriptionRequest{}
+
+	if contextErr := helpers.BindCreateWebhookSubscriptionRequest(context, createWebhookSubscriptionRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if validationErr := problems.Collect(
+		helpers.ValidateWebhookUrl(createWebhookSubscriptionRequest.Url),
+		helpers.ValidateWebhookEventTypes(createWebhookSubscriptionRequest.EventTypes),
+	); validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	subscription, err := controller.transactionManager.CreateWebhookSubscription(createWebhookSubscriptionRequest.Url, createWebhookSubscriptionRequest.EventTypes,
+		createWebhookSubscriptionRequest.Secret, controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, generateWebhookSubscriptionResponse(subscription))
+}
+
+func (controller *Controller) RetrieveWebhookSubscriptions(context *gin.Context) {
+	subscriptions, err := controller.transactionManager.RetrieveWebhookSubscriptions(controller.connection)
+	if err != nil {
+		generateInternalServerErrorResponse(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveWebhookSubscriptionsResponse{
+		Subscriptions: helpers.Map(subscriptions, generateWebhookSubscriptionResponse),
+	})
+}
+
+func (controller *Controller) DeleteWebhookSubscription(context *gin.Context) {
+	deleteWebhookSubscriptionRequest := &types.DeleteWebhookSubscriptionRequest{}
+
+	if contextErr := helpers.BindDeleteWebhookSubscriptionRequest(context, deleteWebhookSubscriptionRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	if userError, dbError := controller.transactionManager.DeleteWebhookSubscription(deleteWebhookSubscriptionRequest.SubscriptionId, controller.connection); dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
+
+func (controller *Controller) RetrieveWebhookDeliveries(context *gin.Context) {
+	retrieveWebhookDeliveriesRequest := &types.RetrieveWebhookDeliveriesRequest{}
+
+	if contextErr := helpers.BindRetrieveWebhookDeliveriesRequest(context, retrieveWebhookDeliveriesRequest); contextErr != nil {
+		generateBadRequestErrorResponse(context, contextErr)
+		return
+	}
+
+	cursor, validationErr := helpers.ValidateIdCursorPagination(&retrieveWebhookDeliveriesRequest.Limit, retrieveWebhookDeliveriesRequest.Cursor)
+	if validationErr != nil {
+		generateBadRequestErrorResponse(context, validationErr)
+		return
+	}
+
+	deliveries, hasMore, total, userError, dbError := controller.transactionManager.RetrieveWebhookDeliveries(retrieveWebhookDeliveriesRequest.SubscriptionId,
+		cursor.BeforeId, retrieveWebhookDeliveriesRequest.Limit, controller.connection)
+	if dbError != nil {
+		generateInternalServerErrorResponse(context, dbError)
+		return
+	} else if userError != nil {
+		generateBadRequestErrorResponse(context, userError)
+		return
+	}
+
+	context.JSON(http.StatusOK, &types.RetrieveWebhookDeliveriesResponse{
+		SubscriptionId: retrieveWebhookDeliveriesRequest.SubscriptionId,
+		Deliveries: helpers.Map(deliveries, func(delivery *models.WebhookDelivery) *types.WebhookDeliveryResponse {
+			response := &types.WebhookDeliveryResponse{
+				DeliveryId:     delivery.ID,
+				EventType:      delivery.EventType,
+				Status:         delivery.Status,
+				Attempts:       delivery.Attempts,
+				ResponseStatus: delivery.ResponseStatus,
+				LastError:      delivery.LastError,
+				CreatedAt:      delivery.CreatedAt,
+				DeliveredAt:    delivery.DeliveredAt,
+			}
+			// only pending deliveries have a next attempt
+			if delivery.Status == models.DELIVERY_PENDING {
+				nextAttemptAt := delivery.NextAttemptAt
+				response.NextAttemptAt = &nextAttemptAt
+			}
+			return response
+		}),
+		Total: total,
+		NextCursor: helpers.NextIdCursor(deliveries, hasMore, func(delivery *models.WebhookDelivery) uint {
+			return delivery.ID
+		}),
+	})
+}
+
+// generateWebhookSubscriptionResponse leaves out the secret, which is never sent back once it is set
+func generateWebhookSubscriptionResponse(subscription *models.WebhookSubscription) *types.WebhookSubscriptionResponse {
+	return &types.WebhookSubscriptionResponse{
+		SubscriptionId: subscription.ID,
+		Url:            subscription.Url,
+		EventTypes:     subscription.Events(),
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
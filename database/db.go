@@ -2,25 +2,47 @@ package database
 
 import (
 	"fmt"
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"net"
+	"net/url"
+	"strings"
+	"time"
 )
 
-// Change accordingly
+// Supported database drivers
 
-const DB_USERNAME = "root"
-const DB_PASSWORD = ""
-const DB_NAME = "production_lms_db"
-const DB_HOST = "localhost"
-const DB_PORT = "3306"
+const MYSQL = "mysql"
+const POSTGRES = "postgres"
+const SQLITE = "sqlite"
 
+// Postgres ssl modes, as understood by libpq
+
+const SSL_MODE_DISABLE = "disable"
+const SSL_MODE_ALLOW = "allow"
+const SSL_MODE_PREFER = "prefer"
+const SSL_MODE_REQUIRE = "require"
+const SSL_MODE_VERIFY_CA = "verify-ca"
+const SSL_MODE_VERIFY_FULL = "verify-full"
+
+// SQLITE_IN_MEMORY can be used as the Name of sqlite credentials to keep the database in memory
+const SQLITE_IN_MEMORY = ":memory:"
+
+// Credentials describe how to reach the database. For sqlite only Name is used, as the path of the database
+// file or SQLITE_IN_MEMORY. An empty Driver defaults to mysql, and an empty Port to the default port of the driver.
+// SslMode is the sslmode of postgres connections, left to the libpq default when empty.
 type Credentials struct {
+	Driver   string
 	Username string
 	Password string
 	Name     string
 	Host     string
 	Port     string
+	SslMode  string
 	Debug    bool
 }
 
@@ -28,42 +50,102 @@ type Connection struct {
 	db *gorm.DB
 }
 
-func InitDefaultConnection() *Connection {
-	return NewConnection(&Credentials{
-		Username: DB_USERNAME,
-		Password: DB_PASSWORD,
-		Name:     DB_NAME,
-		Host:     DB_HOST,
-		Port:     DB_PORT,
-	})
-}
-
 func NewConnection(credentials *Credentials) *Connection {
 	return &Connection{db: connectDB(credentials)}
 }
 
+// WrapDb wraps an open database or a transaction so that it can be passed wherever a connection is expected
+func WrapDb(db *gorm.DB) *Connection {
+	return &Connection{db: db}
+}
+
 func (connection *Connection) GetDb() *gorm.DB {
 	return connection.db
 }
 
 func connectDB(credentials *Credentials) *gorm.DB {
-	var err error
-	dsn := credentials.Username + ":" + credentials.Password + "@tcp" + "(" + credentials.Host + ":" + credentials.Port + ")/" +
-		credentials.Name + "?" + "parseTime=true&loc=Local"
+	dialector, err := newDialector(credentials)
+	if err != nil {
+		fmt.Printf("Error connecting to database : error=%v\n", err)
+		return nil
+	}
 
-	var db *gorm.DB
+	config := &gorm.Config{}
 	if credentials.Debug {
-		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Info),
-		})
-	} else {
-		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+		config.Logger = logger.Default.LogMode(logger.Info)
 	}
 
+	db, err := gorm.Open(dialector, config)
 	if err != nil {
-		fmt.Println("Error connecting to database : error=%v", err)
+		fmt.Printf("Error connecting to database : error=%v\n", err)
 		return nil
 	}
 
+	if credentials.Driver == SQLITE && credentials.Name == SQLITE_IN_MEMORY {
+		// every new connection to :memory: opens a separate empty database, so keep a single one
+		sqlDb, err := db.DB()
+		if err != nil {
+			fmt.Printf("Error connecting to database : error=%v\n", err)
+			return nil
+		}
+		sqlDb.SetMaxOpenConns(1)
+	}
+
 	return db
 }
+
+// DefaultPort returns the port the database server of the driver listens on by default
+func DefaultPort(driver string) string {
+	if driver == POSTGRES {
+		return "5432"
+	}
+	return "3306"
+}
+
+func newDialector(credentials *Credentials) (gorm.Dialector, error) {
+	port := credentials.Port
+	if port == "" {
+		port = DefaultPort(credentials.Driver)
+	}
+	address := net.JoinHostPort(credentials.Host, port)
+
+	switch credentials.Driver {
+	case MYSQL, "":
+		config := mysqlDriver.NewConfig()
+		config.User = credentials.Username
+		config.Passwd = credentials.Password
+		config.Net = "tcp"
+		config.Addr = address
+		config.DBName = credentials.Name
+		config.ParseTime = true
+		config.Loc = time.Local
+		return mysql.Open(config.FormatDSN()), nil
+	case POSTGRES:
+		dsn := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(credentials.Username, credentials.Password),
+			Host:   address,
+			Path:   credentials.Name,
+		}
+		if credentials.SslMode != "" {
+			dsn.RawQuery = url.Values{"sslmode": {credentials.SslMode}}.Encode()
+		}
+		return postgres.Open(dsn.String()), nil
+	case SQLITE:
+		return sqlite.Open(sqliteDsn(credentials.Name)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %s", credentials.Driver)
+	}
+}
+
+// sqliteDsn turns foreign keys on, as they are off by default in sqlite, unless the name already sets them. The name may
+// already hold query parameters, such as file:lms.db?cache=shared.
+func sqliteDsn(name string) string {
+	if strings.Contains(name, "_foreign_keys=") || strings.Contains(name, "_fk=") {
+		return name
+	}
+	if strings.Contains(name, "?") {
+		return name + "&_foreign_keys=on"
+	}
+	return name + "?_foreign_keys=on"
+}
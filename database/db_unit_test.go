@@ -0,0 +1,47 @@
+package database
+
+import (
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"strings"
+	"testing"
+)
+
+func TestSqliteDsn(t *testing.T) {
+	for name, expected := range map[string]string{
+		SQLITE_IN_MEMORY:                  ":memory:?_foreign_keys=on",
+		"lms.db":                          "lms.db?_foreign_keys=on",
+		"file:lms.db?cache=shared":        "file:lms.db?cache=shared&_foreign_keys=on",
+		"file:lms.db?_foreign_keys=off":   "file:lms.db?_foreign_keys=off",
+		"file:lms.db?cache=shared&_fk=on": "file:lms.db?cache=shared&_fk=on",
+	} {
+		if dsn := sqliteDsn(name); dsn != expected {
+			t.Errorf("the dsn of %s should be %s but is %s", name, expected, dsn)
+		}
+	}
+}
+
+func TestNewDialectorDefaultsPortsAndSslMode(t *testing.T) {
+	for credentials, expected := range map[Credentials]string{
+		{Driver: POSTGRES, Username: "lms", Password: "secret", Host: "db", Name: "lms"}:                                          "postgres://lms:secret@db:5432/lms",
+		{Driver: POSTGRES, Username: "lms", Password: "secret", Host: "db", Port: "6432", Name: "lms", SslMode: SSL_MODE_REQUIRE}: "postgres://lms:secret@db:6432/lms?sslmode=require",
+		{Driver: MYSQL, Username: "lms", Password: "secret", Host: "db", Name: "lms"}:                                             "lms:secret@tcp(db:3306)/lms?",
+	} {
+		credentials := credentials
+		dialector, err := newDialector(&credentials)
+		if err != nil {
+			t.Fatalf("the dialector of %+v should be created: %v", credentials, err)
+		}
+
+		var dsn string
+		switch dialector := dialector.(type) {
+		case *postgres.Dialector:
+			dsn = dialector.Config.DSN
+		case *mysql.Dialector:
+			dsn = dialector.Config.DSN
+		}
+		if !strings.HasPrefix(dsn, expected) {
+			t.Errorf("the dsn of %+v should start with %s but is %s", credentials, expected, dsn)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"learning-management-system/models"
+	"learning-management-system/problems"
+)
+
+func ValidateMaxScore(maxScore int) error {
+	return ValidatePositive("max_score", float64(maxScore))
+}
+
+// ValidateSubmission requires a text or a file reference, an empty submission would not be told apart from none
+func ValidateSubmission(text string, fileReference string) error {
+	if text == "" && fileReference == "" {
+		return problems.Newf(problems.MISSING_FIELD, "submission").WithField("text")
+	}
+	return nil
+}
+
+// ValidateWorkStatus accepts one of the work statuses, or an empty status that does not filter the work
+func ValidateWorkStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+
+	if len(Filter(models.WorkStatuses, func(known string) bool { return known == status })) == 0 {
+		return problems.Newf(problems.INVALID_STATUS, "", status, models.WorkStatuses).WithField("status")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"time"
+)
+
+// ValidateDate accepts a date formatted as YYYY-MM-DD, or an empty date that leaves its end of a range open
+func ValidateDate(field string, date string) error {
+	if date == "" {
+		return nil
+	}
+
+	if _, err := time.Parse(models.ATTENDANCE_DATE_LAYOUT, date); err != nil {
+		return problems.Newf(problems.INVALID_FIELD, "date", field).WithField(field)
+	}
+	return nil
+}
+
+// ValidateAttendanceStatuses requires the status recorded for every student to be one of the attendance statuses
+func ValidateAttendanceStatuses(statuses map[string]string) error {
+	var errs []error
+	for _, studentEmail := range SortedKeys(statuses) {
+		status := statuses[studentEmail]
+		if len(Filter(models.AttendanceStatuses, func(known string) bool { return known == status })) == 0 {
+			errs = append(errs, problems.Newf(problems.INVALID_STATUS, "", status, models.AttendanceStatuses).
+				WithField("students").WithEmails(studentEmail))
+		}
+	}
+	return problems.Collect(errs...)
+}
+
+// ValidateAttendanceThreshold accepts a percentage, or no threshold at all
+func ValidateAttendanceThreshold(threshold *float64) error {
+	if threshold != nil && (*threshold < 0 || *threshold > 100) {
+		return problems.Newf(problems.INVALID_FIELD, "percentage", "below").WithField("below")
+	}
+	return nil
+}
+
+// AttendanceRate returns the percentage of the days a student attended, on time or late, out of the days they were
+// expected to. Excused absences are left out, and there is no rate without any other attendance.
+func AttendanceRate(present int, late int, absent int) *float64 {
+	return WeightedAverage([]float64{100, 0}, []float64{float64(present + late), float64(absent)})
+}
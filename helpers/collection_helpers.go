@@ -1,5 +1,7 @@
 package helpers
 
+import "sort"
+
 func Map[T, V any](slice []T, function func(T) V) []V {
 	result := make([]V, len(slice))
 	for i, t := range slice {
@@ -52,3 +54,13 @@ func RemoveAllStringsInSlice(slice []string, stringsToRemove []string) []string
 
 	return result
 }
+
+// SortedKeys returns the keys of the map in ascending order, to range over it deterministically
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"learning-management-system/problems"
+	"strconv"
+	"strings"
+)
+
+const MODE_ALL = "all"
+const MODE_ANY = "any"
+const MODE_ONLY = "only"
+const MODE_AT_LEAST = "atleast"
+
+var CommonStudentsModes = []string{MODE_ALL, MODE_ANY, MODE_ONLY, MODE_AT_LEAST + "=k"}
+
+// CommonStudentsMode selects which students of the given teachers are listed by the commonstudents endpoint
+type CommonStudentsMode struct {
+	Name string
+	// AtLeast is the number of the teachers a student must be registered to in the atleast mode
+	AtLeast int
+}
+
+// ParseCommonStudentsMode parses the mode query parameter for the given number of distinct teachers, an empty mode
+// is the all mode
+func ParseCommonStudentsMode(mode string, teacherCount int) (*CommonStudentsMode, error) {
+	switch mode {
+	case "", MODE_ALL:
+		return &CommonStudentsMode{Name: MODE_ALL}, nil
+	case MODE_ANY, MODE_ONLY:
+		return &CommonStudentsMode{Name: mode}, nil
+	}
+
+	if strings.HasPrefix(mode, MODE_AT_LEAST+"=") {
+		atLeast, err := strconv.Atoi(strings.TrimPrefix(mode, MODE_AT_LEAST+"="))
+		if err != nil || atLeast < 1 || atLeast > teacherCount {
+			return nil, problems.Newf(problems.INVALID_MODE, "atleast", mode, teacherCount).WithField("mode")
+		}
+		return &CommonStudentsMode{Name: MODE_AT_LEAST, AtLeast: atLeast}, nil
+	}
+
+	return nil, problems.Newf(problems.INVALID_MODE, "", mode, CommonStudentsModes).WithField("mode")
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"learning-management-system/problems"
 	"learning-management-system/types"
 	"reflect"
 )
@@ -13,10 +15,27 @@ func BindRegisterStudentsToTeacherRequest(context *gin.Context, registerStudents
 	return bindJsonBodyRequests(context, registerStudentsToTeacherRequest)
 }
 
+func BindUnregisterStudentsFromTeacherRequest(context *gin.Context, unregisterStudentsFromTeacherRequest *types.UnregisterStudentsFromTeacherRequest) error {
+	return bindJsonBodyRequests(context, unregisterStudentsFromTeacherRequest)
+}
+
 func BindSuspendStudentRequest(context *gin.Context, studentSuspensionRequest *types.StudentSuspensionRequest) error {
 	return bindJsonBodyRequests(context, studentSuspensionRequest)
 }
 
+func BindUnsuspendStudentRequest(context *gin.Context, studentUnsuspensionRequest *types.StudentUnsuspensionRequest) error {
+	return bindJsonBodyRequests(context, studentUnsuspensionRequest)
+}
+
+func BindRetrieveStudentSuspensionsRequest(context *gin.Context, retrieveStudentSuspensionsRequest *types.RetrieveStudentSuspensionsRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveStudentSuspensionsRequest); ginErr != nil {
+		return validateGinBindings(retrieveStudentSuspensionsRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
 func BindRetrieveCommonStudentsRequest(context *gin.Context, retrieveCommonStudentsRequest *types.RetrieveCommonStudentsRequest) error {
 
 	if ginErr := context.ShouldBindQuery(retrieveCommonStudentsRequest); ginErr != nil {
@@ -27,7 +46,266 @@ func BindRetrieveCommonStudentsRequest(context *gin.Context, retrieveCommonStude
 }
 
 func BindRetrieveStudentRecipientsRequest(context *gin.Context, retrieveStudentRecipientsRequest *types.RetrieveStudentRecipientsRequest) error {
-	return bindJsonBodyRequests(context, retrieveStudentRecipientsRequest)
+
+	if err := bindJsonBodyRequests(context, retrieveStudentRecipientsRequest); err != nil {
+		return err
+	}
+
+	// the body is bound first so that its required fields are already supplied when the query is validated
+	if ginErr := context.ShouldBindQuery(retrieveStudentRecipientsRequest); ginErr != nil {
+		return validateGinBindings(retrieveStudentRecipientsRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveMoreStudentRecipientsRequest(context *gin.Context, retrieveMoreStudentRecipientsRequest *types.RetrieveMoreStudentRecipientsRequest) error {
+
+	if ginErr := context.ShouldBindQuery(retrieveMoreStudentRecipientsRequest); ginErr != nil {
+		return validateGinBindings(retrieveMoreStudentRecipientsRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveStudentNotificationsRequest(context *gin.Context, retrieveStudentNotificationsRequest *types.RetrieveStudentNotificationsRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveStudentNotificationsRequest); ginErr != nil {
+		return validateGinBindings(retrieveStudentNotificationsRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveStudentNotificationsRequest); ginErr != nil {
+		return validateGinBindings(retrieveStudentNotificationsRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindMarkNotificationAsReadRequest(context *gin.Context, markNotificationAsReadRequest *types.MarkNotificationAsReadRequest) error {
+
+	if ginErr := context.ShouldBindUri(markNotificationAsReadRequest); ginErr != nil {
+		return validateGinBindings(markNotificationAsReadRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveNotificationDeliveriesRequest(context *gin.Context, retrieveNotificationDeliveriesRequest *types.RetrieveNotificationDeliveriesRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveNotificationDeliveriesRequest); ginErr != nil {
+		return validateGinBindings(retrieveNotificationDeliveriesRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindCreateWebhookSubscriptionRequest(context *gin.Context, createWebhookSubscriptionRequest *types.CreateWebhookSubscriptionRequest) error {
+	return bindJsonBodyRequests(context, createWebhookSubscriptionRequest)
+}
+
+func BindDeleteWebhookSubscriptionRequest(context *gin.Context, deleteWebhookSubscriptionRequest *types.DeleteWebhookSubscriptionRequest) error {
+
+	if ginErr := context.ShouldBindUri(deleteWebhookSubscriptionRequest); ginErr != nil {
+		return validateGinBindings(deleteWebhookSubscriptionRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveWebhookDeliveriesRequest(context *gin.Context, retrieveWebhookDeliveriesRequest *types.RetrieveWebhookDeliveriesRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveWebhookDeliveriesRequest); ginErr != nil {
+		return validateGinBindings(retrieveWebhookDeliveriesRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveWebhookDeliveriesRequest); ginErr != nil {
+		return validateGinBindings(retrieveWebhookDeliveriesRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindImportRosterRequest(context *gin.Context, importRosterRequest *types.ImportRosterRequest) error {
+
+	if contentTypeErr := validateContentTypeIsMultipartFormData(context); contentTypeErr != nil {
+		return contentTypeErr
+	}
+
+	if ginErr := context.ShouldBindWith(importRosterRequest, binding.FormMultipart); ginErr != nil {
+		return validateGinBindings(importRosterRequest, "form", ginErr)
+	}
+
+	// dry_run may also be passed in the query string, the file is already bound when the query is validated
+	if ginErr := context.ShouldBindQuery(importRosterRequest); ginErr != nil {
+		return validateGinBindings(importRosterRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindExportRosterRequest(context *gin.Context, exportRosterRequest *types.ExportRosterRequest) error {
+
+	if ginErr := context.ShouldBindQuery(exportRosterRequest); ginErr != nil {
+		return validateGinBindings(exportRosterRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindSaveCourseRequest(context *gin.Context, saveCourseRequest *types.SaveCourseRequest) error {
+	return bindJsonBodyAndUriRequests(context, saveCourseRequest)
+}
+
+func BindRetrieveCourseRequest(context *gin.Context, retrieveCourseRequest *types.RetrieveCourseRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveCourseRequest); ginErr != nil {
+		return validateGinBindings(retrieveCourseRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindSaveSectionRequest(context *gin.Context, saveSectionRequest *types.SaveSectionRequest) error {
+	return bindJsonBodyAndUriRequests(context, saveSectionRequest)
+}
+
+func BindRetrieveSectionRequest(context *gin.Context, retrieveSectionRequest *types.RetrieveSectionRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveSectionRequest); ginErr != nil {
+		return validateGinBindings(retrieveSectionRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindEnrollStudentsRequest(context *gin.Context, enrollStudentsRequest *types.EnrollStudentsRequest) error {
+	return bindJsonBodyAndUriRequests(context, enrollStudentsRequest)
+}
+
+func BindCreateAssignmentRequest(context *gin.Context, createAssignmentRequest *types.CreateAssignmentRequest) error {
+	return bindJsonBodyAndUriRequests(context, createAssignmentRequest)
+}
+
+func BindRetrieveTeacherAssignmentsRequest(context *gin.Context, retrieveTeacherAssignmentsRequest *types.RetrieveTeacherAssignmentsRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveTeacherAssignmentsRequest); ginErr != nil {
+		return validateGinBindings(retrieveTeacherAssignmentsRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveAssignmentRequest(context *gin.Context, retrieveAssignmentRequest *types.RetrieveAssignmentRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveAssignmentRequest); ginErr != nil {
+		return validateGinBindings(retrieveAssignmentRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindSubmitAssignmentRequest(context *gin.Context, submitAssignmentRequest *types.SubmitAssignmentRequest) error {
+	return bindJsonBodyAndUriRequests(context, submitAssignmentRequest)
+}
+
+func BindRetrieveAssignmentSubmissionsRequest(context *gin.Context, retrieveAssignmentSubmissionsRequest *types.RetrieveAssignmentSubmissionsRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveAssignmentSubmissionsRequest); ginErr != nil {
+		return validateGinBindings(retrieveAssignmentSubmissionsRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveAssignmentSubmissionsRequest); ginErr != nil {
+		return validateGinBindings(retrieveAssignmentSubmissionsRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveTeacherSubmissionsRequest(context *gin.Context, retrieveTeacherSubmissionsRequest *types.RetrieveTeacherSubmissionsRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveTeacherSubmissionsRequest); ginErr != nil {
+		return validateGinBindings(retrieveTeacherSubmissionsRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveTeacherSubmissionsRequest); ginErr != nil {
+		return validateGinBindings(retrieveTeacherSubmissionsRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindSaveGradeCategoryRequest(context *gin.Context, saveGradeCategoryRequest *types.SaveGradeCategoryRequest) error {
+	return bindJsonBodyAndUriRequests(context, saveGradeCategoryRequest)
+}
+
+func BindRetrieveGradeCategoriesRequest(context *gin.Context, retrieveGradeCategoriesRequest *types.RetrieveGradeCategoriesRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveGradeCategoriesRequest); ginErr != nil {
+		return validateGinBindings(retrieveGradeCategoriesRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindGradeStudentRequest(context *gin.Context, gradeStudentRequest *types.GradeStudentRequest) error {
+	return bindJsonBodyAndUriRequests(context, gradeStudentRequest)
+}
+
+func BindRetrieveGradebookRequest(context *gin.Context, retrieveGradebookRequest *types.RetrieveGradebookRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveGradebookRequest); ginErr != nil {
+		return validateGinBindings(retrieveGradebookRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveReportCardRequest(context *gin.Context, retrieveReportCardRequest *types.RetrieveReportCardRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveReportCardRequest); ginErr != nil {
+		return validateGinBindings(retrieveReportCardRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRecordRollCallRequest(context *gin.Context, recordRollCallRequest *types.RecordRollCallRequest) error {
+	return bindJsonBodyAndUriRequests(context, recordRollCallRequest)
+}
+
+func BindRetrieveRollCallRequest(context *gin.Context, retrieveRollCallRequest *types.RetrieveRollCallRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveRollCallRequest); ginErr != nil {
+		return validateGinBindings(retrieveRollCallRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveStudentAttendanceRequest(context *gin.Context, retrieveStudentAttendanceRequest *types.RetrieveStudentAttendanceRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveStudentAttendanceRequest); ginErr != nil {
+		return validateGinBindings(retrieveStudentAttendanceRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveStudentAttendanceRequest); ginErr != nil {
+		return validateGinBindings(retrieveStudentAttendanceRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveAttendanceRatesRequest(context *gin.Context, retrieveAttendanceRatesRequest *types.RetrieveAttendanceRatesRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveAttendanceRatesRequest); ginErr != nil {
+		return validateGinBindings(retrieveAttendanceRatesRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveAttendanceRatesRequest); ginErr != nil {
+		return validateGinBindings(retrieveAttendanceRatesRequest, "form", ginErr)
+	}
+
+	return nil
 }
 
 func BindPopulateStudentsRequest(context *gin.Context, populateStudentsRequest *types.PopulateStudentsRequest) error {
@@ -38,21 +316,106 @@ func BindPopulateTeachersRequest(context *gin.Context, populateTeachersRequest *
 	return bindJsonBodyRequests(context, populateTeachersRequest)
 }
 
+func BindRetrieveSwaggerUiAssetRequest(context *gin.Context, retrieveSwaggerUiAssetRequest *types.RetrieveSwaggerUiAssetRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveSwaggerUiAssetRequest); ginErr != nil {
+		return validateGinBindings(retrieveSwaggerUiAssetRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveProfileRequest(context *gin.Context, retrieveProfileRequest *types.RetrieveProfileRequest) error {
+
+	if ginErr := context.ShouldBindUri(retrieveProfileRequest); ginErr != nil {
+		return validateGinBindings(retrieveProfileRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindUpdateProfileRequest(context *gin.Context, updateProfileRequest *types.UpdateProfileRequest) error {
+	return bindJsonBodyAndUriRequests(context, updateProfileRequest)
+}
+
+func BindDeleteProfileRequest(context *gin.Context, deleteProfileRequest *types.DeleteProfileRequest) error {
+
+	if ginErr := context.ShouldBindUri(deleteProfileRequest); ginErr != nil {
+		return validateGinBindings(deleteProfileRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindRegisterStudentsToTeacherV2Request(context *gin.Context, registerStudentsToTeacherRequest *types.RegisterStudentsToTeacherV2Request) error {
+	return bindJsonBodyAndUriRequests(context, registerStudentsToTeacherRequest)
+}
+
+func BindUnregisterStudentsFromTeacherV2Request(context *gin.Context, unregisterStudentsFromTeacherRequest *types.UnregisterStudentsFromTeacherV2Request) error {
+	return bindJsonBodyAndUriRequests(context, unregisterStudentsFromTeacherRequest)
+}
+
+func BindSuspendStudentV2Request(context *gin.Context, studentSuspensionRequest *types.StudentSuspensionV2Request) error {
+	return bindJsonBodyAndUriRequests(context, studentSuspensionRequest)
+}
+
+func BindUnsuspendStudentV2Request(context *gin.Context, studentUnsuspensionRequest *types.StudentUnsuspensionV2Request) error {
+
+	if ginErr := context.ShouldBindUri(studentUnsuspensionRequest); ginErr != nil {
+		return validateGinBindings(studentUnsuspensionRequest, "uri", ginErr)
+	}
+
+	return nil
+}
+
+func BindSendNotificationV2Request(context *gin.Context, sendNotificationRequest *types.SendNotificationV2Request) error {
+
+	if err := bindJsonBodyAndUriRequests(context, sendNotificationRequest); err != nil {
+		return err
+	}
+
+	if ginErr := context.ShouldBindQuery(sendNotificationRequest); ginErr != nil {
+		return validateGinBindings(sendNotificationRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
+func BindRetrieveNotificationRecipientsV2Request(context *gin.Context, retrieveNotificationRecipientsRequest *types.RetrieveNotificationRecipientsV2Request) error {
+
+	if ginErr := context.ShouldBindUri(retrieveNotificationRecipientsRequest); ginErr != nil {
+		return validateGinBindings(retrieveNotificationRecipientsRequest, "uri", ginErr)
+	}
+
+	if ginErr := context.ShouldBindQuery(retrieveNotificationRecipientsRequest); ginErr != nil {
+		return validateGinBindings(retrieveNotificationRecipientsRequest, "form", ginErr)
+	}
+
+	return nil
+}
+
 func validateContentTypeIsApplicationJson(context *gin.Context) error {
 	headerContentType := context.GetHeader("Content-Type")
 
 	if headerContentType == "" {
-		return fmt.Errorf("Content-Type header of application/json must be provided")
+		return problems.Newf(problems.INVALID_CONTENT_TYPE, "missing", "application/json")
 	}
 
 	if headerContentType != "application/json" {
-		return fmt.Errorf("Content-Type header must be application/json")
+		return problems.Newf(problems.INVALID_CONTENT_TYPE, "", "application/json")
+	}
+	return nil
+}
+
+func validateContentTypeIsMultipartFormData(context *gin.Context) error {
+	if context.ContentType() != "multipart/form-data" {
+		return problems.Newf(problems.INVALID_CONTENT_TYPE, "", "multipart/form-data")
 	}
 	return nil
 }
 
 func validateGinBindings[T any](requestStruct *T, binding string, errs ...error) error {
-	var out []string
+	var out problems.List
 	for _, err := range errs {
 		switch typedError := any(err).(type) {
 		// We parse the following possible errors into more readable formats for users
@@ -63,32 +426,32 @@ func validateGinBindings[T any](requestStruct *T, binding string, errs ...error)
 		case *json.UnmarshalTypeError:
 			out = append(out, parseMarshallingError(*typedError))
 		default:
-			out = append(out, err.Error())
+			out = append(out, problems.New(problems.BAD_REQUEST, err.Error()))
 		}
 	}
 
-	// We only return the first error for simplification
+	// Every error is returned so that clients can point out all the faulty fields at once
 	if len(out) > 0 {
-		return fmt.Errorf(out[0])
+		return out
 	}
 
 	return nil
 }
 
-func parseFieldError[T any](fieldError validator.FieldError, requestStruct *T, binding string) string {
+func parseFieldError[T any](fieldError validator.FieldError, requestStruct *T, binding string) *problems.Problem {
 	field, _ := reflect.TypeOf(requestStruct).Elem().FieldByName(fieldError.Field())
 	fieldTagName, _ := field.Tag.Lookup(binding)
 
 	switch fieldError.Tag() {
 	case "required":
-		return fmt.Sprintf("The required field %s is not supplied", fieldTagName)
+		return problems.Newf(problems.MISSING_FIELD, "", fieldTagName).WithField(fieldTagName)
 	default:
-		return fmt.Errorf("%v", fieldError).Error()
+		return problems.New(problems.INVALID_FIELD, fmt.Errorf("%v", fieldError).Error()).WithField(fieldTagName)
 	}
 }
 
-func parseMarshallingError(e json.UnmarshalTypeError) string {
-	return fmt.Sprintf("The field %s must be a %s", e.Field, e.Type.String())
+func parseMarshallingError(e json.UnmarshalTypeError) *problems.Problem {
+	return problems.Newf(problems.INVALID_FIELD, "", e.Field, e.Type.String()).WithField(e.Field)
 }
 
 func bindJsonBodyRequests[T any](context *gin.Context, requestStruct *T) error {
@@ -103,3 +466,17 @@ func bindJsonBodyRequests[T any](context *gin.Context, requestStruct *T) error {
 
 	return nil
 }
+
+// bindJsonBodyAndUriRequests binds the body first so that its required fields are already supplied when the uri is validated
+func bindJsonBodyAndUriRequests[T any](context *gin.Context, requestStruct *T) error {
+
+	if err := bindJsonBodyRequests(context, requestStruct); err != nil {
+		return err
+	}
+
+	if ginErr := context.ShouldBindUri(requestStruct); ginErr != nil {
+		return validateGinBindings(requestStruct, "uri", ginErr)
+	}
+
+	return nil
+}
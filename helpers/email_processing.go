@@ -1,30 +1,39 @@
 package helpers
 
 import (
-	"fmt"
+	"learning-management-system/problems"
 	"net/mail"
 	"regexp"
+	"strings"
 )
 
 // ValidateEmailFormat custom email validation handler
 func ValidateEmailFormat(email string) error {
-	_, err := mail.ParseAddress(email)
-
-	if err != nil {
-		return fmt.Errorf(`The email address %s has an invalid format`, email)
-	}
-
-	return nil
+	return ValidateEmailField("", email)
 }
 
 func ValidateEmailAddresses(emails []string) error {
-	for _, email := range emails {
-		err := ValidateEmailFormat(email)
-		if err != nil {
-			return err
-		}
+	return ValidateEmailField("", emails...)
+}
+
+// ValidateEmailField reports every email of a request field that has an invalid format in a single problem
+func ValidateEmailField(field string, emails ...string) error {
+	invalidEmails := Filter(emails, func(email string) bool {
+		_, err := mail.ParseAddress(email)
+		return err != nil
+	})
+
+	switch len(invalidEmails) {
+	case 0:
+		return nil
+	case 1:
+		return problems.Newf(problems.INVALID_EMAIL, "", invalidEmails[0]).
+			WithField(field).WithEmails(invalidEmails...)
 	}
-	return nil
+
+	// v1 stopped at the first invalid email
+	return problems.Newf(problems.INVALID_EMAIL, "plural", strings.Join(invalidEmails, ", ")).
+		WithField(field).WithEmails(invalidEmails...).WithLegacy(problems.Newf(problems.INVALID_EMAIL, "", invalidEmails[0]))
 }
 
 func FindValidEmailsInText(text string) []string {
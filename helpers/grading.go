@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"learning-management-system/problems"
+	"math"
+)
+
+// letterGrades are the letter grades with the lowest percentage that earns them, the highest first
+var letterGrades = []struct {
+	letter  string
+	minimum float64
+}{{"A", 90}, {"B", 80}, {"C", 70}, {"D", 60}, {"F", 0}}
+
+// LetterGrade returns the letter grade earned by the percentage
+func LetterGrade(percentage float64) string {
+	for _, letterGrade := range letterGrades {
+		if percentage >= letterGrade.minimum {
+			return letterGrade.letter
+		}
+	}
+	return letterGrades[len(letterGrades)-1].letter
+}
+
+// WeightedAverage returns the average of the values weighted by the weights at the same index, rounded to two
+// decimals. There is no average, and nil is returned, when the weights add up to zero.
+func WeightedAverage(values []float64, weights []float64) *float64 {
+	var sum, totalWeight float64
+	for i, value := range values {
+		sum += value * weights[i]
+		totalWeight += weights[i]
+	}
+
+	if totalWeight == 0 {
+		return nil
+	}
+	average := math.Round(sum/totalWeight*100) / 100
+	return &average
+}
+
+// ValidatePositive requires the number in the field to be greater than zero
+func ValidatePositive(field string, number float64) error {
+	if number <= 0 {
+		return problems.Newf(problems.INVALID_FIELD, "positive", field).WithField(field)
+	}
+	return nil
+}
+
+// ValidateScore accepts scores from zero, a score above the max score counts as extra credit
+func ValidateScore(score float64) error {
+	if score < 0 {
+		return problems.Newf(problems.INVALID_FIELD, "negative", "score").WithField("score")
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package helpers
 
-import "testing"
+import (
+	"io"
+	"strings"
+	"testing"
+)
 
 type testStructStub struct {
 	name string
@@ -199,3 +203,193 @@ func TestRemoveAllStringsInSlice2(t *testing.T) {
 		t.Errorf("wrong result length")
 	}
 }
+
+func TestValidateWebhookUrl(t *testing.T) {
+
+	if ValidateWebhookUrl("https://hooks.school.com/lms?token=1") != nil {
+		t.Errorf("url should be valid")
+	}
+
+	if ValidateWebhookUrl("ftp://hooks.school.com") == nil || ValidateWebhookUrl("/relative") == nil {
+		t.Errorf("url should be invalid")
+	}
+}
+
+func TestValidateWebhookEventTypes(t *testing.T) {
+
+	if ValidateWebhookEventTypes([]string{"students.registered", "notification.sent"}) != nil {
+		t.Errorf("events should be valid")
+	}
+
+	if ValidateWebhookEventTypes([]string{}) == nil || ValidateWebhookEventTypes([]string{"student.expelled"}) == nil {
+		t.Errorf("events should be invalid")
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	limit := 0
+	cursor, err := ValidateCursorPagination(&limit, EncodeCursor(&Cursor{NotificationId: 3, After: "test@gmail.com"}))
+
+	if err != nil || limit != DEFAULT_PAGE_LIMIT {
+		t.Errorf("cursor should be valid")
+	}
+
+	if cursor.NotificationId != 3 || cursor.After != "test@gmail.com" {
+		t.Errorf("wrong cursor %+v", cursor)
+	}
+
+	nextCursor := NextIdCursor([]uint{9, 7}, true, func(id uint) uint { return id })
+	if cursor, err := ValidateIdCursorPagination(&limit, nextCursor); err != nil || cursor.BeforeId != 7 {
+		t.Errorf("wrong id cursor %+v", cursor)
+	}
+}
+
+func TestInvalidCursor(t *testing.T) {
+	limit := 10
+
+	if _, err := ValidateCursorPagination(&limit, "not a cursor"); err == nil {
+		t.Errorf("cursor should be invalid")
+	}
+
+	if NextCursor([]string{"test@gmail.com"}, false, 0) != "" {
+		t.Errorf("the last page should have no next cursor")
+	}
+
+	// the cursors of lists sorted by email and of lists sorted newest first cannot be swapped
+	if _, err := ValidateIdCursorPagination(&limit, NextCursor([]string{"test@gmail.com"}, true, 0)); err == nil {
+		t.Errorf("an email cursor should not page a list sorted newest first")
+	}
+	if _, err := ValidateCursorPagination(&limit, EncodeCursor(&Cursor{BeforeId: 7})); err == nil {
+		t.Errorf("an id cursor should not page a list sorted by email")
+	}
+}
+
+func TestParseCommonStudentsMode(t *testing.T) {
+	if mode, err := ParseCommonStudentsMode("", 2); err != nil || mode.Name != MODE_ALL {
+		t.Errorf("an empty mode should be the all mode")
+	}
+
+	if mode, err := ParseCommonStudentsMode("atleast=2", 2); err != nil || mode.Name != MODE_AT_LEAST || mode.AtLeast != 2 {
+		t.Errorf("atleast=2 should be valid for 2 teachers")
+	}
+
+	for _, invalid := range []string{"atleast=3", "atleast=0", "atleast=x", "none"} {
+		if _, err := ParseCommonStudentsMode(invalid, 2); err == nil {
+			t.Errorf("mode %s should be invalid", invalid)
+		}
+	}
+}
+
+func TestValidateAssignmentFields(t *testing.T) {
+	if ValidateMaxScore(1) != nil || ValidateMaxScore(0) == nil {
+		t.Errorf("the max score should have to be positive")
+	}
+
+	if ValidateSubmission("answers", "") != nil || ValidateSubmission("", "s3://bucket/file.pdf") != nil || ValidateSubmission("", "") == nil {
+		t.Errorf("a submission should need a text or a file")
+	}
+
+	for _, valid := range []string{"", "pending", "late"} {
+		if err := ValidateWorkStatus(valid); err != nil {
+			t.Errorf("status %s should be valid", valid)
+		}
+	}
+	if err := ValidateWorkStatus("graded"); err == nil {
+		t.Errorf("status graded should be invalid")
+	}
+}
+
+func TestLetterGrade(t *testing.T) {
+	for percentage, letter := range map[float64]string{100: "A", 90: "A", 89.99: "B", 80: "B", 75: "C", 60: "D", 59.5: "F", 0: "F", 110: "A"} {
+		if got := LetterGrade(percentage); got != letter {
+			t.Errorf("%v should earn %s but earns %s", percentage, letter, got)
+		}
+	}
+}
+
+func TestWeightedAverage(t *testing.T) {
+	average := WeightedAverage([]float64{80, 95, 70}, []float64{40, 60, 0})
+	if average == nil || *average != 89 {
+		t.Errorf("the weighted average should be 89 but is %v", average)
+	}
+
+	average = WeightedAverage([]float64{100, 50, 50}, []float64{1, 1, 1})
+	if average == nil || *average != 66.67 {
+		t.Errorf("the average should be rounded to 66.67 but is %v", average)
+	}
+
+	if WeightedAverage(nil, nil) != nil || WeightedAverage([]float64{80}, []float64{0}) != nil {
+		t.Errorf("there should be no average without weights")
+	}
+}
+
+func TestValidateAttendanceFields(t *testing.T) {
+	for _, valid := range []string{"", "2022-09-01", "2024-02-29"} {
+		if err := ValidateDate("date", valid); err != nil {
+			t.Errorf("date %s should be valid", valid)
+		}
+	}
+	for _, invalid := range []string{"2022-9-1", "01-09-2022", "2022-02-30", "today"} {
+		if err := ValidateDate("date", invalid); err == nil {
+			t.Errorf("date %s should be invalid", invalid)
+		}
+	}
+
+	if ValidateAttendanceStatuses(map[string]string{"a@gmail.com": "present", "b@gmail.com": "excused"}) != nil {
+		t.Errorf("the attendance statuses should be valid")
+	}
+	if ValidateAttendanceStatuses(map[string]string{"a@gmail.com": "present", "b@gmail.com": "sick"}) == nil {
+		t.Errorf("status sick should be invalid")
+	}
+
+	below, above := 75.0, 100.5
+	if ValidateAttendanceThreshold(nil) != nil || ValidateAttendanceThreshold(&below) != nil || ValidateAttendanceThreshold(&above) == nil {
+		t.Errorf("the threshold should have to be a percentage")
+	}
+}
+
+func TestAttendanceRate(t *testing.T) {
+	if rate := AttendanceRate(2, 1, 1); rate == nil || *rate != 75 {
+		t.Errorf("the attendance rate should be 75 but is %v", rate)
+	}
+	if rate := AttendanceRate(1, 0, 2); rate == nil || *rate != 33.33 {
+		t.Errorf("the attendance rate should be rounded to 33.33 but is %v", rate)
+	}
+	if AttendanceRate(0, 0, 0) != nil {
+		t.Errorf("there should be no attendance rate without attendances")
+	}
+}
+
+func TestValidatePhone(t *testing.T) {
+	for _, valid := range []string{"", "+65 6123 4567", "(555) 123-4567", "999", "1.800.555.0199"} {
+		if err := ValidatePhone("phone", valid); err != nil {
+			t.Errorf("phone %s should be valid", valid)
+		}
+	}
+	for _, invalid := range []string{"12", "+65 6123 4567 8901 23", "call me", "+65-6123-ABCD", "   "} {
+		if err := ValidatePhone("phone", invalid); err == nil {
+			t.Errorf("phone %s should be invalid", invalid)
+		}
+	}
+}
+
+func TestRosterCsvReader(t *testing.T) {
+	rows, err := NewRosterCsvReader(strings.NewReader("student,teacher\na@gmail.com,b@gmail.com\nc@gmail.com\n"))
+	if err != nil {
+		t.Fatalf("the header should be valid")
+	}
+
+	if row, err := rows.Next(); err != nil || *row != (RosterRow{Line: 2, TeacherEmail: "b@gmail.com", StudentEmail: "a@gmail.com"}) {
+		t.Errorf("wrong row %+v", row)
+	}
+	if row, err := rows.Next(); err != nil || *row != (RosterRow{Line: 3, StudentEmail: "c@gmail.com"}) {
+		t.Errorf("wrong row %+v", row)
+	}
+	if _, err := rows.Next(); err != io.EOF {
+		t.Errorf("the roster should end")
+	}
+
+	if _, err := NewRosterCsvReader(strings.NewReader("")); err == nil {
+		t.Errorf("an empty roster should have no header")
+	}
+}
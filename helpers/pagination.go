@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"learning-management-system/problems"
+)
+
+const DEFAULT_PAGE_LIMIT = 20
+const MAX_PAGE_LIMIT = 100
+
+// Cursor is the position after the last entry of a page, clients receive it as an opaque string and pass it back to
+// get the next page. Lists sorted by email continue after an email, lists sorted newest first continue before an id.
+type Cursor struct {
+	// NotificationId is the notification whose recipients are listed, it is 0 for other lists
+	NotificationId uint   `json:"n,omitempty"`
+	After          string `json:"a,omitempty"`
+	BeforeId       uint   `json:"b,omitempty"`
+}
+
+// ValidateCursorPagination checks the limit and cursor query parameters of a list sorted by email, a limit of 0 means
+// the parameter was not supplied and is replaced by DEFAULT_PAGE_LIMIT, and an empty cursor starts at the first page
+func ValidateCursorPagination(limit *int, encodedCursor string) (*Cursor, error) {
+	return validateCursorPagination(limit, encodedCursor, func(cursor *Cursor) bool { return cursor.After != "" })
+}
+
+// ValidateIdCursorPagination checks the limit and cursor query parameters of a list sorted newest first, like
+// ValidateCursorPagination does for the lists sorted by email
+func ValidateIdCursorPagination(limit *int, encodedCursor string) (*Cursor, error) {
+	return validateCursorPagination(limit, encodedCursor, func(cursor *Cursor) bool { return cursor.BeforeId != 0 })
+}
+
+func EncodeCursor(cursor *Cursor) string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func DecodeCursor(encodedCursor string) (*Cursor, error) {
+	cursor := &Cursor{}
+	content, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+
+	if err != nil || json.Unmarshal(content, cursor) != nil {
+		return nil, problems.Newf(problems.INVALID_CURSOR, "", encodedCursor).WithField("cursor")
+	}
+
+	return cursor, nil
+}
+
+// NextCursor returns the cursor of the page after the given page, or an empty string if it is the last page
+func NextCursor(page []string, hasMore bool, notificationId uint) string {
+	if !hasMore || len(page) == 0 {
+		return ""
+	}
+	return EncodeCursor(&Cursor{NotificationId: notificationId, After: page[len(page)-1]})
+}
+
+// NextIdCursor returns the cursor of the page after the given page of a list sorted newest first, or an empty string if
+// it is the last page
+func NextIdCursor[T any](page []T, hasMore bool, id func(entry T) uint) string {
+	if !hasMore || len(page) == 0 {
+		return ""
+	}
+	return EncodeCursor(&Cursor{BeforeId: id(page[len(page)-1])})
+}
+
+func validateCursorPagination(limit *int, encodedCursor string, positioned func(cursor *Cursor) bool) (*Cursor, error) {
+	if err := validateLimit(limit); err != nil {
+		return nil, err
+	}
+
+	if encodedCursor == "" {
+		return &Cursor{}, nil
+	}
+
+	cursor, err := DecodeCursor(encodedCursor)
+	if err == nil && !positioned(cursor) {
+		return nil, problems.Newf(problems.INVALID_CURSOR, "", encodedCursor).WithField("cursor")
+	}
+	return cursor, err
+}
+
+func validateLimit(limit *int) error {
+	if *limit == 0 {
+		*limit = DEFAULT_PAGE_LIMIT
+	}
+
+	if *limit < 1 || *limit > MAX_PAGE_LIMIT {
+		return problems.Newf(problems.INVALID_LIMIT, "", MAX_PAGE_LIMIT).WithField("limit")
+	}
+
+	return nil
+}
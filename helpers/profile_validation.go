@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"learning-management-system/problems"
+	"regexp"
+)
+
+var phoneRegex = regexp.MustCompile(`^\+?[0-9 ().-]+$`)
+var phoneDigitRegex = regexp.MustCompile(`[0-9]`)
+
+// ValidatePhone accepts an empty phone, or a phone number of 3 to 15 digits optionally starting with a plus and
+// separated by spaces, dots, dashes or parentheses. The emails, if any, are those of the profile the phone belongs to.
+func ValidatePhone(field string, phone string, emails ...string) error {
+	if phone == "" {
+		return nil
+	}
+
+	digitCount := len(phoneDigitRegex.FindAllString(phone, -1))
+	if !phoneRegex.MatchString(phone) || digitCount < 3 || digitCount > 15 {
+		return problems.Newf(problems.INVALID_FIELD, "phone", field).WithField(field).WithEmails(emails...)
+	}
+	return nil
+}
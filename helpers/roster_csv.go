@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"learning-management-system/problems"
+	"strings"
+)
+
+const ROSTER_TEACHER_COLUMN = "teacher"
+const ROSTER_STUDENT_COLUMN = "student"
+
+var errMissingRosterHeader = problems.Newf(problems.INVALID_CSV, "", ROSTER_TEACHER_COLUMN, ROSTER_STUDENT_COLUMN).WithField("file")
+
+// RosterRow is a line of a roster, a teacher without a student or a student without a teacher only creates the one
+// of them, while a line with both also registers the student to the teacher
+type RosterRow struct {
+	Line         int
+	TeacherEmail string
+	StudentEmail string
+}
+
+// RowError is an invalid line of a roster, the lines after it can still be read
+type RowError struct {
+	Line    int
+	Problem *problems.Problem
+}
+
+func (rowError *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", rowError.Line, rowError.Problem.Detail)
+}
+
+// RosterCsvReader reads a roster line by line from a CSV file whose header names a teacher column, a student column
+// or both, other columns are ignored
+type RosterCsvReader struct {
+	reader        *csv.Reader
+	teacherColumn int
+	studentColumn int
+}
+
+func NewRosterCsvReader(reader io.Reader) (*RosterCsvReader, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	csvReader.ReuseRecord = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errMissingRosterHeader
+	}
+
+	rosterReader := &RosterCsvReader{reader: csvReader, teacherColumn: -1, studentColumn: -1}
+	for column, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case ROSTER_TEACHER_COLUMN:
+			rosterReader.teacherColumn = column
+		case ROSTER_STUDENT_COLUMN:
+			rosterReader.studentColumn = column
+		}
+	}
+
+	if rosterReader.teacherColumn < 0 && rosterReader.studentColumn < 0 {
+		return nil, errMissingRosterHeader
+	}
+	return rosterReader, nil
+}
+
+// Next returns the next line of the roster or io.EOF after the last line. A *RowError is returned for an invalid
+// line, reading can go on after it. Blank lines are skipped.
+func (rosterReader *RosterCsvReader) Next() (*RosterRow, error) {
+	record, err := rosterReader.reader.Read()
+
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return nil, &RowError{Line: parseError.Line, Problem: problems.New(problems.INVALID_CSV, parseError.Err.Error())}
+	} else if err != nil {
+		return nil, err
+	}
+
+	line, _ := rosterReader.reader.FieldPos(0)
+	row := &RosterRow{
+		Line:         line,
+		TeacherEmail: column(record, rosterReader.teacherColumn),
+		StudentEmail: column(record, rosterReader.studentColumn),
+	}
+
+	if row.TeacherEmail == "" && row.StudentEmail == "" {
+		return nil, &RowError{Line: line, Problem: problems.Newf(problems.MISSING_FIELD, "row")}
+	}
+	for _, email := range []string{row.TeacherEmail, row.StudentEmail} {
+		if email == "" {
+			continue
+		}
+		if err := ValidateEmailFormat(email); err != nil {
+			return nil, &RowError{Line: line, Problem: problems.From(err, problems.INVALID_EMAIL)[0]}
+		}
+	}
+
+	return row, nil
+}
+
+func column(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
@@ -0,0 +1,180 @@
+package helpers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"strconv"
+)
+
+const EXPORT_CSV = "csv"
+const EXPORT_JSON = "json"
+const EXPORT_NDJSON = "ndjson"
+
+var ExportFormats = []string{EXPORT_CSV, EXPORT_JSON, EXPORT_NDJSON}
+
+const ROSTER_RECORD_COLUMN = "record"
+const ROSTER_SUSPENDED_COLUMN = "suspended"
+
+const RECORD_TEACHER = "teacher"
+const RECORD_STUDENT = "student"
+const RECORD_REGISTRATION = "registration"
+
+// RosterWriter streams a roster export, every teacher is written before the students and every student before the
+// registrations. A student is written as suspended when they have an active suspension. Close must be called after the
+// last record to complete the export.
+type RosterWriter interface {
+	ContentType() string
+	WriteTeacher(teacher *models.Teacher) error
+	WriteStudent(student *models.Student, suspended bool) error
+	WriteRegistration(relationship *models.RegisterRelationship) error
+	Close() error
+}
+
+// NewRosterWriter returns a writer of the format to the writer, an empty format is json
+func NewRosterWriter(format string, writer io.Writer) (RosterWriter, error) {
+	switch format {
+	case EXPORT_CSV:
+		return newCsvRosterWriter(writer), nil
+	case "", EXPORT_JSON:
+		return &jsonRosterWriter{writer: bufio.NewWriter(writer)}, nil
+	case EXPORT_NDJSON:
+		buffered := bufio.NewWriter(writer)
+		return &ndjsonRosterWriter{writer: buffered, encoder: json.NewEncoder(buffered)}, nil
+	}
+	return nil, problems.Newf(problems.INVALID_FORMAT, "", format, ExportFormats).WithField("format")
+}
+
+// csvRosterWriter writes a line per record, with a record column naming the kind of record. The export can be
+// imported back as its header names the teacher and student columns.
+type csvRosterWriter struct {
+	writer *csv.Writer
+}
+
+func newCsvRosterWriter(writer io.Writer) *csvRosterWriter {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Write([]string{ROSTER_RECORD_COLUMN, ROSTER_TEACHER_COLUMN, ROSTER_STUDENT_COLUMN, ROSTER_SUSPENDED_COLUMN})
+	return &csvRosterWriter{writer: csvWriter}
+}
+
+func (rosterWriter *csvRosterWriter) ContentType() string {
+	return "text/csv"
+}
+
+func (rosterWriter *csvRosterWriter) WriteTeacher(teacher *models.Teacher) error {
+	return rosterWriter.writer.Write([]string{RECORD_TEACHER, teacher.Email, "", ""})
+}
+
+func (rosterWriter *csvRosterWriter) WriteStudent(student *models.Student, suspended bool) error {
+	return rosterWriter.writer.Write([]string{RECORD_STUDENT, "", student.Email, strconv.FormatBool(suspended)})
+}
+
+func (rosterWriter *csvRosterWriter) WriteRegistration(relationship *models.RegisterRelationship) error {
+	return rosterWriter.writer.Write([]string{RECORD_REGISTRATION, relationship.TeacherEmail, relationship.StudentEmail, ""})
+}
+
+func (rosterWriter *csvRosterWriter) Close() error {
+	rosterWriter.writer.Flush()
+	return rosterWriter.writer.Error()
+}
+
+// jsonRosterWriter writes a single object holding an array for each kind of record, opening each array when its
+// first record is written
+type jsonRosterWriter struct {
+	writer *bufio.Writer
+	// section is the array being written, sections before it are complete
+	section int
+	empty   bool
+}
+
+var jsonRosterSections = []string{"teachers", "students", "registrations"}
+
+func (rosterWriter *jsonRosterWriter) ContentType() string {
+	return "application/json"
+}
+
+func (rosterWriter *jsonRosterWriter) WriteTeacher(teacher *models.Teacher) error {
+	return rosterWriter.write(1, &types.ExportedTeacher{Email: teacher.Email})
+}
+
+func (rosterWriter *jsonRosterWriter) WriteStudent(student *models.Student, suspended bool) error {
+	return rosterWriter.write(2, &types.ExportedStudent{Email: student.Email, IsSuspended: suspended})
+}
+
+func (rosterWriter *jsonRosterWriter) WriteRegistration(relationship *models.RegisterRelationship) error {
+	return rosterWriter.write(3, &types.ExportedRegistration{TeacherEmail: relationship.TeacherEmail, StudentEmail: relationship.StudentEmail})
+}
+
+func (rosterWriter *jsonRosterWriter) Close() error {
+	rosterWriter.openSection(len(jsonRosterSections) + 1)
+	return rosterWriter.writer.Flush()
+}
+
+func (rosterWriter *jsonRosterWriter) write(section int, record interface{}) error {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	rosterWriter.openSection(section)
+	if !rosterWriter.empty {
+		rosterWriter.writer.WriteByte(',')
+	}
+	rosterWriter.empty = false
+	_, err = rosterWriter.writer.Write(content)
+	return err
+}
+
+// openSection closes the array being written and writes the arrays up to the section, past the last section it
+// closes the object
+func (rosterWriter *jsonRosterWriter) openSection(section int) {
+	for rosterWriter.section < section {
+		if rosterWriter.section == 0 {
+			rosterWriter.writer.WriteByte('{')
+		} else {
+			rosterWriter.writer.WriteByte(']')
+		}
+
+		rosterWriter.section++
+		if rosterWriter.section > len(jsonRosterSections) {
+			rosterWriter.writer.WriteByte('}')
+			return
+		}
+		if rosterWriter.section > 1 {
+			rosterWriter.writer.WriteByte(',')
+		}
+		fmt.Fprintf(rosterWriter.writer, "%q:[", jsonRosterSections[rosterWriter.section-1])
+		rosterWriter.empty = true
+	}
+}
+
+// ndjsonRosterWriter writes a line per record, with a type naming the kind of record
+type ndjsonRosterWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (rosterWriter *ndjsonRosterWriter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (rosterWriter *ndjsonRosterWriter) WriteTeacher(teacher *models.Teacher) error {
+	return rosterWriter.encoder.Encode(&types.ExportedTeacher{Type: RECORD_TEACHER, Email: teacher.Email})
+}
+
+func (rosterWriter *ndjsonRosterWriter) WriteStudent(student *models.Student, suspended bool) error {
+	return rosterWriter.encoder.Encode(&types.ExportedStudent{Type: RECORD_STUDENT, Email: student.Email, IsSuspended: suspended})
+}
+
+func (rosterWriter *ndjsonRosterWriter) WriteRegistration(relationship *models.RegisterRelationship) error {
+	return rosterWriter.encoder.Encode(&types.ExportedRegistration{Type: RECORD_REGISTRATION, TeacherEmail: relationship.TeacherEmail, StudentEmail: relationship.StudentEmail})
+}
+
+func (rosterWriter *ndjsonRosterWriter) Close() error {
+	return rosterWriter.writer.Flush()
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"net/url"
+)
+
+// ValidateWebhookUrl only accepts absolute http and https urls
+func ValidateWebhookUrl(webhookUrl string) error {
+	parsed, err := url.Parse(webhookUrl)
+
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return problems.Newf(problems.INVALID_WEBHOOK_URL, "", webhookUrl).WithField("url")
+	}
+
+	return nil
+}
+
+func ValidateWebhookEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return problems.Newf(problems.INVALID_EVENT, "missing").WithField("events")
+	}
+
+	for _, eventType := range eventTypes {
+		if len(Filter(models.WebhookEventTypes, func(known string) bool { return known == eventType })) == 0 {
+			return problems.Newf(problems.INVALID_EVENT, "", eventType, models.WebhookEventTypes).WithField("events")
+		}
+	}
+
+	return nil
+}
@@ -2,35 +2,94 @@ package main
 
 import (
 	"github.com/gin-gonic/gin"
+	"learning-management-system/auth"
+	"learning-management-system/config"
 	"learning-management-system/controllers"
 	"learning-management-system/database"
 	"learning-management-system/models"
+	"learning-management-system/notifiers"
+	"learning-management-system/repositories"
+	"learning-management-system/schedulers"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/webhooks"
+	"log"
 )
 
 func main() {
-	connection := setupDb()
-	router := setupRouter(connection)
-	_ = router.Run(":8080")
+	appConfig, err := config.Load(config.ENV_PREFIX, config.Default())
+	if err != nil {
+		log.Fatalf("Invalid configuration : error=%v", err)
+	}
+
+	connection := setupDb(appConfig)
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewSqlRepositories())
+
+	if interval := appConfig.Scheduler.SuspensionExpiryIntervalDuration(); interval > 0 {
+		schedulers.NewSuspensionExpiryScheduler(connection, transactionManager, interval).Start()
+	}
+
+	notificationsConfig := &appConfig.Notifications
+	schedulers.NewNotificationDeliveryScheduler(connection, transactionManager, setupNotifier(notificationsConfig),
+		notificationsConfig.DeliveryIntervalDuration(), notificationsConfig.MaxAttempts, notificationsConfig.RetryBackoffDuration()).Start()
+
+	webhooksConfig := &appConfig.Webhooks
+	schedulers.NewWebhookDeliveryScheduler(connection, transactionManager, webhooks.NewHttpSender(webhooksConfig.TimeoutDuration()),
+		webhooksConfig.DeliveryIntervalDuration(), webhooksConfig.MaxAttempts, webhooksConfig.RetryBackoffDuration()).Start()
+
+	router := setupRouter(connection, transactionManager, appConfig)
+	_ = router.Run(":" + appConfig.Server.Port)
 }
 
-func setupRouter(connection *database.Connection) *gin.Engine {
+func setupRouter(connection *database.Connection, transactionManager *transaction_managers.TransactionManager, appConfig *config.Config) *gin.Engine {
+
+	if !appConfig.Server.Debug {
+		gin.SetMode(gin.ReleaseMode)
+	}
 
 	router := gin.Default()
-	repository := controllers.NewController(connection)
+	repository := controllers.NewController(connection, transactionManager)
 
-	router.POST("/api/register", repository.RegisterStudentsToTeacher)
-	router.GET("/api/commonstudents", repository.RetrieveCommonStudents)
-	router.POST("/api/suspend", repository.SuspendStudent)
-	router.POST("/api/retrievefornotifications", repository.RetrieveStudentRecipients)
-	router.DELETE("/api/clear", repository.ClearDatabase)
-	router.POST("/api/populateteachers", repository.PopulateTeachers)
-	router.POST("/api/populatestudents", repository.PopulateStudents)
+	// every api route requires a token when auth is enabled, and the admin routes additionally require the admin role
+	var authenticate gin.HandlerFunc
+	if appConfig.Auth.Enabled {
+		authenticate = setupAuthenticator(&appConfig.Auth).Middleware()
+	}
+	repository.RegisterRoutes(router, authenticate, appConfig.Features.TestingEndpoints)
 
 	return router
 }
 
-func setupDb() *database.Connection {
-	connection := database.InitDefaultConnection()
-	connection.GetDb().AutoMigrate(&models.Teacher{}, &models.Student{}, &models.RegisterRelationship{})
+func setupAuthenticator(authConfig *config.AuthConfig) *auth.Authenticator {
+	if authConfig.Algorithm == auth.RS256 {
+		publicKey, err := auth.LoadRsaPublicKey(authConfig.PublicKeyFile)
+		if err != nil {
+			log.Fatalf("Invalid auth public key : error=%v", err)
+		}
+		return auth.NewRsaAuthenticator(publicKey, authConfig.Issuer, authConfig.Audience)
+	}
+	return auth.NewHmacAuthenticator([]byte(authConfig.Secret), authConfig.Issuer, authConfig.Audience)
+}
+
+func setupNotifier(notificationsConfig *config.NotificationsConfig) notifiers.Notifier {
+	if notificationsConfig.Notifier == config.SMTP_NOTIFIER {
+		return notifiers.NewSmtpNotifier(&notifiers.SmtpCredentials{
+			Host:     notificationsConfig.SmtpHost,
+			Port:     notificationsConfig.SmtpPort,
+			Username: notificationsConfig.SmtpUsername,
+			Password: notificationsConfig.SmtpPassword,
+			From:     notificationsConfig.SmtpFrom,
+		})
+	}
+	return notifiers.NewLogNotifier(nil)
+}
+
+func setupDb(appConfig *config.Config) *database.Connection {
+	connection := database.NewConnection(appConfig.Database.Credentials())
+	if connection.GetDb() == nil {
+		log.Fatalf("Could not connect to the %s database %s", appConfig.Database.Driver, appConfig.Database.Name)
+	}
+	if err := models.Migrate(connection.GetDb()); err != nil {
+		log.Fatalf("Could not migrate the %s database %s : error=%v", appConfig.Database.Driver, appConfig.Database.Name, err)
+	}
 	return connection
 }
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"learning-management-system/auth"
+	"learning-management-system/config"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/openapi"
+	"learning-management-system/problems"
+	"learning-management-system/repositories"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOpenApiDocumentsEveryRoute fails when a route is added to setupRouter without being described in
+// openapi.Endpoints, or the other way around, and when a route does not enforce the access it is documented with
+func TestOpenApiDocumentsEveryRoute(t *testing.T) {
+	appConfig := config.Default()
+	appConfig.Server.Debug = false
+	appConfig.Features.TestingEndpoints = true
+
+	router := setupRouter(&database.Connection{}, transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories()), appConfig)
+
+	routes := map[string]bool{}
+	for _, route := range router.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+
+	documented := map[string]bool{}
+	for _, endpoint := range openapi.Endpoints {
+		documented[endpoint.Method+" "+endpoint.Path] = true
+		if !routes[endpoint.Method+" "+endpoint.Path] {
+			t.Errorf("%s %s is documented but not routed", endpoint.Method, endpoint.Path)
+		}
+	}
+
+	for route := range routes {
+		if !documented[route] {
+			t.Errorf("%s is routed but not documented", route)
+		}
+	}
+
+	assertAdminEndpointsRequireAdmin(t, appConfig)
+	assertTeacherEndpointsAuthorize(t, appConfig)
+}
+
+// assertAdminEndpointsRequireAdmin sends a teacher token to every admin endpoint, which RequireAdmin must reject before
+// the handler runs. The testing endpoints are admin endpoints, so they are served with auth enabled even when they are
+// not asked for.
+func assertAdminEndpointsRequireAdmin(t *testing.T, appConfig *config.Config) {
+	router := setupAuthRouter(appConfig)
+	teacherToken := signToken(t, appConfig, "teacher@gmail.com", auth.ROLE_TEACHER)
+
+	for _, endpoint := range openapi.Endpoints {
+		if endpoint.Access != openapi.ACCESS_ADMIN {
+			continue
+		}
+
+		recorder := serve(router, endpoint.Method, pathParameter.ReplaceAllString(endpoint.Path, "$1"), "{}", teacherToken)
+		problem := &types.ProblemResponse{}
+		_ = json.Unmarshal(recorder.Body.Bytes(), problem)
+		if recorder.Code != http.StatusForbidden || problem.Code != problems.FORBIDDEN || problem.Detail != "This endpoint requires the admin role" {
+			t.Errorf("%s %s is documented for admins but is not behind RequireAdmin", endpoint.Method, endpoint.Path)
+		}
+	}
+}
+
+// teacherEndpointRequests hold the query and body of the requests to the teacher endpoints that are valid apart from
+// their teacher, by operation id without its version suffix. The other endpoints are sent an empty json body.
+var teacherEndpointRequests = map[string]struct {
+	query string
+	body  string
+}{
+	"RegisterStudentsToTeacher":     {body: `{"teacher":"owner@gmail.com","students":["student@gmail.com"]}`},
+	"UnregisterStudentsFromTeacher": {body: `{"teacher":"owner@gmail.com","students":["student@gmail.com"]}`},
+	"RetrieveStudentRecipients":     {body: `{"teacher":"owner@gmail.com","notification":"hello"}`},
+	"SendNotification":              {body: `{"notification":"hello"}`},
+	"RetrieveMoreStudentRecipients": {query: "?cursor=" + helpers.EncodeCursor(&helpers.Cursor{NotificationId: 1, After: "a@gmail.com"})},
+	"CreateAssignment":              {body: `{"title":"Essay","due_at":"2022-09-01T08:00:00Z","max_score":10}`},
+	"SubmitAssignment":              {body: `{"text":"answers"}`},
+	"SaveGradeCategory":             {body: `{"weight":1}`},
+	"GradeStudent":                  {body: `{"category":"homework","score":1}`},
+	"RecordRollCall":                {body: `{"students":{"student@gmail.com":"present"}}`},
+	"UpdateTeacher":                 {body: `{"name":"Owner"}`},
+}
+
+// assertTeacherEndpointsAuthorize sends the token of another teacher to every teacher endpoint, acting on a teacher,
+// student, assignment and notification of owner@gmail.com, which the endpoint must forbid
+func assertTeacherEndpointsAuthorize(t *testing.T, appConfig *config.Config) {
+	router := setupAuthRouter(appConfig)
+	adminToken := signToken(t, appConfig, "admin@gmail.com", auth.ROLE_ADMIN)
+	otherTeacherToken := signToken(t, appConfig, "other@gmail.com", auth.ROLE_TEACHER)
+
+	for _, seed := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/populateteachers", `{"teachers":["owner@gmail.com","other@gmail.com"]}`},
+		{http.MethodPost, "/api/populatestudents", `{"students":["student@gmail.com"]}`},
+		{http.MethodPost, "/api/register", `{"teacher":"owner@gmail.com","students":["student@gmail.com"]}`},
+		{http.MethodPost, "/api/teachers/owner@gmail.com/assignments", `{"title":"Essay","due_at":"2022-09-01T08:00:00Z","max_score":10}`},
+		{http.MethodPost, "/api/retrievefornotifications", `{"teacher":"owner@gmail.com","notification":"hello"}`},
+	} {
+		if recorder := serve(router, seed.method, seed.path, seed.body, adminToken); recorder.Code >= http.StatusBadRequest {
+			t.Fatalf("could not seed %s %s: %s", seed.method, seed.path, recorder.Body.String())
+		}
+	}
+
+	// the emails of teachers are those after /teachers/, the others are of students
+	pathValues := strings.NewReplacer("teachers/:email", "teachers/owner@gmail.com", ":email", "student@gmail.com",
+		":student", "student@gmail.com", ":id", "1", ":name", "homework", ":date", "2022-09-01")
+	for _, endpoint := range openapi.Endpoints {
+		if endpoint.Access != openapi.ACCESS_TEACHER && endpoint.Access != openapi.ACCESS_REGISTERED_TEACHER {
+			continue
+		}
+
+		request, ok := teacherEndpointRequests[strings.TrimSuffix(strings.TrimSuffix(endpoint.OperationId, "V1"), "V2")]
+		if !ok {
+			request.body = "{}"
+		}
+
+		recorder := serve(router, endpoint.Method, pathValues.Replace(endpoint.Path)+request.query, request.body, otherTeacherToken)
+		problem := &types.ProblemResponse{}
+		_ = json.Unmarshal(recorder.Body.Bytes(), problem)
+		if recorder.Code != http.StatusForbidden || problem.Code != problems.FORBIDDEN {
+			t.Errorf("%s %s is documented for the %s access but let another teacher through: %d %s", endpoint.Method, endpoint.Path,
+				endpoint.Access, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+var pathParameter = regexp.MustCompile(`:(\w+)`)
+
+// setupAuthRouter sets up a router on fresh in-memory repositories that requires tokens signed with a test secret
+func setupAuthRouter(appConfig *config.Config) *gin.Engine {
+	appConfig.Features.TestingEndpoints = false
+	appConfig.Auth.Enabled = true
+	appConfig.Auth.Algorithm = auth.HS256
+	appConfig.Auth.Secret = "a test secret that is long enough"
+	return setupRouter(&database.Connection{}, transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories()), appConfig)
+}
+
+func signToken(t *testing.T, appConfig *config.Config, subject string, role string) string {
+	claims := &auth.Claims{
+		Role:             role,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: subject, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(appConfig.Auth.Secret))
+	if err != nil {
+		t.Fatalf("could not sign token: %s", err.Error())
+	}
+	return token
+}
+
+func serve(router *gin.Engine, method string, path string, body string, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestOpenApiDocumentDescribesEveryOperation(t *testing.T) {
+	document := openapi.Build()
+
+	operationIds := map[string]bool{}
+	for _, endpoint := range openapi.Endpoints {
+		if operationIds[endpoint.OperationId] {
+			t.Errorf("the operation id %s of %s %s is not unique", endpoint.OperationId, endpoint.Method, endpoint.Path)
+		}
+		operationIds[endpoint.OperationId] = true
+	}
+
+	for _, endpoint := range openapi.Endpoints {
+		operation := document.Paths[openapi.DocumentPath(endpoint.Path)][strings.ToLower(endpoint.Method)]
+		if operation == nil {
+			t.Errorf("%s %s has no operation", endpoint.Method, endpoint.Path)
+			continue
+		}
+
+		// every path parameter of the route must be bound by the request
+		for _, name := range openapi.PathParameters(endpoint.Path) {
+			found := false
+			for _, parameter := range operation.Parameters {
+				found = found || (parameter.In == "path" && parameter.Name == name)
+			}
+			if !found {
+				t.Errorf("%s %s does not describe the path parameter %s", endpoint.Method, endpoint.Path, name)
+			}
+		}
+	}
+
+	if _, err := json.Marshal(document); err != nil {
+		t.Errorf("the document cannot be marshalled: %v", err)
+	}
+}
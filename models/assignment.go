@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Statuses of the work of a student on an assignment, derived from the due date and the submission of the student
+
+const WORK_PENDING = "pending"
+const WORK_MISSING = "missing"
+const WORK_SUBMITTED = "submitted"
+const WORK_LATE = "late"
+
+var WorkStatuses = []string{WORK_PENDING, WORK_MISSING, WORK_SUBMITTED, WORK_LATE}
+
+// Assignment is set by a teacher for the students registered to them
+type Assignment struct {
+	ID           uint    `gorm:"primaryKey"`
+	TeacherEmail string  `gorm:"index"`
+	Teacher      Teacher `gorm:"foreignKey:TeacherEmail"`
+	Title        string
+	Description  string
+	DueAt        time.Time
+	MaxScore     int
+	CreatedAt    time.Time
+}
+
+// Submission is the latest work a student handed in for an assignment, submitting again replaces it
+type Submission struct {
+	AssignmentID  uint       `gorm:"primaryKey"`
+	Assignment    Assignment `gorm:"foreignKey:AssignmentID"`
+	StudentEmail  string     `gorm:"primaryKey;index"`
+	Student       Student    `gorm:"foreignKey:StudentEmail"`
+	Text          string
+	FileReference string
+	SubmittedAt   time.Time
+}
+
+// WorkStatus returns the status of the work on the assignment at the given time, submission being nil when the
+// student has not submitted anything
+func (assignment *Assignment) WorkStatus(submission *Submission, at time.Time) string {
+	if submission == nil {
+		if at.After(assignment.DueAt) {
+			return WORK_MISSING
+		}
+		return WORK_PENDING
+	}
+	if submission.SubmittedAt.After(assignment.DueAt) {
+		return WORK_LATE
+	}
+	return WORK_SUBMITTED
+}
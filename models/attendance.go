@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Statuses of the attendance of a student on a date. An excused absence is neither held for nor against the student.
+
+const ATTENDANCE_PRESENT = "present"
+const ATTENDANCE_ABSENT = "absent"
+const ATTENDANCE_LATE = "late"
+const ATTENDANCE_EXCUSED = "excused"
+
+var AttendanceStatuses = []string{ATTENDANCE_PRESENT, ATTENDANCE_ABSENT, ATTENDANCE_LATE, ATTENDANCE_EXCUSED}
+
+// ATTENDANCE_DATE_LAYOUT formats the dates of the attendances, which sort chronologically as strings
+const ATTENDANCE_DATE_LAYOUT = "2006-01-02"
+
+// Attendance is the status a teacher recorded for a student registered to them on a date, recording it again replaces
+// it
+type Attendance struct {
+	TeacherEmail string  `gorm:"primaryKey"`
+	Teacher      Teacher `gorm:"foreignKey:TeacherEmail"`
+	StudentEmail string  `gorm:"primaryKey;index"`
+	Student      Student `gorm:"foreignKey:StudentEmail"`
+	Date         string  `gorm:"primaryKey;size:10"`
+	Status       string
+	RecordedAt   time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+// Course is a subject, its students are taught in sections
+type Course struct {
+	Code string `gorm:"primaryKey"`
+	Name string
+}
+
+// Section is a class of a course, named uniquely within the course and taught by the teachers assigned to it
+type Section struct {
+	ID         uint   `gorm:"primaryKey"`
+	CourseCode string `gorm:"uniqueIndex:idx_section_course_name"`
+	Course     Course `gorm:"foreignKey:CourseCode"`
+	Name       string `gorm:"uniqueIndex:idx_section_course_name"`
+}
+
+// SectionTeacher assigns a teacher to a section
+type SectionTeacher struct {
+	SectionID    uint    `gorm:"primaryKey"`
+	Section      Section `gorm:"foreignKey:SectionID"`
+	TeacherEmail string  `gorm:"primaryKey;index"`
+	Teacher      Teacher `gorm:"foreignKey:TeacherEmail"`
+}
+
+// Enrollment enrolls a student into a section
+type Enrollment struct {
+	SectionID    uint    `gorm:"primaryKey"`
+	Section      Section `gorm:"foreignKey:SectionID"`
+	StudentEmail string  `gorm:"primaryKey;index"`
+	Student      Student `gorm:"foreignKey:StudentEmail"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// GradeCategory weighs the grades a teacher gives in it, such as homework or exams. The weights of the categories of
+// a teacher are relative to each other and need not add up to 100.
+type GradeCategory struct {
+	ID           uint    `gorm:"primaryKey"`
+	TeacherEmail string  `gorm:"uniqueIndex:idx_grade_category_teacher_name"`
+	Teacher      Teacher `gorm:"foreignKey:TeacherEmail"`
+	Name         string  `gorm:"uniqueIndex:idx_grade_category_teacher_name"`
+	Weight       float64
+}
+
+// Grade is a score a teacher gives a student registered to them, optionally for one of their assignments
+type Grade struct {
+	ID           uint          `gorm:"primaryKey"`
+	TeacherEmail string        `gorm:"index:idx_grade_teacher_student"`
+	Teacher      Teacher       `gorm:"foreignKey:TeacherEmail"`
+	StudentEmail string        `gorm:"index:idx_grade_teacher_student"`
+	Student      Student       `gorm:"foreignKey:StudentEmail"`
+	CategoryID   uint          `gorm:"index"`
+	Category     GradeCategory `gorm:"foreignKey:CategoryID"`
+	AssignmentID *uint
+	Assignment   *Assignment `gorm:"foreignKey:AssignmentID"`
+	Title        string
+	Score        float64
+	MaxScore     float64
+	GradedAt     time.Time
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// All lists every model, in an order that lets them be migrated one after the other
+func All() []interface{} {
+	return []interface{}{
+		&Teacher{}, &Student{}, &RegisterRelationship{}, &StudentSuspension{}, &Notification{}, &NotificationRecipient{},
+		&NotificationDelivery{}, &WebhookSubscription{}, &WebhookDelivery{}, &Course{}, &Section{}, &SectionTeacher{},
+		&Enrollment{}, &Assignment{}, &Submission{}, &GradeCategory{}, &Grade{}, &Attendance{},
+	}
+}
+
+// Migrate migrates every model and then the data that predates them
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(All()...); err != nil {
+		return err
+	}
+	return backfillSuspensions(db)
+}
+
+// backfillSuspensions opens a suspension for the students flagged as suspended without one, who were suspended before
+// suspensions were recorded. Only the suspensions tell who is suspended, so these students would be notified again.
+func backfillSuspensions(db *gorm.DB) error {
+	openSuspensions := db.Table("student_suspensions").Select("1").
+		Where("student_suspensions.student_email = students.email AND student_suspensions.lifted_at IS NULL")
+
+	var studentEmails []string
+	if err := db.Table("students").Where("is_suspended = ? AND NOT EXISTS (?)", true, openSuspensions).
+		Order("email").Pluck("email", &studentEmails).Error; err != nil || len(studentEmails) == 0 {
+		return err
+	}
+
+	now := time.Now()
+	suspensions := make([]*StudentSuspension, 0, len(studentEmails))
+	for _, studentEmail := range studentEmails {
+		suspensions = append(suspensions, &StudentSuspension{StudentEmail: studentEmail, SuspendedAt: now})
+	}
+	return db.Omit("Student").Create(&suspensions).Error
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Notification struct {
+	ID           uint    `gorm:"primaryKey"`
+	TeacherEmail string  `gorm:"index"`
+	Teacher      Teacher `gorm:"foreignKey:TeacherEmail"`
+	Message      string
+	SentAt       time.Time
+}
+
+// NotificationRecipient is an entry in the inbox of a student, one per resolved recipient of a notification
+type NotificationRecipient struct {
+	NotificationID uint         `gorm:"primaryKey"`
+	Notification   Notification `gorm:"foreignKey:NotificationID"`
+	StudentEmail   string       `gorm:"primaryKey;index"`
+	Student        Student      `gorm:"foreignKey:StudentEmail"`
+	ReadAt         *time.Time
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Statuses of a notification delivery
+
+const DELIVERY_PENDING = "pending"
+const DELIVERY_SENT = "sent"
+const DELIVERY_FAILED = "failed"
+
+// NotificationDelivery is the outbox entry for delivering a notification to one of its recipients
+type NotificationDelivery struct {
+	NotificationID uint         `gorm:"primaryKey"`
+	Notification   Notification `gorm:"foreignKey:NotificationID"`
+	StudentEmail   string       `gorm:"primaryKey"`
+	Student        Student      `gorm:"foreignKey:StudentEmail"`
+	Status         string       `gorm:"index"`
+	Attempts       int
+	LastError      string
+	NextAttemptAt  time.Time `gorm:"index"`
+	DeliveredAt    *time.Time
+}
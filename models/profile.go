@@ -0,0 +1,32 @@
+package models
+
+import "encoding/json"
+
+// Profile holds the optional details of a teacher or a student beyond their email
+type Profile struct {
+	Name        string
+	DisplayName string
+	GradeLevel  string
+	Homeroom    string
+	Phone       string
+	// Metadata is a JSON object of string values kept for the clients, the api does not interpret it
+	Metadata string
+}
+
+func (profile *Profile) MetadataMap() map[string]string {
+	metadata := map[string]string{}
+	if profile.Metadata != "" {
+		_ = json.Unmarshal([]byte(profile.Metadata), &metadata)
+	}
+	return metadata
+}
+
+// SetMetadata stores the metadata as a JSON object, or clears it when there is none
+func (profile *Profile) SetMetadata(metadata map[string]string) {
+	if len(metadata) == 0 {
+		profile.Metadata = ""
+		return
+	}
+	encoded, _ := json.Marshal(metadata)
+	profile.Metadata = string(encoded)
+}
@@ -3,4 +3,5 @@ package models
 type Student struct {
 	Email       string `gorm:"primaryKey"`
 	IsSuspended bool
+	Profile     Profile `gorm:"embedded"`
 }
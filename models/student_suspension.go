@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type StudentSuspension struct {
+	ID           uint    `gorm:"primaryKey"`
+	StudentEmail string  `gorm:"index"`
+	Student      Student `gorm:"foreignKey:StudentEmail"`
+	SuspendedBy  string
+	Reason       string
+	SuspendedAt  time.Time
+	Until        *time.Time `gorm:"index"`
+	LiftedAt     *time.Time
+}
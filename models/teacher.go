@@ -1,5 +1,6 @@
 package models
 
 type Teacher struct {
-	Email string `gorm:"primaryKey"`
+	Email   string  `gorm:"primaryKey"`
+	Profile Profile `gorm:"embedded"`
 }
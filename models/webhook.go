@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Events that webhook subscriptions can subscribe to
+
+const EVENT_STUDENTS_REGISTERED = "students.registered"
+const EVENT_STUDENT_SUSPENDED = "student.suspended"
+const EVENT_NOTIFICATION_SENT = "notification.sent"
+
+var WebhookEventTypes = []string{EVENT_STUDENTS_REGISTERED, EVENT_STUDENT_SUSPENDED, EVENT_NOTIFICATION_SENT}
+
+// WebhookSubscription posts the events of the given types to a url, signed with the secret
+type WebhookSubscription struct {
+	ID  uint `gorm:"primaryKey"`
+	Url string
+	// EventTypes is the comma separated list of subscribed events
+	EventTypes string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+func (subscription *WebhookSubscription) Events() []string {
+	if subscription.EventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(subscription.EventTypes, ",")
+}
+
+func (subscription *WebhookSubscription) IsSubscribedTo(eventType string) bool {
+	for _, subscribedEventType := range subscription.Events() {
+		if subscribedEventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is the queue entry for posting an event to a subscription, it uses the statuses of a notification
+// delivery
+type WebhookDelivery struct {
+	ID             uint                `gorm:"primaryKey"`
+	SubscriptionID uint                `gorm:"index"`
+	Subscription   WebhookSubscription `gorm:"foreignKey:SubscriptionID"`
+	EventType      string
+	Payload        string
+	Status         string `gorm:"index"`
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	NextAttemptAt  time.Time `gorm:"index"`
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
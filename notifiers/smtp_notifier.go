@@ -0,0 +1,55 @@
+package notifiers
+
+import (
+	"fmt"
+	"learning-management-system/models"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type SmtpCredentials struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SmtpNotifier emails notifications through an SMTP server, authenticating only if a username is given
+type SmtpNotifier struct {
+	credentials *SmtpCredentials
+}
+
+func NewSmtpNotifier(credentials *SmtpCredentials) *SmtpNotifier {
+	return &SmtpNotifier{credentials: credentials}
+}
+
+func (notifier *SmtpNotifier) Notify(recipientEmail string, notification *models.Notification) error {
+	var auth smtp.Auth
+	if notifier.credentials.Username != "" {
+		auth = smtp.PlainAuth("", notifier.credentials.Username, notifier.credentials.Password, notifier.credentials.Host)
+	}
+
+	address := net.JoinHostPort(notifier.credentials.Host, notifier.credentials.Port)
+	return smtp.SendMail(address, auth, notifier.credentials.From, []string{recipientEmail}, notifier.buildMessage(recipientEmail, notification))
+}
+
+func (notifier *SmtpNotifier) buildMessage(recipientEmail string, notification *models.Notification) []byte {
+	headers := []string{
+		"From: " + notifier.credentials.From,
+		"To: " + recipientEmail,
+		"Reply-To: " + notification.TeacherEmail,
+		"Subject: " + mime.QEncoding.Encode("utf-8", "Notification from "+notification.TeacherEmail),
+		"Date: " + notification.SentAt.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+	}
+
+	// SMTP requires CRLF line endings in the body as well
+	body := strings.ReplaceAll(strings.ReplaceAll(notification.Message, "\r\n", "\n"), "\n", "\r\n")
+
+	return []byte(fmt.Sprintf("%s\r\n\r\n%s\r\n", strings.Join(headers, "\r\n"), body))
+}
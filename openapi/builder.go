@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"learning-management-system/types"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const OPENAPI_VERSION = "3.0.3"
+const PROBLEM_CONTENT_TYPE = "application/problem+json"
+
+var ginParameterPattern = regexp.MustCompile(`:([^/]+)`)
+
+var buildOnce sync.Once
+var document *Document
+
+// Build returns the OpenAPI document of Endpoints, it is built once
+func Build() *Document {
+	buildOnce.Do(func() {
+		document = BuildDocument(Endpoints)
+	})
+	return document
+}
+
+func BuildDocument(endpoints []*Endpoint) *Document {
+	generator := newSchemaGenerator()
+	problem := generator.schemaOf(reflect.TypeOf(types.ProblemResponse{}))
+
+	built := &Document{
+		OpenApi: OPENAPI_VERSION,
+		Info: &Info{
+			Title: "Learning Management System API",
+			Description: "Errors are RFC 7807 problem details. When auth is enabled, every operation but the docs requires a " +
+				"bearer token, teachers may only act as themselves and admin operations require the admin role. The " +
+				"unversioned operations are a deprecated alias of /api/v1, /api/v2 takes the emails and ids it acts on from the path.",
+			Version: "1.0.0",
+		},
+		Paths: map[string]PathItem{},
+	}
+
+	for _, endpoint := range endpoints {
+		path := DocumentPath(endpoint.Path)
+		if built.Paths[path] == nil {
+			built.Paths[path] = PathItem{}
+		}
+		built.Paths[path][strings.ToLower(endpoint.Method)] = buildOperation(generator, endpoint, problem)
+	}
+
+	built.Components = &Components{
+		Schemas: generator.schemas,
+		SecuritySchemes: map[string]*SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+	return built
+}
+
+// DocumentPath turns the :parameters of a gin path into the {parameters} of an OpenAPI path
+func DocumentPath(ginPath string) string {
+	return ginParameterPattern.ReplaceAllString(ginPath, "{$1}")
+}
+
+// PathParameters returns the names of the :parameters of a gin path
+func PathParameters(ginPath string) []string {
+	var names []string
+	for _, match := range ginParameterPattern.FindAllStringSubmatch(ginPath, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func buildOperation(generator *schemaGenerator, endpoint *Endpoint, problem *Schema) *Operation {
+	operation := &Operation{
+		OperationId: endpoint.OperationId,
+		Summary:     endpoint.Summary,
+		Tags:        []string{endpoint.Tag},
+		Responses:   map[string]*Response{},
+		Deprecated:  endpoint.Deprecated,
+	}
+
+	if endpoint.Request != nil {
+		requestType := reflect.TypeOf(endpoint.Request).Elem()
+		operation.Parameters = generator.parametersOf(requestType, endpoint.Multipart)
+
+		if endpoint.Multipart {
+			operation.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+				"multipart/form-data": {Schema: generator.objectOf(requestType, "form")},
+			}}
+		} else if len(fieldsWithTag(requestType, "json")) > 0 {
+			operation.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+				CONTENT_TYPE_JSON: {Schema: generator.componentOf(requestType, "json")},
+			}}
+		}
+	}
+
+	success := &Response{Description: http.StatusText(endpoint.Status)}
+	if endpoint.Status != http.StatusNoContent {
+		success.Content = map[string]*MediaType{}
+		contentTypes := endpoint.ContentTypes
+		if len(contentTypes) == 0 {
+			contentTypes = []string{CONTENT_TYPE_JSON}
+		}
+		for _, contentType := range contentTypes {
+			schema := &Schema{Type: "string"}
+			if contentType == CONTENT_TYPE_JSON && endpoint.Response != nil {
+				schema = generator.schemaOf(reflect.TypeOf(endpoint.Response))
+			} else if contentType == CONTENT_TYPE_JSON {
+				schema = &Schema{Type: "object"}
+			}
+			success.Content[contentType] = &MediaType{Schema: schema}
+		}
+	}
+	operation.Responses[strconv.Itoa(endpoint.Status)] = success
+
+	problemStatuses := []int{http.StatusInternalServerError}
+	if endpoint.Request != nil {
+		problemStatuses = append(problemStatuses, http.StatusBadRequest)
+	}
+	if endpoint.Access != ACCESS_PUBLIC {
+		operation.Security = []map[string][]string{{"bearerAuth": {}}}
+		problemStatuses = append(problemStatuses, http.StatusUnauthorized)
+	}
+	if endpoint.Access == ACCESS_TEACHER || endpoint.Access == ACCESS_REGISTERED_TEACHER || endpoint.Access == ACCESS_ADMIN {
+		problemStatuses = append(problemStatuses, http.StatusForbidden)
+	}
+	for _, status := range problemStatuses {
+		operation.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]*MediaType{PROBLEM_CONTENT_TYPE: {Schema: problem}},
+		}
+	}
+
+	return operation
+}
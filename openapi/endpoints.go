@@ -0,0 +1,243 @@
+package openapi
+
+import (
+	"learning-management-system/types"
+	"net/http"
+)
+
+const CONTENT_TYPE_JSON = "application/json"
+const CONTENT_TYPE_CSV = "text/csv"
+const CONTENT_TYPE_NDJSON = "application/x-ndjson"
+const CONTENT_TYPE_HTML = "text/html"
+const CONTENT_TYPE_CSS = "text/css"
+const CONTENT_TYPE_JAVASCRIPT = "application/javascript"
+
+// Access tells who may call an endpoint when auth is enabled
+const (
+	ACCESS_ANY                = "any"
+	ACCESS_TEACHER            = "teacher"
+	ACCESS_REGISTERED_TEACHER = "registered teacher"
+	ACCESS_ADMIN              = "admin"
+	ACCESS_PUBLIC             = "public"
+)
+
+// Endpoint describes a route of the router, its request and response are types structs whose tags give the
+// parameters and bodies of the operation
+type Endpoint struct {
+	Method      string
+	Path        string
+	OperationId string
+	Summary     string
+	Tag         string
+	Access      string
+	Request     interface{}
+	// Multipart tells that the form fields of the request are a multipart body rather than query parameters
+	Multipart bool
+	Status    int
+	Response  interface{}
+	// ContentTypes of the response, application/json when empty
+	ContentTypes []string
+	Deprecated   bool
+}
+
+// Endpoints lists every route registered by setupRouter, a test fails when they diverge. The unversioned routes are
+// the deprecated alias of v1.
+var Endpoints = joinEndpoints(
+	docsEndpoints,
+	versionEndpoints("/api", "", true, v1Endpoints, resourceEndpoints),
+	versionEndpoints("/api/v1", "V1", false, v1Endpoints, resourceEndpoints),
+	versionEndpoints("/api/v2", "V2", false, v2Endpoints, resourceEndpoints),
+)
+
+var docsEndpoints = []*Endpoint{
+	{Method: http.MethodGet, Path: "/api/openapi.json", OperationId: "RetrieveOpenApiDocument", Summary: "This OpenAPI document", Tag: "docs",
+		Access: ACCESS_PUBLIC, Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/api/docs", OperationId: "RetrieveSwaggerUi", Summary: "Swagger UI for this document", Tag: "docs",
+		Access: ACCESS_PUBLIC, Status: http.StatusOK, ContentTypes: []string{CONTENT_TYPE_HTML}},
+	{Method: http.MethodGet, Path: "/api/docs/:file", OperationId: "RetrieveSwaggerUiAsset", Summary: "A stylesheet or script of Swagger UI", Tag: "docs",
+		Access: ACCESS_PUBLIC, Request: &types.RetrieveSwaggerUiAssetRequest{}, Status: http.StatusOK, ContentTypes: []string{CONTENT_TYPE_CSS, CONTENT_TYPE_JAVASCRIPT}},
+}
+
+// v1Endpoints are relative to the path of their version
+var v1Endpoints = []*Endpoint{
+	{Method: http.MethodPost, Path: "/register", OperationId: "RegisterStudentsToTeacher", Summary: "Register students to a teacher", Tag: "registrations",
+		Access: ACCESS_TEACHER, Request: &types.RegisterStudentsToTeacherRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/unregister", OperationId: "UnregisterStudentsFromTeacher", Summary: "Unregister students from a teacher", Tag: "registrations",
+		Access: ACCESS_TEACHER, Request: &types.UnregisterStudentsFromTeacherRequest{}, Status: http.StatusOK, Response: &types.UnregisterStudentsFromTeacherResponse{}},
+	{Method: http.MethodGet, Path: "/commonstudents", OperationId: "RetrieveCommonStudents", Summary: "Students registered to the teachers", Tag: "registrations",
+		Access: ACCESS_ANY, Request: &types.RetrieveCommonStudentsRequest{}, Status: http.StatusOK, Response: &types.RetrieveRegisteredStudentsResponse{}},
+
+	{Method: http.MethodPost, Path: "/suspend", OperationId: "SuspendStudent", Summary: "Suspend a student", Tag: "suspensions",
+		Access: ACCESS_ADMIN, Request: &types.StudentSuspensionRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/unsuspend", OperationId: "UnsuspendStudent", Summary: "Lift the suspension of a student", Tag: "suspensions",
+		Access: ACCESS_ADMIN, Request: &types.StudentUnsuspensionRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/students/:email/suspensions", OperationId: "RetrieveStudentSuspensions", Summary: "Suspension history of a student", Tag: "suspensions",
+		Access: ACCESS_ANY, Request: &types.RetrieveStudentSuspensionsRequest{}, Status: http.StatusOK, Response: &types.RetrieveStudentSuspensionsResponse{}},
+
+	{Method: http.MethodPost, Path: "/retrievefornotifications", OperationId: "RetrieveStudentRecipients", Summary: "Send a notification and retrieve its recipients", Tag: "notifications",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveStudentRecipientsRequest{}, Status: http.StatusOK, Response: &types.RetrieveCommonStudentsResponse{}},
+	{Method: http.MethodGet, Path: "/retrievefornotifications", OperationId: "RetrieveMoreStudentRecipients", Summary: "Next page of the recipients of a notification", Tag: "notifications",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveMoreStudentRecipientsRequest{}, Status: http.StatusOK, Response: &types.RetrieveCommonStudentsResponse{}},
+	{Method: http.MethodGet, Path: "/students/:email/notifications", OperationId: "RetrieveStudentNotifications", Summary: "Inbox of a student", Tag: "notifications",
+		Access: ACCESS_REGISTERED_TEACHER, Request: &types.RetrieveStudentNotificationsRequest{}, Status: http.StatusOK, Response: &types.RetrieveStudentNotificationsResponse{}},
+	{Method: http.MethodPost, Path: "/students/:email/notifications/:id/read", OperationId: "MarkNotificationAsRead", Summary: "Mark a notification of an inbox as read", Tag: "notifications",
+		Access: ACCESS_REGISTERED_TEACHER, Request: &types.MarkNotificationAsReadRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/notifications/:id/deliveries", OperationId: "RetrieveNotificationDeliveries", Summary: "Delivery status of a notification per recipient", Tag: "notifications",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveNotificationDeliveriesRequest{}, Status: http.StatusOK, Response: &types.RetrieveNotificationDeliveriesResponse{}},
+
+	{Method: http.MethodPost, Path: "/webhooks", OperationId: "CreateWebhookSubscription", Summary: "Subscribe a webhook to events", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Request: &types.CreateWebhookSubscriptionRequest{}, Status: http.StatusCreated, Response: &types.WebhookSubscriptionResponse{}},
+	{Method: http.MethodGet, Path: "/webhooks", OperationId: "RetrieveWebhookSubscriptions", Summary: "Webhook subscriptions", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Status: http.StatusOK, Response: &types.RetrieveWebhookSubscriptionsResponse{}},
+	{Method: http.MethodDelete, Path: "/webhooks/:id", OperationId: "DeleteWebhookSubscription", Summary: "Delete a webhook subscription", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Request: &types.DeleteWebhookSubscriptionRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/webhooks/:id/deliveries", OperationId: "RetrieveWebhookDeliveries", Summary: "Delivery log of a webhook subscription", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Request: &types.RetrieveWebhookDeliveriesRequest{}, Status: http.StatusOK, Response: &types.RetrieveWebhookDeliveriesResponse{}},
+
+	{Method: http.MethodPost, Path: "/import", OperationId: "ImportRoster", Summary: "Import a CSV roster, invalid lines are reported with a 400 and this response", Tag: "roster",
+		Access: ACCESS_ADMIN, Request: &types.ImportRosterRequest{}, Multipart: true, Status: http.StatusOK, Response: &types.ImportRosterResponse{}},
+	{Method: http.MethodGet, Path: "/export", OperationId: "ExportRoster", Summary: "Export the roster", Tag: "roster",
+		Access: ACCESS_ADMIN, Request: &types.ExportRosterRequest{}, Status: http.StatusOK, Response: &types.ExportedRoster{},
+		ContentTypes: []string{CONTENT_TYPE_JSON, CONTENT_TYPE_CSV, CONTENT_TYPE_NDJSON}},
+
+	{Method: http.MethodDelete, Path: "/clear", OperationId: "ClearDatabase", Summary: "Delete every record, a testing endpoint", Tag: "testing",
+		Access: ACCESS_ADMIN, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/populateteachers", OperationId: "PopulateTeachers", Summary: "Create teachers, optionally with their profiles, a testing endpoint", Tag: "testing",
+		Access: ACCESS_ADMIN, Request: &types.PopulateTeachersRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/populatestudents", OperationId: "PopulateStudents", Summary: "Create students, optionally with their profiles, a testing endpoint", Tag: "testing",
+		Access: ACCESS_ADMIN, Request: &types.PopulateStudentsRequest{}, Status: http.StatusNoContent},
+}
+
+// v2Endpoints are relative to the path of their version
+var v2Endpoints = []*Endpoint{
+	{Method: http.MethodGet, Path: "/students", OperationId: "RetrieveCommonStudents", Summary: "Students registered to the teachers", Tag: "registrations",
+		Access: ACCESS_ANY, Request: &types.RetrieveCommonStudentsRequest{}, Status: http.StatusOK, Response: &types.RetrieveRegisteredStudentsResponse{}},
+	{Method: http.MethodPost, Path: "/teachers/:email/students", OperationId: "RegisterStudentsToTeacher", Summary: "Register students to a teacher", Tag: "registrations",
+		Access: ACCESS_TEACHER, Request: &types.RegisterStudentsToTeacherV2Request{}, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/teachers/:email/students", OperationId: "UnregisterStudentsFromTeacher", Summary: "Unregister students from a teacher", Tag: "registrations",
+		Access: ACCESS_TEACHER, Request: &types.UnregisterStudentsFromTeacherV2Request{}, Status: http.StatusOK, Response: &types.UnregisterStudentsFromTeacherResponse{}},
+
+	{Method: http.MethodGet, Path: "/students/:email/suspensions", OperationId: "RetrieveStudentSuspensions", Summary: "Suspension history of a student", Tag: "suspensions",
+		Access: ACCESS_ANY, Request: &types.RetrieveStudentSuspensionsRequest{}, Status: http.StatusOK, Response: &types.RetrieveStudentSuspensionsResponse{}},
+	{Method: http.MethodPost, Path: "/students/:email/suspension", OperationId: "SuspendStudent", Summary: "Suspend a student", Tag: "suspensions",
+		Access: ACCESS_ADMIN, Request: &types.StudentSuspensionV2Request{}, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/students/:email/suspension", OperationId: "UnsuspendStudent", Summary: "Lift the suspension of a student", Tag: "suspensions",
+		Access: ACCESS_ADMIN, Request: &types.StudentUnsuspensionV2Request{}, Status: http.StatusNoContent},
+
+	{Method: http.MethodPost, Path: "/teachers/:email/notifications", OperationId: "SendNotification", Summary: "Send a notification and retrieve its first recipients", Tag: "notifications",
+		Access: ACCESS_TEACHER, Request: &types.SendNotificationV2Request{}, Status: http.StatusCreated, Response: &types.RetrieveNotificationRecipientsResponse{}},
+	{Method: http.MethodGet, Path: "/notifications/:id/recipients", OperationId: "RetrieveNotificationRecipients", Summary: "Recipients of a notification", Tag: "notifications",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveNotificationRecipientsV2Request{}, Status: http.StatusOK, Response: &types.RetrieveNotificationRecipientsResponse{}},
+	{Method: http.MethodGet, Path: "/notifications/:id/deliveries", OperationId: "RetrieveNotificationDeliveries", Summary: "Delivery status of a notification per recipient", Tag: "notifications",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveNotificationDeliveriesRequest{}, Status: http.StatusOK, Response: &types.RetrieveNotificationDeliveriesResponse{}},
+	{Method: http.MethodGet, Path: "/students/:email/notifications", OperationId: "RetrieveStudentNotifications", Summary: "Inbox of a student", Tag: "notifications",
+		Access: ACCESS_REGISTERED_TEACHER, Request: &types.RetrieveStudentNotificationsRequest{}, Status: http.StatusOK, Response: &types.RetrieveStudentNotificationsResponse{}},
+	{Method: http.MethodPost, Path: "/students/:email/notifications/:id/read", OperationId: "MarkNotificationAsRead", Summary: "Mark a notification of an inbox as read", Tag: "notifications",
+		Access: ACCESS_REGISTERED_TEACHER, Request: &types.MarkNotificationAsReadRequest{}, Status: http.StatusNoContent},
+
+	{Method: http.MethodPost, Path: "/webhooks", OperationId: "CreateWebhookSubscription", Summary: "Subscribe a webhook to events", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Request: &types.CreateWebhookSubscriptionRequest{}, Status: http.StatusCreated, Response: &types.WebhookSubscriptionResponse{}},
+	{Method: http.MethodGet, Path: "/webhooks", OperationId: "RetrieveWebhookSubscriptions", Summary: "Webhook subscriptions", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Status: http.StatusOK, Response: &types.RetrieveWebhookSubscriptionsResponse{}},
+	{Method: http.MethodDelete, Path: "/webhooks/:id", OperationId: "DeleteWebhookSubscription", Summary: "Delete a webhook subscription", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Request: &types.DeleteWebhookSubscriptionRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/webhooks/:id/deliveries", OperationId: "RetrieveWebhookDeliveries", Summary: "Delivery log of a webhook subscription", Tag: "webhooks",
+		Access: ACCESS_ADMIN, Request: &types.RetrieveWebhookDeliveriesRequest{}, Status: http.StatusOK, Response: &types.RetrieveWebhookDeliveriesResponse{}},
+
+	{Method: http.MethodPost, Path: "/roster", OperationId: "ImportRoster", Summary: "Import a CSV roster, invalid lines are reported with a 400 and this response", Tag: "roster",
+		Access: ACCESS_ADMIN, Request: &types.ImportRosterRequest{}, Multipart: true, Status: http.StatusOK, Response: &types.ImportRosterResponse{}},
+	{Method: http.MethodGet, Path: "/roster", OperationId: "ExportRoster", Summary: "Export the roster", Tag: "roster",
+		Access: ACCESS_ADMIN, Request: &types.ExportRosterRequest{}, Status: http.StatusOK, Response: &types.ExportedRoster{},
+		ContentTypes: []string{CONTENT_TYPE_JSON, CONTENT_TYPE_CSV, CONTENT_TYPE_NDJSON}},
+
+	{Method: http.MethodDelete, Path: "/database", OperationId: "ClearDatabase", Summary: "Delete every record, a testing endpoint", Tag: "testing",
+		Access: ACCESS_ADMIN, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/teachers", OperationId: "PopulateTeachers", Summary: "Create teachers, optionally with their profiles, a testing endpoint", Tag: "testing",
+		Access: ACCESS_ADMIN, Request: &types.PopulateTeachersRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/students", OperationId: "PopulateStudents", Summary: "Create students, optionally with their profiles, a testing endpoint", Tag: "testing",
+		Access: ACCESS_ADMIN, Request: &types.PopulateStudentsRequest{}, Status: http.StatusNoContent},
+}
+
+// resourceEndpoints are the courses, assignments, gradebooks, attendance and profiles endpoints, which are resource
+// oriented and served by every version, relative to its path
+var resourceEndpoints = []*Endpoint{
+	{Method: http.MethodGet, Path: "/courses", OperationId: "RetrieveCourses", Summary: "Courses, without their sections", Tag: "courses",
+		Access: ACCESS_ANY, Status: http.StatusOK, Response: &types.RetrieveCoursesResponse{}},
+	{Method: http.MethodGet, Path: "/courses/:code", OperationId: "RetrieveCourse", Summary: "A course and the teachers and students of its sections", Tag: "courses",
+		Access: ACCESS_ANY, Request: &types.RetrieveCourseRequest{}, Status: http.StatusOK, Response: &types.CourseResponse{}},
+	{Method: http.MethodPut, Path: "/courses/:code", OperationId: "SaveCourse", Summary: "Create or rename a course", Tag: "courses",
+		Access: ACCESS_ADMIN, Request: &types.SaveCourseRequest{}, Status: http.StatusOK, Response: &types.CourseResponse{}},
+	{Method: http.MethodGet, Path: "/courses/:code/sections/:section", OperationId: "RetrieveSection", Summary: "The teachers and students of a section", Tag: "courses",
+		Access: ACCESS_ANY, Request: &types.RetrieveSectionRequest{}, Status: http.StatusOK, Response: &types.SectionResponse{}},
+	{Method: http.MethodPut, Path: "/courses/:code/sections/:section", OperationId: "SaveSection", Summary: "Create a section and assign teachers to it", Tag: "courses",
+		Access: ACCESS_ADMIN, Request: &types.SaveSectionRequest{}, Status: http.StatusOK, Response: &types.SectionResponse{}},
+	{Method: http.MethodPost, Path: "/courses/:code/sections/:section/students", OperationId: "EnrollStudents", Summary: "Enroll students into a section", Tag: "courses",
+		Access: ACCESS_ADMIN, Request: &types.EnrollStudentsRequest{}, Status: http.StatusNoContent},
+
+	{Method: http.MethodPost, Path: "/teachers/:email/assignments", OperationId: "CreateAssignment", Summary: "Set an assignment to the students of a teacher", Tag: "assignments",
+		Access: ACCESS_TEACHER, Request: &types.CreateAssignmentRequest{}, Status: http.StatusCreated, Response: &types.AssignmentResponse{}},
+	{Method: http.MethodGet, Path: "/teachers/:email/assignments", OperationId: "RetrieveTeacherAssignments", Summary: "Assignments of a teacher, the earliest due first", Tag: "assignments",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveTeacherAssignmentsRequest{}, Status: http.StatusOK, Response: &types.RetrieveAssignmentsResponse{}},
+	{Method: http.MethodGet, Path: "/teachers/:email/submissions", OperationId: "RetrieveTeacherSubmissions", Summary: "Work of the students of a teacher, such as the pending or late work", Tag: "assignments",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveTeacherSubmissionsRequest{}, Status: http.StatusOK, Response: &types.RetrieveSubmissionsResponse{}},
+	{Method: http.MethodGet, Path: "/assignments/:id", OperationId: "RetrieveAssignment", Summary: "An assignment", Tag: "assignments",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveAssignmentRequest{}, Status: http.StatusOK, Response: &types.AssignmentResponse{}},
+	{Method: http.MethodGet, Path: "/assignments/:id/submissions", OperationId: "RetrieveAssignmentSubmissions", Summary: "Work of the students on an assignment", Tag: "assignments",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveAssignmentSubmissionsRequest{}, Status: http.StatusOK, Response: &types.RetrieveSubmissionsResponse{}},
+	{Method: http.MethodPut, Path: "/assignments/:id/submissions/:email", OperationId: "SubmitAssignment", Summary: "Submit or resubmit the work of a student", Tag: "assignments",
+		Access: ACCESS_TEACHER, Request: &types.SubmitAssignmentRequest{}, Status: http.StatusOK, Response: &types.SubmissionResponse{}},
+
+	{Method: http.MethodPut, Path: "/teachers/:email/categories/:name", OperationId: "SaveGradeCategory", Summary: "Create a weighted grade category or change its weight", Tag: "gradebooks",
+		Access: ACCESS_TEACHER, Request: &types.SaveGradeCategoryRequest{}, Status: http.StatusOK, Response: &types.GradeCategoryResponse{}},
+	{Method: http.MethodGet, Path: "/teachers/:email/categories", OperationId: "RetrieveGradeCategories", Summary: "Grade categories of a teacher", Tag: "gradebooks",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveGradeCategoriesRequest{}, Status: http.StatusOK, Response: &types.RetrieveGradeCategoriesResponse{}},
+	{Method: http.MethodPost, Path: "/teachers/:email/students/:student/grades", OperationId: "GradeStudent", Summary: "Grade a student registered to a teacher", Tag: "gradebooks",
+		Access: ACCESS_TEACHER, Request: &types.GradeStudentRequest{}, Status: http.StatusCreated, Response: &types.GradeResponse{}},
+	{Method: http.MethodGet, Path: "/teachers/:email/students/:student/gradebook", OperationId: "RetrieveGradebook", Summary: "Grades and averages of a student with a teacher", Tag: "gradebooks",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveGradebookRequest{}, Status: http.StatusOK, Response: &types.GradebookResponse{}},
+	{Method: http.MethodGet, Path: "/students/:email/reportcard", OperationId: "RetrieveReportCard", Summary: "Averages of a student with every teacher", Tag: "gradebooks",
+		Access: ACCESS_REGISTERED_TEACHER, Request: &types.RetrieveReportCardRequest{}, Status: http.StatusOK, Response: &types.ReportCardResponse{}},
+
+	{Method: http.MethodGet, Path: "/teachers/:email/attendance", OperationId: "RetrieveAttendanceRates", Summary: "Attendance rates of the students of a teacher, optionally below a threshold", Tag: "attendance",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveAttendanceRatesRequest{}, Status: http.StatusOK, Response: &types.RetrieveAttendanceRatesResponse{}},
+	{Method: http.MethodPut, Path: "/teachers/:email/attendance/:date", OperationId: "RecordRollCall", Summary: "Record the attendance of students on a date", Tag: "attendance",
+		Access: ACCESS_TEACHER, Request: &types.RecordRollCallRequest{}, Status: http.StatusOK, Response: &types.RollCallResponse{}},
+	{Method: http.MethodGet, Path: "/teachers/:email/attendance/:date", OperationId: "RetrieveRollCall", Summary: "Attendance of the students of a teacher on a date", Tag: "attendance",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveRollCallRequest{}, Status: http.StatusOK, Response: &types.RollCallResponse{}},
+	{Method: http.MethodGet, Path: "/teachers/:email/students/:student/attendance", OperationId: "RetrieveStudentAttendance", Summary: "Attendance and attendance rate of a student with a teacher", Tag: "attendance",
+		Access: ACCESS_TEACHER, Request: &types.RetrieveStudentAttendanceRequest{}, Status: http.StatusOK, Response: &types.StudentAttendanceResponse{}},
+
+	{Method: http.MethodGet, Path: "/students/:email", OperationId: "RetrieveStudent", Summary: "A student and their profile", Tag: "profiles",
+		Access: ACCESS_REGISTERED_TEACHER, Request: &types.RetrieveProfileRequest{}, Status: http.StatusOK, Response: &types.StudentResponse{}},
+	{Method: http.MethodPatch, Path: "/students/:email", OperationId: "UpdateStudent", Summary: "Change fields of the profile of a student", Tag: "profiles",
+		Access: ACCESS_ADMIN, Request: &types.UpdateProfileRequest{}, Status: http.StatusOK, Response: &types.StudentResponse{}},
+	{Method: http.MethodDelete, Path: "/students/:email", OperationId: "DeleteStudent", Summary: "Delete a student and everything recorded about them", Tag: "profiles",
+		Access: ACCESS_ADMIN, Request: &types.DeleteProfileRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/teachers/:email", OperationId: "RetrieveTeacher", Summary: "A teacher and their profile", Tag: "profiles",
+		Access: ACCESS_ANY, Request: &types.RetrieveProfileRequest{}, Status: http.StatusOK, Response: &types.TeacherResponse{}},
+	{Method: http.MethodPatch, Path: "/teachers/:email", OperationId: "UpdateTeacher", Summary: "Change fields of the profile of a teacher", Tag: "profiles",
+		Access: ACCESS_TEACHER, Request: &types.UpdateProfileRequest{}, Status: http.StatusOK, Response: &types.TeacherResponse{}},
+	{Method: http.MethodDelete, Path: "/teachers/:email", OperationId: "DeleteTeacher", Summary: "Delete a teacher and everything they recorded", Tag: "profiles",
+		Access: ACCESS_ADMIN, Request: &types.DeleteProfileRequest{}, Status: http.StatusNoContent},
+}
+
+// versionEndpoints copies the endpoints under the path of a version, their operation ids are suffixed to stay unique
+func versionEndpoints(path string, operationIdSuffix string, deprecated bool, endpointLists ...[]*Endpoint) []*Endpoint {
+	var versioned []*Endpoint
+	for _, endpoint := range joinEndpoints(endpointLists...) {
+		copied := *endpoint
+		copied.Path = path + endpoint.Path
+		copied.OperationId = endpoint.OperationId + operationIdSuffix
+		copied.Deprecated = deprecated
+		versioned = append(versioned, &copied)
+	}
+	return versioned
+}
+
+func joinEndpoints(endpointLists ...[]*Endpoint) []*Endpoint {
+	var joined []*Endpoint
+	for _, endpoints := range endpointLists {
+		joined = append(joined, endpoints...)
+	}
+	return joined
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"learning-management-system/types"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+var populateEntryType = reflect.TypeOf(types.PopulateEntry{})
+
+// schemaGenerator derives schemas from the types structs, so the document cannot drift from what the handlers bind
+// and return. Structs are added to the components once and referenced by name.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{schemas: map[string]*Schema{}}
+}
+
+func (generator *schemaGenerator) schemaOf(typ reflect.Type) *Schema {
+	switch typ {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}
+	case populateEntryType:
+		// a populate entry is either a bare email or an object with the profile
+		return &Schema{OneOf: []*Schema{{Type: "string", Format: "email"}, generator.componentOf(typ, "json")}}
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		schema := generator.schemaOf(typ.Elem())
+		// only scalars are nullable, pointers to structs are used for references that are always set
+		if schema.Ref == "" && typ.Elem() != fileHeaderType {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Uint, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: generator.schemaOf(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		return generator.componentOf(typ, "json")
+	}
+	return &Schema{}
+}
+
+// componentOf adds the object schema of the fields of the struct with the tag to the components, named after the
+// struct, and returns a reference to it
+func (generator *schemaGenerator) componentOf(typ reflect.Type, tag string) *Schema {
+	if _, ok := generator.schemas[typ.Name()]; !ok {
+		// the name is reserved before the fields are generated in case the struct refers to itself
+		generator.schemas[typ.Name()] = &Schema{}
+		generator.schemas[typ.Name()] = generator.objectOf(typ, tag)
+	}
+	return &Schema{Ref: "#/components/schemas/" + typ.Name()}
+}
+
+// objectOf returns the schema of the fields of the struct with the tag, the fields with binding:"required" are
+// required
+func (generator *schemaGenerator) objectOf(typ reflect.Type, tag string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for _, field := range fieldsWithTag(typ, tag) {
+		name := tagName(field, tag)
+		schema.Properties[name] = generator.schemaOf(field.Type)
+		if isRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// parametersOf returns the path parameters of the uri fields of the struct and, unless they are part of a multipart
+// body, the query parameters of its form fields
+func (generator *schemaGenerator) parametersOf(typ reflect.Type, multipartBody bool) []*Parameter {
+	var parameters []*Parameter
+
+	for _, field := range fieldsWithTag(typ, "uri") {
+		parameters = append(parameters, &Parameter{Name: tagName(field, "uri"), In: "path", Required: true, Schema: generator.schemaOf(field.Type)})
+	}
+
+	if multipartBody {
+		return parameters
+	}
+
+	for _, field := range fieldsWithTag(typ, "form") {
+		parameters = append(parameters, &Parameter{Name: tagName(field, "form"), In: "query", Required: isRequired(field), Schema: generator.schemaOf(field.Type)})
+	}
+	return parameters
+}
+
+func fieldsWithTag(typ reflect.Type, tag string) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		if name := tagName(typ.Field(i), tag); name != "" && name != "-" {
+			fields = append(fields, typ.Field(i))
+		}
+	}
+	return fields
+}
+
+func tagName(field reflect.StructField, tag string) string {
+	return strings.Split(field.Tag.Get(tag), ",")[0]
+}
+
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package problems
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const DEFAULT_LOCALE = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalog holds the message templates of every bundled locale, keyed by the code of the problem and an optional
+// variant, e.g. STUDENT_NOT_FOUND or STUDENT_NOT_FOUND.plural
+var catalog = loadCatalog()
+
+func loadCatalog() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := map[string]map[string]string{}
+	for _, file := range files {
+		content, err := localeFiles.ReadFile("locales/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		messages := map[string]string{}
+		if err := json.Unmarshal(content, &messages); err != nil {
+			panic(fmt.Errorf("could not parse locale file %s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return loaded
+}
+
+// Locales returns the bundled locales in alphabetical order
+func Locales() []string {
+	locales := make([]string, 0, len(catalog))
+	for locale := range catalog {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Messages returns the message templates of a bundled locale
+func Messages(locale string) map[string]string {
+	return catalog[locale]
+}
+
+// NegotiateLocale picks the bundled locale with the highest quality in an Accept-Language header, a region such as
+// fr-CA falls back to its language. DEFAULT_LOCALE is picked when no bundled locale is accepted.
+func NegotiateLocale(acceptLanguage string) string {
+	negotiated, bestQuality := DEFAULT_LOCALE, 0.0
+
+	for _, languageRange := range strings.Split(acceptLanguage, ",") {
+		parameters := strings.Split(languageRange, ";")
+		tag := strings.ToLower(strings.TrimSpace(parameters[0]))
+		quality := 1.0
+
+		for _, parameter := range parameters[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if strings.HasPrefix(parameter, "q=") {
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(parameter, "q="), 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		language := strings.SplitN(tag, "-", 2)[0]
+		if language == "*" {
+			language = DEFAULT_LOCALE
+		}
+		if _, ok := catalog[language]; ok && quality > bestQuality {
+			negotiated, bestQuality = language, quality
+		}
+	}
+
+	return negotiated
+}
+
+func format(locale string, key string, args []interface{}) (string, bool) {
+	template, ok := catalog[locale][key]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(template, args...), true
+}
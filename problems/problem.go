@@ -0,0 +1,150 @@
+package problems
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Codes identify each kind of problem, clients should rely on them rather than on the details which are meant for
+// people and may change
+const (
+	BAD_REQUEST            = "BAD_REQUEST"
+	INVALID_CONTENT_TYPE   = "INVALID_CONTENT_TYPE"
+	MISSING_FIELD          = "MISSING_FIELD"
+	INVALID_FIELD          = "INVALID_FIELD"
+	INVALID_EMAIL          = "INVALID_EMAIL"
+	INVALID_LIMIT          = "INVALID_LIMIT"
+	INVALID_CURSOR         = "INVALID_CURSOR"
+	INVALID_MODE           = "INVALID_MODE"
+	INVALID_FORMAT         = "INVALID_FORMAT"
+	INVALID_CSV            = "INVALID_CSV"
+	INVALID_WEBHOOK_URL    = "INVALID_WEBHOOK_URL"
+	INVALID_EVENT          = "INVALID_EVENT"
+	INVALID_STATUS         = "INVALID_STATUS"
+	STUDENT_NOT_FOUND      = "STUDENT_NOT_FOUND"
+	TEACHER_NOT_FOUND      = "TEACHER_NOT_FOUND"
+	NOTIFICATION_NOT_FOUND = "NOTIFICATION_NOT_FOUND"
+	WEBHOOK_NOT_FOUND      = "WEBHOOK_NOT_FOUND"
+	COURSE_NOT_FOUND       = "COURSE_NOT_FOUND"
+	SECTION_NOT_FOUND      = "SECTION_NOT_FOUND"
+	ASSIGNMENT_NOT_FOUND   = "ASSIGNMENT_NOT_FOUND"
+	CATEGORY_NOT_FOUND     = "CATEGORY_NOT_FOUND"
+	REGISTRATION_NOT_FOUND = "REGISTRATION_NOT_FOUND"
+	UNAUTHORIZED           = "UNAUTHORIZED"
+	FORBIDDEN              = "FORBIDDEN"
+	INTERNAL_ERROR         = "INTERNAL_ERROR"
+)
+
+// Problem is an error that tells the client what is wrong with its request: a stable code, the field of the request
+// at fault if there is one, the emails at fault if there are any, and a detail for people
+type Problem struct {
+	Code   string
+	Field  string
+	Emails []string
+	Detail string
+
+	// key and args render the detail in other locales, a problem without a key is only described in English
+	key  string
+	args []interface{}
+
+	// legacy is the problem v1 reported in its message before problems gathered every fault, nil when it is this one
+	legacy *Problem
+}
+
+// New returns a problem whose detail is not in the catalog, such as errors of libraries
+func New(code string, detail string) *Problem {
+	return &Problem{Code: code, Detail: detail}
+}
+
+// Newf returns a problem whose detail is the catalog message of the code and variant, an empty variant being the
+// default message of the code, formatted with the args
+func Newf(code string, variant string, args ...interface{}) *Problem {
+	key := code
+	if variant != "" {
+		key += "." + variant
+	}
+
+	detail, ok := format(DEFAULT_LOCALE, key, args)
+	if !ok {
+		panic(fmt.Sprintf("The message %s is not in the %s catalog", key, DEFAULT_LOCALE))
+	}
+	return &Problem{Code: code, Detail: detail, key: key, args: args}
+}
+
+// Localize returns the detail in the locale, or in English if the locale has no message for the problem
+func (problem *Problem) Localize(locale string) string {
+	if detail, ok := format(locale, problem.key, problem.args); ok {
+		return detail
+	}
+	return problem.Detail
+}
+
+// LocalizeMessage returns the message of the problem for the clients of v1 in the locale
+func (problem *Problem) LocalizeMessage(locale string) string {
+	if problem.legacy != nil {
+		return problem.legacy.Localize(locale)
+	}
+	return problem.Localize(locale)
+}
+
+// WithLegacy keeps the problem v1 reported in its message instead of this one
+func (problem *Problem) WithLegacy(legacy *Problem) *Problem {
+	problem.legacy = legacy
+	return problem
+}
+
+func (problem *Problem) WithField(field string) *Problem {
+	problem.Field = field
+	return problem
+}
+
+func (problem *Problem) WithEmails(emails ...string) *Problem {
+	problem.Emails = emails
+	return problem
+}
+
+func (problem *Problem) Error() string {
+	return problem.Detail
+}
+
+// List holds every problem found in a request, so that they can all be reported at once
+type List []*Problem
+
+func (list List) Error() string {
+	details := make([]string, 0, len(list))
+	for _, problem := range list {
+		details = append(details, problem.Detail)
+	}
+	return strings.Join(details, "; ")
+}
+
+// Collect gathers the problems of the errors into a single error, skipping nil errors, or returns nil if every error
+// is nil
+func Collect(errs ...error) error {
+	list := List{}
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, From(err, BAD_REQUEST)...)
+		}
+	}
+
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// From returns the problems of an error, an error that is not a problem becomes a problem with the code
+func From(err error, code string) List {
+	var list List
+	if errors.As(err, &list) {
+		return list
+	}
+
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return List{problem}
+	}
+	return List{New(code, err.Error())}
+}
@@ -0,0 +1,82 @@
+package problems
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[a-z]`)
+
+func TestLocalesTranslateEveryMessage(t *testing.T) {
+	english := Messages(DEFAULT_LOCALE)
+
+	for _, locale := range Locales() {
+		messages := Messages(locale)
+		if len(messages) != len(english) {
+			t.Errorf("locale %s has %d messages, expected %d", locale, len(messages), len(english))
+		}
+
+		for key, template := range english {
+			translated, ok := messages[key]
+			if !ok {
+				t.Errorf("locale %s has no message %s", locale, key)
+				continue
+			}
+
+			// the args are formatted in order, so every translation must use the same verbs in the same order
+			if verbs, translatedVerbs := verbPattern.FindAllString(template, -1), verbPattern.FindAllString(translated, -1); !equalStrings(verbs, translatedVerbs) {
+				t.Errorf("locale %s formats %s with %v, expected %v", locale, key, translatedVerbs, verbs)
+			}
+		}
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	cases := map[string]string{
+		"":                            "en",
+		"fr":                          "fr",
+		"fr-CA":                       "fr",
+		"FR-ca, en;q=0.8":             "fr",
+		"en-GB, fr;q=0.9":             "en",
+		"de, fr;q=0.5, en;q=0.3":      "fr",
+		"de, ja":                      "en",
+		"*":                           "en",
+		"fr;q=0, en;q=0.1":            "en",
+		"en;q=0.2, fr;q=0.7, *;q=0.1": "fr",
+		"fr;q=invalid":                "fr",
+	}
+
+	for acceptLanguage, expected := range cases {
+		if locale := NegotiateLocale(acceptLanguage); locale != expected {
+			t.Errorf("Accept-Language %q negotiated %s, expected %s", acceptLanguage, locale, expected)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	problem := Newf(STUDENT_NOT_FOUND, "plural", "test1@gmail.com, test2@gmail.com")
+
+	if problem.Detail != "Students with emails test1@gmail.com, test2@gmail.com do not exist in the database" {
+		t.Errorf("wrong detail %s", problem.Detail)
+	}
+	if detail := problem.Localize("fr"); detail != "Les élèves avec les e-mails test1@gmail.com, test2@gmail.com n'existent pas dans la base de données" {
+		t.Errorf("wrong french detail %s", detail)
+	}
+
+	// problems outside the catalog keep their detail
+	if detail := New(BAD_REQUEST, "unexpected EOF").Localize("fr"); detail != "unexpected EOF" {
+		t.Errorf("wrong detail %s", detail)
+	}
+}
+
+func equalStrings(expected []string, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			return false
+		}
+	}
+	return true
+}
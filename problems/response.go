@@ -0,0 +1,53 @@
+package problems
+
+import (
+	"github.com/gin-gonic/gin"
+	"learning-management-system/types"
+	"net/http"
+)
+
+const CONTENT_TYPE = "application/problem+json"
+
+// withMessageKey marks the requests whose problems keep the message of the error bodies that predate problems
+const withMessageKey = "problems.withMessage"
+
+// WithMessage makes the problems of the routes it handles keep the message member of the error bodies that predate
+// problems, as an extension member describing the first problem
+func WithMessage() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Set(withMessageKey, true)
+		context.Next()
+	}
+}
+
+// Respond aborts the request with an RFC 7807 problem+json response listing every problem of the error. The first
+// problem is also described by the top level members, an error that is not a problem is reported with the code.
+// Details are in the locale negotiated from the Accept-Language header.
+func Respond(context *gin.Context, status int, code string, err error) {
+	list := From(err, code)
+	locale := NegotiateLocale(context.GetHeader("Accept-Language"))
+
+	response := &types.ProblemResponse{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   list[0].Code,
+		Detail: list[0].Localize(locale),
+		Errors: make([]*types.ProblemDetailResponse, 0, len(list)),
+	}
+	if context.GetBool(withMessageKey) {
+		response.Message = list[0].LocalizeMessage(locale)
+	}
+	for _, problem := range list {
+		response.Errors = append(response.Errors, &types.ProblemDetailResponse{
+			Code:   problem.Code,
+			Field:  problem.Field,
+			Emails: problem.Emails,
+			Detail: problem.Localize(locale),
+		})
+	}
+
+	context.Header("Content-Type", CONTENT_TYPE)
+	context.Header("Content-Language", locale)
+	context.AbortWithStatusJSON(status, response)
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"learning-management-system/models"
+)
+
+type AssignmentRepo struct{}
+
+func NewAssignmentRepo() *AssignmentRepo {
+	return &AssignmentRepo{}
+}
+
+func (*AssignmentRepo) CreateAssignment(assignment *models.Assignment, db *gorm.DB) (err error) {
+	err = db.Omit("Teacher").Create(assignment).Error
+	return err
+}
+
+func (*AssignmentRepo) GetAssignmentById(assignmentId uint, db *gorm.DB) (assignment *models.Assignment, err error) {
+	assignment = &models.Assignment{}
+	err = db.Table("assignments").Where("id = ?", assignmentId).Find(&assignment).Error
+	if assignment.ID == 0 {
+		return nil, err
+	}
+	return assignment, err
+}
+
+// GetAssignmentsByTeacherEmail returns the assignments of the teacher, the earliest due first
+func (*AssignmentRepo) GetAssignmentsByTeacherEmail(teacherEmail string, db *gorm.DB) (assignments []*models.Assignment, err error) {
+	assignments = make([]*models.Assignment, 0)
+	err = db.Table("assignments").Where("teacher_email = ?", teacherEmail).Order("due_at, id").Find(&assignments).Error
+	return assignments, err
+}
+
+// SaveSubmission creates the submission of the student, or replaces the one they submitted before
+func (*AssignmentRepo) SaveSubmission(submission *models.Submission, db *gorm.DB) (err error) {
+	err = db.Omit("Assignment", "Student").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "assignment_id"}, {Name: "student_email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "file_reference", "submitted_at"}),
+	}).Create(submission).Error
+	return err
+}
+
+// GetSubmissionsByAssignmentIds returns the submissions for the assignments sorted by assignment and student
+func (*AssignmentRepo) GetSubmissionsByAssignmentIds(assignmentIds []uint, db *gorm.DB) (submissions []*models.Submission, err error) {
+	submissions = make([]*models.Submission, 0)
+	if len(assignmentIds) == 0 {
+		return submissions, nil
+	}
+	err = db.Table("submissions").Where("assignment_id IN ?", assignmentIds).Order("assignment_id, student_email").Find(&submissions).Error
+	return submissions, err
+}
+
+// DeleteAllAssignments deletes the assignments together with their submissions
+func (*AssignmentRepo) DeleteAllAssignments(db *gorm.DB) error {
+	if err := db.Exec("DELETE FROM submissions").Error; err != nil {
+		return err
+	}
+	return db.Exec("DELETE FROM assignments").Error
+}
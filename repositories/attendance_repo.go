@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"learning-management-system/models"
+)
+
+type AttendanceRepo struct{}
+
+func NewAttendanceRepo() *AttendanceRepo {
+	return &AttendanceRepo{}
+}
+
+// SaveAttendances records the attendances, replacing the status already recorded for a student on the same date
+func (*AttendanceRepo) SaveAttendances(attendances []*models.Attendance, db *gorm.DB) (err error) {
+	if len(attendances) == 0 {
+		return nil
+	}
+	err = db.Omit("Teacher", "Student").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "teacher_email"}, {Name: "student_email"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "recorded_at"}),
+	}).Create(&attendances).Error
+	return err
+}
+
+func (*AttendanceRepo) GetAttendancesByDate(teacherEmail string, date string, db *gorm.DB) (attendances []*models.Attendance, err error) {
+	attendances = make([]*models.Attendance, 0)
+	err = db.Table("attendances").Where("teacher_email = ? AND date = ?", teacherEmail, date).Order("student_email").Find(&attendances).Error
+	return attendances, err
+}
+
+// GetAttendancesBetween returns the attendances the teacher recorded within the dates, sorted by student and then date
+func (*AttendanceRepo) GetAttendancesBetween(teacherEmail string, fromDate string, toDate string, db *gorm.DB) (attendances []*models.Attendance, err error) {
+	attendances = make([]*models.Attendance, 0)
+	err = db.Table("attendances").Where("teacher_email = ?", teacherEmail).Scopes(dateRange(fromDate, toDate)).
+		Order("student_email").Order("date").Find(&attendances).Error
+	return attendances, err
+}
+
+// GetStudentAttendancesBetween returns the attendances the teacher recorded for the student within the dates, sorted
+// by date
+func (*AttendanceRepo) GetStudentAttendancesBetween(teacherEmail string, studentEmail string, fromDate string, toDate string, db *gorm.DB) (attendances []*models.Attendance, err error) {
+	attendances = make([]*models.Attendance, 0)
+	err = db.Table("attendances").Where("teacher_email = ? AND student_email = ?", teacherEmail, studentEmail).
+		Scopes(dateRange(fromDate, toDate)).Order("date").Find(&attendances).Error
+	return attendances, err
+}
+
+func (*AttendanceRepo) DeleteAllAttendances(db *gorm.DB) error {
+	return db.Exec("DELETE FROM attendances").Error
+}
+
+// dateRange keeps the rows dated from fromDate to toDate inclusive, an empty date leaves its end of the range open
+func dateRange(fromDate string, toDate string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if fromDate != "" {
+			db = db.Where("date >= ?", fromDate)
+		}
+		if toDate != "" {
+			db = db.Where("date <= ?", toDate)
+		}
+		return db
+	}
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+)
+
+type CourseRepo struct{}
+
+func NewCourseRepo() *CourseRepo {
+	return &CourseRepo{}
+}
+
+// SaveCourse creates the course, or renames it if it already exists
+func (*CourseRepo) SaveCourse(course *models.Course, db *gorm.DB) (err error) {
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name"}),
+	}).Create(course).Error
+	return err
+}
+
+func (*CourseRepo) GetCourseByCode(courseCode string, db *gorm.DB) (course *models.Course, err error) {
+	course = &models.Course{}
+	err = db.Table("courses").Where("code = ?", courseCode).Find(&course).Error
+	if course.Code == "" {
+		return nil, err
+	}
+	return course, err
+}
+
+func (*CourseRepo) GetAllCourses(db *gorm.DB) (courses []*models.Course, err error) {
+	courses = make([]*models.Course, 0)
+	err = db.Table("courses").Order("code").Find(&courses).Error
+	return courses, err
+}
+
+// CreateSectionIfNotExists returns the section of the course with the name, creating it if there is none
+func (repo *CourseRepo) CreateSectionIfNotExists(courseCode string, sectionName string, db *gorm.DB) (*models.Section, error) {
+	section := &models.Section{CourseCode: courseCode, Name: sectionName}
+	if err := db.Omit("Course").Clauses(clause.OnConflict{DoNothing: true}).Create(section).Error; err != nil {
+		return nil, err
+	}
+	return repo.GetSection(courseCode, sectionName, db)
+}
+
+func (*CourseRepo) GetSection(courseCode string, sectionName string, db *gorm.DB) (section *models.Section, err error) {
+	section = &models.Section{}
+	err = db.Table("sections").Where("course_code = ? AND name = ?", courseCode, sectionName).Find(&section).Error
+	if section.ID == 0 {
+		return nil, err
+	}
+	return section, err
+}
+
+func (*CourseRepo) GetSectionsByCourseCode(courseCode string, db *gorm.DB) (sections []*models.Section, err error) {
+	sections = make([]*models.Section, 0)
+	err = db.Table("sections").Where("course_code = ?", courseCode).Order("name").Find(&sections).Error
+	return sections, err
+}
+
+// GetSectionsByTeacherEmail returns the sections of the course the teacher is assigned to, sorted by name
+func (*CourseRepo) GetSectionsByTeacherEmail(teacherEmail string, courseCode string, db *gorm.DB) (sections []*models.Section, err error) {
+	sections = make([]*models.Section, 0)
+	err = db.Table("sections").Select("sections.*").
+		Joins("JOIN section_teachers ON section_teachers.section_id = sections.id").
+		Where("section_teachers.teacher_email = ? AND sections.course_code = ?", teacherEmail, courseCode).
+		Order("sections.name").Find(&sections).Error
+	return sections, err
+}
+
+func (*CourseRepo) AssignTeachersToSectionIfNotAssigned(sectionId uint, teacherEmails []string, db *gorm.DB) (err error) {
+	if len(teacherEmails) == 0 {
+		return nil
+	}
+	sectionTeachers := helpers.Map(teacherEmails, func(teacherEmail string) *models.SectionTeacher {
+		return &models.SectionTeacher{SectionID: sectionId, TeacherEmail: teacherEmail}
+	})
+	err = db.Omit("Section", "Teacher").Clauses(clause.OnConflict{DoNothing: true}).Create(&sectionTeachers).Error
+	return err
+}
+
+func (*CourseRepo) GetTeacherEmailsBySectionId(sectionId uint, db *gorm.DB) (teacherEmails []string, err error) {
+	teacherEmails = make([]string, 0)
+	err = db.Table("section_teachers").Where("section_id = ?", sectionId).Order("teacher_email").Pluck("teacher_email", &teacherEmails).Error
+	return teacherEmails, err
+}
+
+func (*CourseRepo) EnrollStudentsIfNotEnrolled(sectionId uint, studentEmails []string, db *gorm.DB) (err error) {
+	if len(studentEmails) == 0 {
+		return nil
+	}
+	enrollments := helpers.Map(studentEmails, func(studentEmail string) *models.Enrollment {
+		return &models.Enrollment{SectionID: sectionId, StudentEmail: studentEmail}
+	})
+	err = db.Omit("Section", "Student").Clauses(clause.OnConflict{DoNothing: true}).Create(&enrollments).Error
+	return err
+}
+
+func (*CourseRepo) GetStudentEmailsBySectionId(sectionId uint, db *gorm.DB) (studentEmails []string, err error) {
+	studentEmails = make([]string, 0)
+	err = db.Table("enrollments").Where("section_id = ?", sectionId).Order("student_email").Pluck("student_email", &studentEmails).Error
+	return studentEmails, err
+}
+
+// GetStudentEmailsTaughtByAtLeast returns the emails of the students enrolled in sections of the course taught by at
+// least minTeacherCount of the teachers, sorted and paged like GetStudentEmailsRegisteredToAtLeast
+func (*CourseRepo) GetStudentEmailsTaughtByAtLeast(teacherEmails []string, courseCode string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) (studentEmails []string, err error) {
+	uniqueTeacherEmails := helpers.RemoveDuplicatesInStringSlice(teacherEmails)
+	studentEmails = make([]string, 0)
+	if len(uniqueTeacherEmails) == 0 {
+		return studentEmails, nil
+	}
+
+	err = taughtStudents(db, courseCode).Where("section_teachers.teacher_email IN ?", uniqueTeacherEmails).
+		Scopes(emailPage("enrollments.student_email", afterStudentEmail, limit)).
+		Group("enrollments.student_email").Having("COUNT(DISTINCT section_teachers.teacher_email) >= ?", minTeacherCount).
+		Order("enrollments.student_email").Pluck("enrollments.student_email", &studentEmails).Error
+	return studentEmails, err
+}
+
+// GetStudentEmailsTaughtOnlyBy returns the emails of the students the teacher teaches in sections of the course but
+// none of the other teachers do, sorted and paged like GetStudentEmailsRegisteredToAtLeast
+func (*CourseRepo) GetStudentEmailsTaughtOnlyBy(teacherEmail string, otherTeacherEmails []string, courseCode string, afterStudentEmail string, limit int, db *gorm.DB) (studentEmails []string, err error) {
+	studentEmails = make([]string, 0)
+	query := taughtStudents(db, courseCode).Where("section_teachers.teacher_email = ?", teacherEmail)
+	if len(otherTeacherEmails) > 0 {
+		otherStudents := taughtStudents(db, courseCode).Select("enrollments.student_email").
+			Where("section_teachers.teacher_email IN ?", otherTeacherEmails)
+		query = query.Where("enrollments.student_email NOT IN (?)", otherStudents)
+	}
+
+	err = query.Scopes(emailPage("enrollments.student_email", afterStudentEmail, limit)).
+		Distinct("enrollments.student_email").Order("enrollments.student_email").Pluck("enrollments.student_email", &studentEmails).Error
+	return studentEmails, err
+}
+
+// DeleteAllCourses deletes the courses together with their sections, the teachers assigned to these sections and the
+// enrollments in them
+func (*CourseRepo) DeleteAllCourses(db *gorm.DB) error {
+	for _, table := range []string{"enrollments", "section_teachers", "sections", "courses"} {
+		if err := db.Exec("DELETE FROM " + table).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taughtStudents joins the enrollments in the sections of the course with the teachers of these sections
+func taughtStudents(db *gorm.DB, courseCode string) *gorm.DB {
+	return db.Table("enrollments").
+		Joins("JOIN sections ON sections.id = enrollments.section_id").
+		Joins("JOIN section_teachers ON section_teachers.section_id = enrollments.section_id").
+		Where("sections.course_code = ?", courseCode)
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"learning-management-system/models"
+)
+
+type GradebookRepo struct{}
+
+func NewGradebookRepo() *GradebookRepo {
+	return &GradebookRepo{}
+}
+
+// SaveCategory creates the category of the teacher, or changes its weight if it already exists. The id of the
+// category is set in both cases.
+func (repo *GradebookRepo) SaveCategory(category *models.GradeCategory, db *gorm.DB) error {
+	if err := db.Omit("Teacher").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "teacher_email"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weight"}),
+	}).Create(category).Error; err != nil {
+		return err
+	}
+
+	saved, err := repo.GetCategory(category.TeacherEmail, category.Name, db)
+	if err != nil {
+		return err
+	}
+	category.ID = saved.ID
+	return nil
+}
+
+func (*GradebookRepo) GetCategory(teacherEmail string, name string, db *gorm.DB) (category *models.GradeCategory, err error) {
+	category = &models.GradeCategory{}
+	err = db.Table("grade_categories").Where("teacher_email = ? AND name = ?", teacherEmail, name).Find(&category).Error
+	if category.ID == 0 {
+		return nil, err
+	}
+	return category, err
+}
+
+func (*GradebookRepo) GetCategoriesByTeacherEmail(teacherEmail string, db *gorm.DB) (categories []*models.GradeCategory, err error) {
+	categories = make([]*models.GradeCategory, 0)
+	err = db.Table("grade_categories").Where("teacher_email = ?", teacherEmail).Order("name").Find(&categories).Error
+	return categories, err
+}
+
+func (*GradebookRepo) CreateGrade(grade *models.Grade, db *gorm.DB) (err error) {
+	err = db.Omit("Teacher", "Student", "Category", "Assignment").Create(grade).Error
+	return err
+}
+
+// GetGrades returns the grades the teacher gave the student, in the order they were given
+func (*GradebookRepo) GetGrades(teacherEmail string, studentEmail string, db *gorm.DB) (grades []*models.Grade, err error) {
+	grades = make([]*models.Grade, 0)
+	err = db.Table("grades").Where("teacher_email = ? AND student_email = ?", teacherEmail, studentEmail).Order("id").Find(&grades).Error
+	return grades, err
+}
+
+// DeleteAllGrades deletes the grades together with the categories
+func (*GradebookRepo) DeleteAllGrades(db *gorm.DB) error {
+	if err := db.Exec("DELETE FROM grades").Error; err != nil {
+		return err
+	}
+	return db.Exec("DELETE FROM grade_categories").Error
+}
@@ -0,0 +1,1562 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+type registerRelationshipKey struct {
+	teacherEmail string
+	studentEmail string
+}
+
+// sectionMemberKey keys the teachers assigned to a section as well as the students enrolled in it
+type sectionMemberKey struct {
+	sectionId uint
+	email     string
+}
+
+// attendanceKey identifies the attendance of a student with a teacher on a date
+type attendanceKey struct {
+	teacherEmail string
+	studentEmail string
+	date         string
+}
+
+// InMemoryStore holds the tables shared by the in-memory repositories. Every access is guarded by a
+// read-write mutex so the repositories can be used concurrently from gin handlers. A transaction holds the mutex until
+// it ends, so that restoring its snapshot cannot wipe out the writes of other goroutines.
+type InMemoryStore struct {
+	mutex sync.RWMutex
+	// transactionDb is handed to the units of work run by Transaction, so that their calls and nested transactions
+	// can be told apart from the calls of other goroutines
+	transactionDb          *gorm.DB
+	students               map[string]models.Student
+	teachers               map[string]models.Teacher
+	registerRelationships  map[registerRelationshipKey]bool
+	studentSuspensions     []models.StudentSuspension
+	lastSuspensionId       uint
+	notifications          map[uint]models.Notification
+	lastNotificationId     uint
+	notificationInboxes    []models.NotificationRecipient
+	notificationDeliveries []models.NotificationDelivery
+	webhookSubscriptions   []models.WebhookSubscription
+	lastSubscriptionId     uint
+	webhookDeliveries      []models.WebhookDelivery
+	lastWebhookDeliveryId  uint
+	courses                map[string]models.Course
+	sections               []models.Section
+	lastSectionId          uint
+	sectionTeachers        map[sectionMemberKey]bool
+	enrollments            map[sectionMemberKey]bool
+	assignments            []models.Assignment
+	lastAssignmentId       uint
+	submissions            []models.Submission
+	gradeCategories        []models.GradeCategory
+	lastGradeCategoryId    uint
+	grades                 []models.Grade
+	lastGradeId            uint
+	attendances            map[attendanceKey]models.Attendance
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		students:              make(map[string]models.Student),
+		teachers:              make(map[string]models.Teacher),
+		registerRelationships: make(map[registerRelationshipKey]bool),
+		notifications:         make(map[uint]models.Notification),
+		courses:               make(map[string]models.Course),
+		sectionTeachers:       make(map[sectionMemberKey]bool),
+		enrollments:           make(map[sectionMemberKey]bool),
+		attendances:           make(map[attendanceKey]models.Attendance),
+		transactionDb:         &gorm.DB{},
+	}
+}
+
+// Transaction serialises units of work and restores a snapshot of the store if the function fails or panics. A
+// transaction nested in another one only restores the changes made by its own function, like a savepoint.
+func (store *InMemoryStore) Transaction(db *gorm.DB, function func(tx *gorm.DB) error) (err error) {
+	defer store.lock(db)()
+
+	snapshot := store.snapshot()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			store.restore(snapshot)
+			panic(recovered)
+		}
+	}()
+
+	if err = function(store.transactionDb); err != nil {
+		store.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+// lock takes the write lock of the store for the calls made outside of a transaction, a transaction already holds it
+// for the calls made with its db
+func (store *InMemoryStore) lock(db *gorm.DB) (unlock func()) {
+	if db == store.transactionDb {
+		return func() {}
+	}
+	store.mutex.Lock()
+	return store.mutex.Unlock
+}
+
+// rlock takes the read lock of the store like lock takes its write lock
+func (store *InMemoryStore) rlock(db *gorm.DB) (unlock func()) {
+	if db == store.transactionDb {
+		return func() {}
+	}
+	store.mutex.RLock()
+	return store.mutex.RUnlock
+}
+
+// snapshot copies the tables of the store, its caller holds the lock
+func (store *InMemoryStore) snapshot() *InMemoryStore {
+	snapshot := NewInMemoryStore()
+	for email, student := range store.students {
+		snapshot.students[email] = student
+	}
+	for email, teacher := range store.teachers {
+		snapshot.teachers[email] = teacher
+	}
+	for key := range store.registerRelationships {
+		snapshot.registerRelationships[key] = true
+	}
+	snapshot.studentSuspensions = append(snapshot.studentSuspensions, store.studentSuspensions...)
+	snapshot.lastSuspensionId = store.lastSuspensionId
+	for id, notification := range store.notifications {
+		snapshot.notifications[id] = notification
+	}
+	snapshot.lastNotificationId = store.lastNotificationId
+	snapshot.notificationInboxes = append(snapshot.notificationInboxes, store.notificationInboxes...)
+	snapshot.notificationDeliveries = append(snapshot.notificationDeliveries, store.notificationDeliveries...)
+	snapshot.webhookSubscriptions = append(snapshot.webhookSubscriptions, store.webhookSubscriptions...)
+	snapshot.lastSubscriptionId = store.lastSubscriptionId
+	snapshot.webhookDeliveries = append(snapshot.webhookDeliveries, store.webhookDeliveries...)
+	snapshot.lastWebhookDeliveryId = store.lastWebhookDeliveryId
+	for code, course := range store.courses {
+		snapshot.courses[code] = course
+	}
+	snapshot.sections = append(snapshot.sections, store.sections...)
+	snapshot.lastSectionId = store.lastSectionId
+	for key := range store.sectionTeachers {
+		snapshot.sectionTeachers[key] = true
+	}
+	snapshot.assignments = append(snapshot.assignments, store.assignments...)
+	snapshot.lastAssignmentId = store.lastAssignmentId
+	snapshot.submissions = append(snapshot.submissions, store.submissions...)
+	snapshot.gradeCategories = append(snapshot.gradeCategories, store.gradeCategories...)
+	snapshot.lastGradeCategoryId = store.lastGradeCategoryId
+	snapshot.grades = append(snapshot.grades, store.grades...)
+	snapshot.lastGradeId = store.lastGradeId
+	for key, attendance := range store.attendances {
+		snapshot.attendances[key] = attendance
+	}
+	for key := range store.enrollments {
+		snapshot.enrollments[key] = true
+	}
+	return snapshot
+}
+
+// restore puts back the tables of the snapshot, its caller holds the lock
+func (store *InMemoryStore) restore(snapshot *InMemoryStore) {
+	store.students = snapshot.students
+	store.teachers = snapshot.teachers
+	store.registerRelationships = snapshot.registerRelationships
+	store.studentSuspensions = snapshot.studentSuspensions
+	store.lastSuspensionId = snapshot.lastSuspensionId
+	store.notifications = snapshot.notifications
+	store.lastNotificationId = snapshot.lastNotificationId
+	store.notificationInboxes = snapshot.notificationInboxes
+	store.notificationDeliveries = snapshot.notificationDeliveries
+	store.webhookSubscriptions = snapshot.webhookSubscriptions
+	store.lastSubscriptionId = snapshot.lastSubscriptionId
+	store.webhookDeliveries = snapshot.webhookDeliveries
+	store.lastWebhookDeliveryId = snapshot.lastWebhookDeliveryId
+	store.courses = snapshot.courses
+	store.sections = snapshot.sections
+	store.lastSectionId = snapshot.lastSectionId
+	store.sectionTeachers = snapshot.sectionTeachers
+	store.enrollments = snapshot.enrollments
+	store.assignments = snapshot.assignments
+	store.lastAssignmentId = snapshot.lastAssignmentId
+	store.submissions = snapshot.submissions
+	store.gradeCategories = snapshot.gradeCategories
+	store.lastGradeCategoryId = snapshot.lastGradeCategoryId
+	store.grades = snapshot.grades
+	store.lastGradeId = snapshot.lastGradeId
+	store.attendances = snapshot.attendances
+}
+
+type InMemoryStudentRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryStudentRepo) CreateStudentsIfNotExist(studentEmails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, email := range studentEmails {
+		if _, ok := repo.store.students[email]; !ok {
+			repo.store.students[email] = models.Student{Email: email, IsSuspended: false}
+		}
+	}
+	return nil
+}
+
+// UpdateStudent mirrors gorm's Updates by only applying non-zero fields
+func (repo *InMemoryStudentRepo) UpdateStudent(studentToUpdate *models.Student, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	student, ok := repo.store.students[studentToUpdate.Email]
+	if !ok {
+		return nil
+	}
+	if studentToUpdate.IsSuspended {
+		student.IsSuspended = true
+	}
+	repo.store.students[student.Email] = student
+	return nil
+}
+
+func (repo *InMemoryStudentRepo) UpdateStudentSuspensionStatus(studentEmail string, isSuspended bool, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	if student, ok := repo.store.students[studentEmail]; ok {
+		student.IsSuspended = isSuspended
+		repo.store.students[studentEmail] = student
+	}
+	return nil
+}
+
+func (repo *InMemoryStudentRepo) DeleteAllStudents(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.students = make(map[string]models.Student)
+	return nil
+}
+
+func (repo *InMemoryStudentRepo) GetStudentsByEmails(studentEmails []string, db *gorm.DB) ([]*models.Student, error) {
+	defer repo.store.rlock(db)()
+
+	students := make([]*models.Student, 0)
+	for _, email := range sortedUniqueStrings(studentEmails) {
+		if student, ok := repo.store.students[email]; ok {
+			students = append(students, &student)
+		}
+	}
+	return students, nil
+}
+
+// GetStudentsAfterEmail returns at most limit students sorted by email, starting after afterEmail
+func (repo *InMemoryStudentRepo) GetStudentsAfterEmail(afterEmail string, limit int, db *gorm.DB) ([]*models.Student, error) {
+	defer repo.store.rlock(db)()
+
+	emails := make([]string, 0, len(repo.store.students))
+	for email := range repo.store.students {
+		emails = append(emails, email)
+	}
+
+	students := make([]*models.Student, 0)
+	for _, email := range pageOfEmails(emails, afterEmail, limit) {
+		student := repo.store.students[email]
+		students = append(students, &student)
+	}
+	return students, nil
+}
+
+func (repo *InMemoryStudentRepo) GetStudentByEmail(studentEmail string, db *gorm.DB) (*models.Student, error) {
+	defer repo.store.rlock(db)()
+
+	if student, ok := repo.store.students[studentEmail]; ok {
+		return &student, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryStudentRepo) UpdateStudentProfile(studentEmail string, profile *models.Profile, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	if student, ok := repo.store.students[studentEmail]; ok {
+		student.Profile = *profile
+		repo.store.students[studentEmail] = student
+	}
+	return nil
+}
+
+// DeleteStudent deletes the student together with everything recorded for them, like the sql repository
+func (repo *InMemoryStudentRepo) DeleteStudent(studentEmail string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	store := repo.store
+	store.notificationDeliveries = helpers.Filter(store.notificationDeliveries, func(delivery models.NotificationDelivery) bool {
+		return delivery.StudentEmail != studentEmail
+	})
+	store.notificationInboxes = helpers.Filter(store.notificationInboxes, func(recipient models.NotificationRecipient) bool {
+		return recipient.StudentEmail != studentEmail
+	})
+	store.studentSuspensions = helpers.Filter(store.studentSuspensions, func(suspension models.StudentSuspension) bool {
+		return suspension.StudentEmail != studentEmail
+	})
+	for key := range store.registerRelationships {
+		if key.studentEmail == studentEmail {
+			delete(store.registerRelationships, key)
+		}
+	}
+	for key := range store.enrollments {
+		if key.email == studentEmail {
+			delete(store.enrollments, key)
+		}
+	}
+	store.submissions = helpers.Filter(store.submissions, func(submission models.Submission) bool {
+		return submission.StudentEmail != studentEmail
+	})
+	store.grades = helpers.Filter(store.grades, func(grade models.Grade) bool {
+		return grade.StudentEmail != studentEmail
+	})
+	for key := range store.attendances {
+		if key.studentEmail == studentEmail {
+			delete(store.attendances, key)
+		}
+	}
+	delete(store.students, studentEmail)
+	return nil
+}
+
+type InMemoryTeacherRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryTeacherRepo) CreateTeachersIfNotExist(emails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, email := range emails {
+		if _, ok := repo.store.teachers[email]; !ok {
+			repo.store.teachers[email] = models.Teacher{Email: email}
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryTeacherRepo) DeleteAllTeachers(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.teachers = make(map[string]models.Teacher)
+	return nil
+}
+
+func (repo *InMemoryTeacherRepo) GetTeachersByEmails(teacherEmails []string, db *gorm.DB) ([]*models.Teacher, error) {
+	defer repo.store.rlock(db)()
+
+	teachers := make([]*models.Teacher, 0)
+	for _, email := range sortedUniqueStrings(teacherEmails) {
+		if teacher, ok := repo.store.teachers[email]; ok {
+			teachers = append(teachers, &teacher)
+		}
+	}
+	return teachers, nil
+}
+
+// GetTeachersAfterEmail returns at most limit teachers sorted by email, starting after afterEmail
+func (repo *InMemoryTeacherRepo) GetTeachersAfterEmail(afterEmail string, limit int, db *gorm.DB) ([]*models.Teacher, error) {
+	defer repo.store.rlock(db)()
+
+	emails := make([]string, 0, len(repo.store.teachers))
+	for email := range repo.store.teachers {
+		emails = append(emails, email)
+	}
+
+	teachers := make([]*models.Teacher, 0)
+	for _, email := range pageOfEmails(emails, afterEmail, limit) {
+		teacher := repo.store.teachers[email]
+		teachers = append(teachers, &teacher)
+	}
+	return teachers, nil
+}
+
+func (repo *InMemoryTeacherRepo) GetTeacherByEmail(teacherEmail string, db *gorm.DB) (*models.Teacher, error) {
+	defer repo.store.rlock(db)()
+
+	if teacher, ok := repo.store.teachers[teacherEmail]; ok {
+		return &teacher, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryTeacherRepo) UpdateTeacherProfile(teacherEmail string, profile *models.Profile, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	if teacher, ok := repo.store.teachers[teacherEmail]; ok {
+		teacher.Profile = *profile
+		repo.store.teachers[teacherEmail] = teacher
+	}
+	return nil
+}
+
+// DeleteTeacher deletes the teacher together with everything they recorded, like the sql repository
+func (repo *InMemoryTeacherRepo) DeleteTeacher(teacherEmail string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	store := repo.store
+	notificationIds := make(map[uint]bool)
+	for id, notification := range store.notifications {
+		if notification.TeacherEmail == teacherEmail {
+			notificationIds[id] = true
+			delete(store.notifications, id)
+		}
+	}
+	store.notificationDeliveries = helpers.Filter(store.notificationDeliveries, func(delivery models.NotificationDelivery) bool {
+		return !notificationIds[delivery.NotificationID]
+	})
+	store.notificationInboxes = helpers.Filter(store.notificationInboxes, func(recipient models.NotificationRecipient) bool {
+		return !notificationIds[recipient.NotificationID]
+	})
+	store.grades = helpers.Filter(store.grades, func(grade models.Grade) bool {
+		return grade.TeacherEmail != teacherEmail
+	})
+	store.gradeCategories = helpers.Filter(store.gradeCategories, func(category models.GradeCategory) bool {
+		return category.TeacherEmail != teacherEmail
+	})
+	assignmentIds := make(map[uint]bool)
+	store.assignments = helpers.Filter(store.assignments, func(assignment models.Assignment) bool {
+		if assignment.TeacherEmail == teacherEmail {
+			assignmentIds[assignment.ID] = true
+			return false
+		}
+		return true
+	})
+	store.submissions = helpers.Filter(store.submissions, func(submission models.Submission) bool {
+		return !assignmentIds[submission.AssignmentID]
+	})
+	for key := range store.attendances {
+		if key.teacherEmail == teacherEmail {
+			delete(store.attendances, key)
+		}
+	}
+	for key := range store.sectionTeachers {
+		if key.email == teacherEmail {
+			delete(store.sectionTeachers, key)
+		}
+	}
+	for key := range store.registerRelationships {
+		if key.teacherEmail == teacherEmail {
+			delete(store.registerRelationships, key)
+		}
+	}
+	delete(store.teachers, teacherEmail)
+	return nil
+}
+
+type InMemoryRegisterRelationshipRepo struct {
+	store *InMemoryStore
+}
+
+// CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists skips duplicates and relationships referencing
+// missing teachers or students, like INSERT IGNORE does with foreign keys
+func (repo *InMemoryRegisterRelationshipRepo) CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists(teacherEmail string, studentEmails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	if _, ok := repo.store.teachers[teacherEmail]; !ok {
+		return nil
+	}
+	for _, studentEmail := range studentEmails {
+		if _, ok := repo.store.students[studentEmail]; ok {
+			repo.store.registerRelationships[registerRelationshipKey{teacherEmail: teacherEmail, studentEmail: studentEmail}] = true
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryRegisterRelationshipRepo) GetRelationshipsByTeacherEmail(teacherEmail string, db *gorm.DB) ([]*models.RegisterRelationship, error) {
+	return repo.GetRelationshipsByTeacherEmails([]string{teacherEmail}, db)
+}
+
+// GetRelationshipsByTeacherEmails returns relationships ordered by student email and then teacher email
+func (repo *InMemoryRegisterRelationshipRepo) GetRelationshipsByTeacherEmails(teacherEmails []string, db *gorm.DB) ([]*models.RegisterRelationship, error) {
+	defer repo.store.rlock(db)()
+
+	teacherEmailSet := make(map[string]bool)
+	for _, email := range teacherEmails {
+		teacherEmailSet[email] = true
+	}
+
+	relationships := make([]*models.RegisterRelationship, 0)
+	for key := range repo.store.registerRelationships {
+		if !teacherEmailSet[key.teacherEmail] {
+			continue
+		}
+		relationships = append(relationships, &models.RegisterRelationship{
+			TeacherEmail:      key.teacherEmail,
+			Teacher:           repo.store.teachers[key.teacherEmail],
+			StudentEmail:      key.studentEmail,
+			RegisteredStudent: repo.store.students[key.studentEmail],
+		})
+	}
+
+	sort.Slice(relationships, func(i, j int) bool {
+		if relationships[i].StudentEmail != relationships[j].StudentEmail {
+			return relationships[i].StudentEmail < relationships[j].StudentEmail
+		}
+		return relationships[i].TeacherEmail < relationships[j].TeacherEmail
+	})
+
+	return relationships, nil
+}
+
+// GetStudentEmailsRegisteredToAtLeast returns the emails of the students registered to at least minTeacherCount of
+// the teachers, sorted by email, after afterStudentEmail and at most limit of them unless limit is not positive
+func (repo *InMemoryRegisterRelationshipRepo) GetStudentEmailsRegisteredToAtLeast(teacherEmails []string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	teacherCounts := make(map[string]int)
+	for _, teacherEmail := range helpers.RemoveDuplicatesInStringSlice(teacherEmails) {
+		for _, studentEmail := range repo.studentEmailsOf(teacherEmail) {
+			teacherCounts[studentEmail]++
+		}
+	}
+
+	studentEmails := make([]string, 0)
+	for studentEmail, count := range teacherCounts {
+		if count >= minTeacherCount {
+			studentEmails = append(studentEmails, studentEmail)
+		}
+	}
+	return pageOfEmails(studentEmails, afterStudentEmail, limit), nil
+}
+
+// GetStudentEmailsRegisteredOnlyTo returns the emails of the students registered to the teacher but to none of the
+// other teachers, sorted and paged like GetStudentEmailsRegisteredToAtLeast
+func (repo *InMemoryRegisterRelationshipRepo) GetStudentEmailsRegisteredOnlyTo(teacherEmail string, otherTeacherEmails []string, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	studentEmails := repo.studentEmailsOf(teacherEmail)
+	for _, otherTeacherEmail := range otherTeacherEmails {
+		studentEmails = helpers.RemoveAllStringsInSlice(studentEmails, repo.studentEmailsOf(otherTeacherEmail))
+	}
+	return pageOfEmails(studentEmails, afterStudentEmail, limit), nil
+}
+
+func (repo *InMemoryRegisterRelationshipRepo) GetTeacherEmailsByStudentEmail(studentEmail string, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	teacherEmails := make([]string, 0)
+	for key := range repo.store.registerRelationships {
+		if key.studentEmail == studentEmail {
+			teacherEmails = append(teacherEmails, key.teacherEmail)
+		}
+	}
+	sort.Strings(teacherEmails)
+	return teacherEmails, nil
+}
+
+// studentEmailsOf must be called with the store lock held
+func (repo *InMemoryRegisterRelationshipRepo) studentEmailsOf(teacherEmail string) []string {
+	studentEmails := make([]string, 0)
+	for key := range repo.store.registerRelationships {
+		if key.teacherEmail == teacherEmail {
+			studentEmails = append(studentEmails, key.studentEmail)
+		}
+	}
+	return studentEmails
+}
+
+// GetRelationshipsAfter returns at most limit relationships sorted by teacher and then student, starting after the
+// relationship between afterTeacherEmail and afterStudentEmail. The teachers and students are not preloaded.
+func (repo *InMemoryRegisterRelationshipRepo) GetRelationshipsAfter(afterTeacherEmail string, afterStudentEmail string, limit int, db *gorm.DB) ([]*models.RegisterRelationship, error) {
+	defer repo.store.rlock(db)()
+
+	relationships := make([]*models.RegisterRelationship, 0)
+	for key := range repo.store.registerRelationships {
+		if key.teacherEmail > afterTeacherEmail || (key.teacherEmail == afterTeacherEmail && key.studentEmail > afterStudentEmail) {
+			relationships = append(relationships, &models.RegisterRelationship{TeacherEmail: key.teacherEmail, StudentEmail: key.studentEmail})
+		}
+	}
+
+	sort.Slice(relationships, func(i, j int) bool {
+		if relationships[i].TeacherEmail != relationships[j].TeacherEmail {
+			return relationships[i].TeacherEmail < relationships[j].TeacherEmail
+		}
+		return relationships[i].StudentEmail < relationships[j].StudentEmail
+	})
+
+	if limit < len(relationships) {
+		relationships = relationships[:limit]
+	}
+	return relationships, nil
+}
+
+func (repo *InMemoryRegisterRelationshipRepo) DeleteRegisterRelationships(teacherEmail string, studentEmails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, studentEmail := range studentEmails {
+		delete(repo.store.registerRelationships, registerRelationshipKey{teacherEmail: teacherEmail, studentEmail: studentEmail})
+	}
+	return nil
+}
+
+func (repo *InMemoryRegisterRelationshipRepo) DeleteAllRegisterRelationships(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.registerRelationships = make(map[registerRelationshipKey]bool)
+	return nil
+}
+
+type InMemoryStudentSuspensionRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryStudentSuspensionRepo) CreateSuspension(suspension *models.StudentSuspension, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.lastSuspensionId++
+	suspension.ID = repo.store.lastSuspensionId
+	stored := *suspension
+	stored.Student = models.Student{}
+	repo.store.studentSuspensions = append(repo.store.studentSuspensions, stored)
+	return nil
+}
+
+func isSuspensionActiveAt(suspension *models.StudentSuspension, at time.Time) bool {
+	return suspension.LiftedAt == nil && (suspension.Until == nil || suspension.Until.After(at))
+}
+
+func (repo *InMemoryStudentSuspensionRepo) GetActiveSuspensionByStudentEmail(studentEmail string, at time.Time, db *gorm.DB) (*models.StudentSuspension, error) {
+	suspensions, _ := repo.GetSuspensionsByStudentEmail(studentEmail, db)
+	for _, suspension := range suspensions {
+		if isSuspensionActiveAt(suspension, at) {
+			return suspension, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryStudentSuspensionRepo) GetActivelySuspendedStudentEmails(studentEmails []string, at time.Time, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	studentEmailSet := make(map[string]bool)
+	for _, email := range studentEmails {
+		studentEmailSet[email] = true
+	}
+
+	suspendedStudentEmails := make([]string, 0)
+	for i := range repo.store.studentSuspensions {
+		suspension := &repo.store.studentSuspensions[i]
+		if studentEmailSet[suspension.StudentEmail] && isSuspensionActiveAt(suspension, at) {
+			suspendedStudentEmails = append(suspendedStudentEmails, suspension.StudentEmail)
+		}
+	}
+	return sortedUniqueStrings(suspendedStudentEmails), nil
+}
+
+// GetExpiredSuspensions returns suspensions whose end has passed at the given time but that have not been lifted yet
+func (repo *InMemoryStudentSuspensionRepo) GetExpiredSuspensions(at time.Time, db *gorm.DB) ([]*models.StudentSuspension, error) {
+	defer repo.store.rlock(db)()
+
+	suspensions := make([]*models.StudentSuspension, 0)
+	for _, suspension := range repo.store.studentSuspensions {
+		if suspension.LiftedAt == nil && suspension.Until != nil && !suspension.Until.After(at) {
+			suspension := suspension
+			suspensions = append(suspensions, &suspension)
+		}
+	}
+
+	sort.SliceStable(suspensions, func(i, j int) bool {
+		return suspensions[i].Until.Before(*suspensions[j].Until)
+	})
+
+	return suspensions, nil
+}
+
+func (repo *InMemoryStudentSuspensionRepo) LiftSuspension(suspensionId uint, liftedAt time.Time, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i := range repo.store.studentSuspensions {
+		if repo.store.studentSuspensions[i].ID == suspensionId {
+			repo.store.studentSuspensions[i].LiftedAt = &liftedAt
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryStudentSuspensionRepo) UpdateSuspensionUntil(suspensionId uint, until *time.Time, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i := range repo.store.studentSuspensions {
+		if repo.store.studentSuspensions[i].ID == suspensionId {
+			repo.store.studentSuspensions[i].Until = until
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryStudentSuspensionRepo) LiftActiveSuspensions(studentEmail string, liftedAt time.Time, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i := range repo.store.studentSuspensions {
+		suspension := &repo.store.studentSuspensions[i]
+		if suspension.StudentEmail == studentEmail && suspension.LiftedAt == nil {
+			lifted := liftedAt
+			suspension.LiftedAt = &lifted
+		}
+	}
+	return nil
+}
+
+// GetSuspensionsByStudentEmail returns suspensions ordered by when they started
+func (repo *InMemoryStudentSuspensionRepo) GetSuspensionsByStudentEmail(studentEmail string, db *gorm.DB) ([]*models.StudentSuspension, error) {
+	defer repo.store.rlock(db)()
+
+	suspensions := make([]*models.StudentSuspension, 0)
+	for _, suspension := range repo.store.studentSuspensions {
+		if suspension.StudentEmail == studentEmail {
+			suspension := suspension
+			suspensions = append(suspensions, &suspension)
+		}
+	}
+
+	sort.SliceStable(suspensions, func(i, j int) bool {
+		return suspensions[i].SuspendedAt.Before(suspensions[j].SuspendedAt)
+	})
+
+	return suspensions, nil
+}
+
+func (repo *InMemoryStudentSuspensionRepo) DeleteAllSuspensions(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.studentSuspensions = nil
+	return nil
+}
+
+type InMemoryNotificationRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryNotificationRepo) CreateNotification(notification *models.Notification, recipientEmails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.lastNotificationId++
+	notification.ID = repo.store.lastNotificationId
+	stored := *notification
+	stored.Teacher = models.Teacher{}
+	repo.store.notifications[stored.ID] = stored
+
+	for _, studentEmail := range recipientEmails {
+		repo.store.notificationInboxes = append(repo.store.notificationInboxes, models.NotificationRecipient{NotificationID: stored.ID, StudentEmail: studentEmail})
+	}
+	return nil
+}
+
+func (repo *InMemoryNotificationRepo) GetNotificationById(notificationId uint, db *gorm.DB) (*models.Notification, error) {
+	defer repo.store.rlock(db)()
+
+	if notification, ok := repo.store.notifications[notificationId]; ok {
+		return &notification, nil
+	}
+	return nil, nil
+}
+
+// GetRecipientEmails returns the emails of the recipients of a notification after afterStudentEmail, sorted by email
+func (repo *InMemoryNotificationRepo) GetRecipientEmails(notificationId uint, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	studentEmails := make([]string, 0)
+	for _, recipient := range repo.store.notificationInboxes {
+		if recipient.NotificationID == notificationId {
+			studentEmails = append(studentEmails, recipient.StudentEmail)
+		}
+	}
+	return pageOfEmails(studentEmails, afterStudentEmail, limit), nil
+}
+
+// GetInboxByStudentEmail returns the newest entries of the inbox of a student first, starting before the notification
+// with id beforeNotificationId unless it is 0, with their notification preloaded
+func (repo *InMemoryNotificationRepo) GetInboxByStudentEmail(studentEmail string, unreadOnly bool, beforeNotificationId uint, limit int, db *gorm.DB) ([]*models.NotificationRecipient, error) {
+	defer repo.store.rlock(db)()
+
+	recipients := make([]*models.NotificationRecipient, 0)
+	for _, recipient := range repo.inbox(studentEmail, unreadOnly) {
+		if beforeNotificationId == 0 || recipient.NotificationID < beforeNotificationId {
+			recipients = append(recipients, recipient)
+		}
+	}
+	sort.Slice(recipients, func(i, j int) bool {
+		return recipients[i].NotificationID > recipients[j].NotificationID
+	})
+
+	if limit > 0 && limit < len(recipients) {
+		recipients = recipients[:limit]
+	}
+	return recipients, nil
+}
+
+func (repo *InMemoryNotificationRepo) CountInboxByStudentEmail(studentEmail string, unreadOnly bool, db *gorm.DB) (int64, error) {
+	defer repo.store.rlock(db)()
+
+	return int64(len(repo.inbox(studentEmail, unreadOnly))), nil
+}
+
+func (repo *InMemoryNotificationRepo) GetInboxEntry(notificationId uint, studentEmail string, db *gorm.DB) (*models.NotificationRecipient, error) {
+	defer repo.store.rlock(db)()
+
+	for _, recipient := range repo.store.notificationInboxes {
+		if recipient.NotificationID == notificationId && recipient.StudentEmail == studentEmail {
+			return &recipient, nil
+		}
+	}
+	return nil, nil
+}
+
+// MarkInboxEntryAsRead sets when the notification was read, unless it was already read before
+func (repo *InMemoryNotificationRepo) MarkInboxEntryAsRead(notificationId uint, studentEmail string, readAt time.Time, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i := range repo.store.notificationInboxes {
+		recipient := &repo.store.notificationInboxes[i]
+		if recipient.NotificationID == notificationId && recipient.StudentEmail == studentEmail && recipient.ReadAt == nil {
+			recipient.ReadAt = &readAt
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryNotificationRepo) DeleteAllNotifications(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.notifications = make(map[uint]models.Notification)
+	repo.store.notificationInboxes = nil
+	return nil
+}
+
+// inbox must be called with the store lock held
+func (repo *InMemoryNotificationRepo) inbox(studentEmail string, unreadOnly bool) []*models.NotificationRecipient {
+	recipients := make([]*models.NotificationRecipient, 0)
+	for _, recipient := range repo.store.notificationInboxes {
+		if recipient.StudentEmail != studentEmail || (unreadOnly && recipient.ReadAt != nil) {
+			continue
+		}
+		recipient := recipient
+		recipient.Notification = repo.store.notifications[recipient.NotificationID]
+		recipients = append(recipients, &recipient)
+	}
+	return recipients
+}
+
+type InMemoryNotificationDeliveryRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryNotificationDeliveryRepo) CreateDeliveries(notificationId uint, studentEmails []string, nextAttemptAt time.Time, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, studentEmail := range studentEmails {
+		if repo.indexOf(notificationId, studentEmail) < 0 {
+			repo.store.notificationDeliveries = append(repo.store.notificationDeliveries, models.NotificationDelivery{
+				NotificationID: notificationId,
+				StudentEmail:   studentEmail,
+				Status:         models.DELIVERY_PENDING,
+				NextAttemptAt:  nextAttemptAt,
+			})
+		}
+	}
+	return nil
+}
+
+// GetDueDeliveries returns up to limit pending deliveries whose next attempt is due, oldest first, with their
+// notification preloaded
+func (repo *InMemoryNotificationDeliveryRepo) GetDueDeliveries(at time.Time, limit int, db *gorm.DB) ([]*models.NotificationDelivery, error) {
+	defer repo.store.rlock(db)()
+
+	deliveries := make([]*models.NotificationDelivery, 0)
+	for _, delivery := range repo.store.notificationDeliveries {
+		if delivery.Status == models.DELIVERY_PENDING && !delivery.NextAttemptAt.After(at) {
+			delivery := delivery
+			delivery.Notification = repo.store.notifications[delivery.NotificationID]
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		if deliveries[i].NotificationID != deliveries[j].NotificationID {
+			return deliveries[i].NotificationID < deliveries[j].NotificationID
+		}
+		return deliveries[i].StudentEmail < deliveries[j].StudentEmail
+	})
+
+	if limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *InMemoryNotificationDeliveryRepo) UpdateDelivery(delivery *models.NotificationDelivery, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	if i := repo.indexOf(delivery.NotificationID, delivery.StudentEmail); i >= 0 {
+		updated := *delivery
+		updated.Notification = models.Notification{}
+		updated.Student = models.Student{}
+		repo.store.notificationDeliveries[i] = updated
+	}
+	return nil
+}
+
+func (repo *InMemoryNotificationDeliveryRepo) GetDeliveriesByNotificationId(notificationId uint, db *gorm.DB) ([]*models.NotificationDelivery, error) {
+	defer repo.store.rlock(db)()
+
+	deliveries := make([]*models.NotificationDelivery, 0)
+	for _, delivery := range repo.store.notificationDeliveries {
+		if delivery.NotificationID == notificationId {
+			delivery := delivery
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].StudentEmail < deliveries[j].StudentEmail
+	})
+	return deliveries, nil
+}
+
+func (repo *InMemoryNotificationDeliveryRepo) DeleteAllDeliveries(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.notificationDeliveries = nil
+	return nil
+}
+
+// indexOf must be called with the store lock held
+func (repo *InMemoryNotificationDeliveryRepo) indexOf(notificationId uint, studentEmail string) int {
+	for i, delivery := range repo.store.notificationDeliveries {
+		if delivery.NotificationID == notificationId && delivery.StudentEmail == studentEmail {
+			return i
+		}
+	}
+	return -1
+}
+
+type InMemoryWebhookSubscriptionRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryWebhookSubscriptionRepo) CreateSubscription(subscription *models.WebhookSubscription, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.lastSubscriptionId++
+	subscription.ID = repo.store.lastSubscriptionId
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = time.Now()
+	}
+	repo.store.webhookSubscriptions = append(repo.store.webhookSubscriptions, *subscription)
+	return nil
+}
+
+func (repo *InMemoryWebhookSubscriptionRepo) GetSubscriptionById(subscriptionId uint, db *gorm.DB) (*models.WebhookSubscription, error) {
+	defer repo.store.rlock(db)()
+
+	for _, subscription := range repo.store.webhookSubscriptions {
+		if subscription.ID == subscriptionId {
+			return &subscription, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryWebhookSubscriptionRepo) GetAllSubscriptions(db *gorm.DB) ([]*models.WebhookSubscription, error) {
+	defer repo.store.rlock(db)()
+
+	subscriptions := make([]*models.WebhookSubscription, 0, len(repo.store.webhookSubscriptions))
+	for _, subscription := range repo.store.webhookSubscriptions {
+		subscription := subscription
+		subscriptions = append(subscriptions, &subscription)
+	}
+	return subscriptions, nil
+}
+
+func (repo *InMemoryWebhookSubscriptionRepo) DeleteSubscription(subscriptionId uint, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	remaining := make([]models.WebhookSubscription, 0, len(repo.store.webhookSubscriptions))
+	for _, subscription := range repo.store.webhookSubscriptions {
+		if subscription.ID != subscriptionId {
+			remaining = append(remaining, subscription)
+		}
+	}
+	repo.store.webhookSubscriptions = remaining
+	return nil
+}
+
+func (repo *InMemoryWebhookSubscriptionRepo) DeleteAllSubscriptions(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.webhookSubscriptions = nil
+	return nil
+}
+
+type InMemoryWebhookDeliveryRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryWebhookDeliveryRepo) CreateWebhookDeliveries(deliveries []*models.WebhookDelivery, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, delivery := range deliveries {
+		repo.store.lastWebhookDeliveryId++
+		delivery.ID = repo.store.lastWebhookDeliveryId
+		if delivery.CreatedAt.IsZero() {
+			delivery.CreatedAt = time.Now()
+		}
+		stored := *delivery
+		stored.Subscription = models.WebhookSubscription{}
+		repo.store.webhookDeliveries = append(repo.store.webhookDeliveries, stored)
+	}
+	return nil
+}
+
+// GetDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due, oldest first, with their
+// subscription preloaded
+func (repo *InMemoryWebhookDeliveryRepo) GetDueWebhookDeliveries(at time.Time, limit int, db *gorm.DB) ([]*models.WebhookDelivery, error) {
+	defer repo.store.rlock(db)()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for _, delivery := range repo.store.webhookDeliveries {
+		if delivery.Status == models.DELIVERY_PENDING && !delivery.NextAttemptAt.After(at) {
+			delivery := delivery
+			for _, subscription := range repo.store.webhookSubscriptions {
+				if subscription.ID == delivery.SubscriptionID {
+					delivery.Subscription = subscription
+				}
+			}
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+
+	if limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *InMemoryWebhookDeliveryRepo) UpdateWebhookDelivery(delivery *models.WebhookDelivery, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i, stored := range repo.store.webhookDeliveries {
+		if stored.ID == delivery.ID {
+			stored.Status = delivery.Status
+			stored.Attempts = delivery.Attempts
+			stored.ResponseStatus = delivery.ResponseStatus
+			stored.LastError = delivery.LastError
+			stored.NextAttemptAt = delivery.NextAttemptAt
+			stored.DeliveredAt = delivery.DeliveredAt
+			repo.store.webhookDeliveries[i] = stored
+		}
+	}
+	return nil
+}
+
+// GetWebhookDeliveriesBySubscriptionId returns the newest deliveries to a subscription first, starting before the
+// delivery with id beforeId unless it is 0
+func (repo *InMemoryWebhookDeliveryRepo) GetWebhookDeliveriesBySubscriptionId(subscriptionId uint, beforeId uint, limit int, db *gorm.DB) ([]*models.WebhookDelivery, error) {
+	defer repo.store.rlock(db)()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for i := len(repo.store.webhookDeliveries) - 1; i >= 0; i-- {
+		delivery := repo.store.webhookDeliveries[i]
+		if delivery.SubscriptionID == subscriptionId && (beforeId == 0 || delivery.ID < beforeId) {
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+
+	if limit > 0 && limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *InMemoryWebhookDeliveryRepo) CountWebhookDeliveriesBySubscriptionId(subscriptionId uint, db *gorm.DB) (int64, error) {
+	defer repo.store.rlock(db)()
+
+	var count int64
+	for _, delivery := range repo.store.webhookDeliveries {
+		if delivery.SubscriptionID == subscriptionId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo *InMemoryWebhookDeliveryRepo) DeleteWebhookDeliveriesBySubscriptionId(subscriptionId uint, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	remaining := make([]models.WebhookDelivery, 0, len(repo.store.webhookDeliveries))
+	for _, delivery := range repo.store.webhookDeliveries {
+		if delivery.SubscriptionID != subscriptionId {
+			remaining = append(remaining, delivery)
+		}
+	}
+	repo.store.webhookDeliveries = remaining
+	return nil
+}
+
+func (repo *InMemoryWebhookDeliveryRepo) DeleteAllWebhookDeliveries(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.webhookDeliveries = nil
+	return nil
+}
+
+type InMemoryCourseRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryCourseRepo) SaveCourse(course *models.Course, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.courses[course.Code] = *course
+	return nil
+}
+
+func (repo *InMemoryCourseRepo) GetCourseByCode(courseCode string, db *gorm.DB) (*models.Course, error) {
+	defer repo.store.rlock(db)()
+
+	if course, ok := repo.store.courses[courseCode]; ok {
+		return &course, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryCourseRepo) GetAllCourses(db *gorm.DB) ([]*models.Course, error) {
+	defer repo.store.rlock(db)()
+
+	courses := make([]*models.Course, 0, len(repo.store.courses))
+	for _, course := range repo.store.courses {
+		course := course
+		courses = append(courses, &course)
+	}
+	sort.Slice(courses, func(i, j int) bool {
+		return courses[i].Code < courses[j].Code
+	})
+	return courses, nil
+}
+
+// CreateSectionIfNotExists skips sections of missing courses, like INSERT IGNORE does with foreign keys
+func (repo *InMemoryCourseRepo) CreateSectionIfNotExists(courseCode string, sectionName string, db *gorm.DB) (*models.Section, error) {
+	unlock := repo.store.lock(db)
+	if _, ok := repo.store.courses[courseCode]; ok && repo.sectionIndexOf(courseCode, sectionName) < 0 {
+		repo.store.lastSectionId++
+		repo.store.sections = append(repo.store.sections, models.Section{ID: repo.store.lastSectionId, CourseCode: courseCode, Name: sectionName})
+	}
+	unlock()
+
+	return repo.GetSection(courseCode, sectionName, db)
+}
+
+func (repo *InMemoryCourseRepo) GetSection(courseCode string, sectionName string, db *gorm.DB) (*models.Section, error) {
+	defer repo.store.rlock(db)()
+
+	if index := repo.sectionIndexOf(courseCode, sectionName); index >= 0 {
+		section := repo.store.sections[index]
+		return &section, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryCourseRepo) GetSectionsByCourseCode(courseCode string, db *gorm.DB) ([]*models.Section, error) {
+	defer repo.store.rlock(db)()
+
+	return repo.sectionsWhere(func(section *models.Section) bool {
+		return section.CourseCode == courseCode
+	}), nil
+}
+
+func (repo *InMemoryCourseRepo) GetSectionsByTeacherEmail(teacherEmail string, courseCode string, db *gorm.DB) ([]*models.Section, error) {
+	defer repo.store.rlock(db)()
+
+	return repo.sectionsWhere(func(section *models.Section) bool {
+		return section.CourseCode == courseCode && repo.store.sectionTeachers[sectionMemberKey{sectionId: section.ID, email: teacherEmail}]
+	}), nil
+}
+
+// AssignTeachersToSectionIfNotAssigned skips missing teachers, like INSERT IGNORE does with foreign keys
+func (repo *InMemoryCourseRepo) AssignTeachersToSectionIfNotAssigned(sectionId uint, teacherEmails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, teacherEmail := range teacherEmails {
+		if _, ok := repo.store.teachers[teacherEmail]; ok {
+			repo.store.sectionTeachers[sectionMemberKey{sectionId: sectionId, email: teacherEmail}] = true
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryCourseRepo) GetTeacherEmailsBySectionId(sectionId uint, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	return pageOfEmails(membersOf(repo.store.sectionTeachers, sectionId), "", 0), nil
+}
+
+// EnrollStudentsIfNotEnrolled skips missing students, like INSERT IGNORE does with foreign keys
+func (repo *InMemoryCourseRepo) EnrollStudentsIfNotEnrolled(sectionId uint, studentEmails []string, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, studentEmail := range studentEmails {
+		if _, ok := repo.store.students[studentEmail]; ok {
+			repo.store.enrollments[sectionMemberKey{sectionId: sectionId, email: studentEmail}] = true
+		}
+	}
+	return nil
+}
+
+func (repo *InMemoryCourseRepo) GetStudentEmailsBySectionId(sectionId uint, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	return pageOfEmails(membersOf(repo.store.enrollments, sectionId), "", 0), nil
+}
+
+func (repo *InMemoryCourseRepo) GetStudentEmailsTaughtByAtLeast(teacherEmails []string, courseCode string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	teacherCounts := make(map[string]int)
+	for _, teacherEmail := range helpers.RemoveDuplicatesInStringSlice(teacherEmails) {
+		for _, studentEmail := range repo.studentEmailsTaughtBy(teacherEmail, courseCode) {
+			teacherCounts[studentEmail]++
+		}
+	}
+
+	studentEmails := make([]string, 0)
+	for studentEmail, count := range teacherCounts {
+		if count >= minTeacherCount {
+			studentEmails = append(studentEmails, studentEmail)
+		}
+	}
+	return pageOfEmails(studentEmails, afterStudentEmail, limit), nil
+}
+
+func (repo *InMemoryCourseRepo) GetStudentEmailsTaughtOnlyBy(teacherEmail string, otherTeacherEmails []string, courseCode string, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+	defer repo.store.rlock(db)()
+
+	studentEmails := repo.studentEmailsTaughtBy(teacherEmail, courseCode)
+	for _, otherTeacherEmail := range otherTeacherEmails {
+		studentEmails = helpers.RemoveAllStringsInSlice(studentEmails, repo.studentEmailsTaughtBy(otherTeacherEmail, courseCode))
+	}
+	return pageOfEmails(studentEmails, afterStudentEmail, limit), nil
+}
+
+func (repo *InMemoryCourseRepo) DeleteAllCourses(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.courses = make(map[string]models.Course)
+	repo.store.sections = nil
+	repo.store.sectionTeachers = make(map[sectionMemberKey]bool)
+	repo.store.enrollments = make(map[sectionMemberKey]bool)
+	return nil
+}
+
+// sectionIndexOf must be called with the store lock held
+func (repo *InMemoryCourseRepo) sectionIndexOf(courseCode string, sectionName string) int {
+	for i, section := range repo.store.sections {
+		if section.CourseCode == courseCode && section.Name == sectionName {
+			return i
+		}
+	}
+	return -1
+}
+
+// sectionsWhere returns the matching sections sorted by name, it must be called with the store lock held
+func (repo *InMemoryCourseRepo) sectionsWhere(matches func(section *models.Section) bool) []*models.Section {
+	sections := make([]*models.Section, 0)
+	for _, section := range repo.store.sections {
+		section := section
+		if matches(&section) {
+			sections = append(sections, &section)
+		}
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i].Name < sections[j].Name
+	})
+	return sections
+}
+
+// studentEmailsTaughtBy returns the distinct students of the teacher in sections of the course, it must be called
+// with the store lock held
+func (repo *InMemoryCourseRepo) studentEmailsTaughtBy(teacherEmail string, courseCode string) []string {
+	studentEmails := make([]string, 0)
+	for _, section := range repo.store.sections {
+		if section.CourseCode == courseCode && repo.store.sectionTeachers[sectionMemberKey{sectionId: section.ID, email: teacherEmail}] {
+			studentEmails = append(studentEmails, membersOf(repo.store.enrollments, section.ID)...)
+		}
+	}
+	return helpers.RemoveDuplicatesInStringSlice(studentEmails)
+}
+
+func membersOf(members map[sectionMemberKey]bool, sectionId uint) []string {
+	emails := make([]string, 0)
+	for key := range members {
+		if key.sectionId == sectionId {
+			emails = append(emails, key.email)
+		}
+	}
+	return emails
+}
+
+type InMemoryAssignmentRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryAssignmentRepo) CreateAssignment(assignment *models.Assignment, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.lastAssignmentId++
+	assignment.ID = repo.store.lastAssignmentId
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = time.Now()
+	}
+	repo.store.assignments = append(repo.store.assignments, *assignment)
+	return nil
+}
+
+func (repo *InMemoryAssignmentRepo) GetAssignmentById(assignmentId uint, db *gorm.DB) (*models.Assignment, error) {
+	defer repo.store.rlock(db)()
+
+	for _, assignment := range repo.store.assignments {
+		if assignment.ID == assignmentId {
+			return &assignment, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryAssignmentRepo) GetAssignmentsByTeacherEmail(teacherEmail string, db *gorm.DB) ([]*models.Assignment, error) {
+	defer repo.store.rlock(db)()
+
+	assignments := make([]*models.Assignment, 0)
+	for _, assignment := range repo.store.assignments {
+		if assignment.TeacherEmail == teacherEmail {
+			assignment := assignment
+			assignments = append(assignments, &assignment)
+		}
+	}
+	sort.SliceStable(assignments, func(i, j int) bool {
+		return assignments[i].DueAt.Before(assignments[j].DueAt)
+	})
+	return assignments, nil
+}
+
+func (repo *InMemoryAssignmentRepo) SaveSubmission(submission *models.Submission, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i, existing := range repo.store.submissions {
+		if existing.AssignmentID == submission.AssignmentID && existing.StudentEmail == submission.StudentEmail {
+			repo.store.submissions[i] = *submission
+			return nil
+		}
+	}
+	repo.store.submissions = append(repo.store.submissions, *submission)
+	return nil
+}
+
+func (repo *InMemoryAssignmentRepo) GetSubmissionsByAssignmentIds(assignmentIds []uint, db *gorm.DB) ([]*models.Submission, error) {
+	defer repo.store.rlock(db)()
+
+	wanted := make(map[uint]bool, len(assignmentIds))
+	for _, assignmentId := range assignmentIds {
+		wanted[assignmentId] = true
+	}
+
+	submissions := make([]*models.Submission, 0)
+	for _, submission := range repo.store.submissions {
+		if wanted[submission.AssignmentID] {
+			submission := submission
+			submissions = append(submissions, &submission)
+		}
+	}
+	sort.Slice(submissions, func(i, j int) bool {
+		if submissions[i].AssignmentID != submissions[j].AssignmentID {
+			return submissions[i].AssignmentID < submissions[j].AssignmentID
+		}
+		return submissions[i].StudentEmail < submissions[j].StudentEmail
+	})
+	return submissions, nil
+}
+
+func (repo *InMemoryAssignmentRepo) DeleteAllAssignments(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.assignments = nil
+	repo.store.submissions = nil
+	return nil
+}
+
+type InMemoryGradebookRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryGradebookRepo) SaveCategory(category *models.GradeCategory, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for i, existing := range repo.store.gradeCategories {
+		if existing.TeacherEmail == category.TeacherEmail && existing.Name == category.Name {
+			category.ID = existing.ID
+			repo.store.gradeCategories[i].Weight = category.Weight
+			return nil
+		}
+	}
+	repo.store.lastGradeCategoryId++
+	category.ID = repo.store.lastGradeCategoryId
+	repo.store.gradeCategories = append(repo.store.gradeCategories, *category)
+	return nil
+}
+
+func (repo *InMemoryGradebookRepo) GetCategory(teacherEmail string, name string, db *gorm.DB) (*models.GradeCategory, error) {
+	defer repo.store.rlock(db)()
+
+	for _, category := range repo.store.gradeCategories {
+		if category.TeacherEmail == teacherEmail && category.Name == name {
+			return &category, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryGradebookRepo) GetCategoriesByTeacherEmail(teacherEmail string, db *gorm.DB) ([]*models.GradeCategory, error) {
+	defer repo.store.rlock(db)()
+
+	categories := make([]*models.GradeCategory, 0)
+	for _, category := range repo.store.gradeCategories {
+		if category.TeacherEmail == teacherEmail {
+			category := category
+			categories = append(categories, &category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (repo *InMemoryGradebookRepo) CreateGrade(grade *models.Grade, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.lastGradeId++
+	grade.ID = repo.store.lastGradeId
+	repo.store.grades = append(repo.store.grades, *grade)
+	return nil
+}
+
+func (repo *InMemoryGradebookRepo) GetGrades(teacherEmail string, studentEmail string, db *gorm.DB) ([]*models.Grade, error) {
+	defer repo.store.rlock(db)()
+
+	grades := make([]*models.Grade, 0)
+	for _, grade := range repo.store.grades {
+		if grade.TeacherEmail == teacherEmail && grade.StudentEmail == studentEmail {
+			grade := grade
+			grades = append(grades, &grade)
+		}
+	}
+	return grades, nil
+}
+
+func (repo *InMemoryGradebookRepo) DeleteAllGrades(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.grades = nil
+	repo.store.gradeCategories = nil
+	return nil
+}
+
+type InMemoryAttendanceRepo struct {
+	store *InMemoryStore
+}
+
+func (repo *InMemoryAttendanceRepo) SaveAttendances(attendances []*models.Attendance, db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	for _, attendance := range attendances {
+		repo.store.attendances[attendanceKey{attendance.TeacherEmail, attendance.StudentEmail, attendance.Date}] = *attendance
+	}
+	return nil
+}
+
+func (repo *InMemoryAttendanceRepo) GetAttendancesByDate(teacherEmail string, date string, db *gorm.DB) ([]*models.Attendance, error) {
+	return repo.filterAttendances(func(attendance models.Attendance) bool {
+		return attendance.TeacherEmail == teacherEmail && attendance.Date == date
+	}, db), nil
+}
+
+func (repo *InMemoryAttendanceRepo) GetAttendancesBetween(teacherEmail string, fromDate string, toDate string, db *gorm.DB) ([]*models.Attendance, error) {
+	return repo.filterAttendances(func(attendance models.Attendance) bool {
+		return attendance.TeacherEmail == teacherEmail && inDateRange(attendance.Date, fromDate, toDate)
+	}, db), nil
+}
+
+func (repo *InMemoryAttendanceRepo) GetStudentAttendancesBetween(teacherEmail string, studentEmail string, fromDate string, toDate string, db *gorm.DB) ([]*models.Attendance, error) {
+	return repo.filterAttendances(func(attendance models.Attendance) bool {
+		return attendance.TeacherEmail == teacherEmail && attendance.StudentEmail == studentEmail && inDateRange(attendance.Date, fromDate, toDate)
+	}, db), nil
+}
+
+func (repo *InMemoryAttendanceRepo) DeleteAllAttendances(db *gorm.DB) error {
+	defer repo.store.lock(db)()
+
+	repo.store.attendances = make(map[attendanceKey]models.Attendance)
+	return nil
+}
+
+// filterAttendances returns the attendances kept by the filter sorted by student and then date, like the sql queries
+func (repo *InMemoryAttendanceRepo) filterAttendances(keep func(attendance models.Attendance) bool, db *gorm.DB) []*models.Attendance {
+	defer repo.store.rlock(db)()
+
+	attendances := make([]*models.Attendance, 0)
+	for _, attendance := range repo.store.attendances {
+		if keep(attendance) {
+			attendance := attendance
+			attendances = append(attendances, &attendance)
+		}
+	}
+	sort.Slice(attendances, func(i, j int) bool {
+		if attendances[i].StudentEmail != attendances[j].StudentEmail {
+			return attendances[i].StudentEmail < attendances[j].StudentEmail
+		}
+		return attendances[i].Date < attendances[j].Date
+	})
+	return attendances
+}
+
+func inDateRange(date string, fromDate string, toDate string) bool {
+	return (fromDate == "" || date >= fromDate) && (toDate == "" || date <= toDate)
+}
+
+func sortedUniqueStrings(slice []string) []string {
+	result := helpers.RemoveDuplicatesInStringSlice(slice)
+	sort.Strings(result)
+	return result
+}
+
+// pageOfEmails sorts the emails and keeps those after afterEmail, at most limit of them unless limit is not positive
+func pageOfEmails(emails []string, afterEmail string, limit int) []string {
+	sort.Strings(emails)
+	start := sort.SearchStrings(emails, afterEmail)
+	for start < len(emails) && emails[start] == afterEmail {
+		start++
+	}
+	emails = emails[start:]
+	if limit > 0 && limit < len(emails) {
+		emails = emails[:limit]
+	}
+	return emails
+}
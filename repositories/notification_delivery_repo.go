@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"time"
+)
+
+type NotificationDeliveryRepo struct{}
+
+func NewNotificationDeliveryRepo() *NotificationDeliveryRepo {
+	return &NotificationDeliveryRepo{}
+}
+
+func (*NotificationDeliveryRepo) CreateDeliveries(notificationId uint, studentEmails []string, nextAttemptAt time.Time, db *gorm.DB) (err error) {
+	if len(studentEmails) == 0 {
+		return nil
+	}
+
+	deliveries := helpers.Map(studentEmails, func(studentEmail string) *models.NotificationDelivery {
+		return &models.NotificationDelivery{NotificationID: notificationId, StudentEmail: studentEmail, Status: models.DELIVERY_PENDING, NextAttemptAt: nextAttemptAt}
+	})
+	err = db.Omit("Notification", "Student").Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+	return err
+}
+
+// GetDueDeliveries returns up to limit pending deliveries whose next attempt is due, oldest first, with their
+// notification preloaded
+func (*NotificationDeliveryRepo) GetDueDeliveries(at time.Time, limit int, db *gorm.DB) (deliveries []*models.NotificationDelivery, err error) {
+	err = db.Table("notification_deliveries").Preload("Notification").Where("status = ? AND next_attempt_at <= ?", models.DELIVERY_PENDING, at).
+		Order("next_attempt_at, notification_id, student_email").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (*NotificationDeliveryRepo) UpdateDelivery(delivery *models.NotificationDelivery, db *gorm.DB) (err error) {
+	err = db.Table("notification_deliveries").Where("notification_id = ? AND student_email = ?", delivery.NotificationID, delivery.StudentEmail).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"last_error":      delivery.LastError,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"delivered_at":    delivery.DeliveredAt,
+	}).Error
+	return err
+}
+
+func (*NotificationDeliveryRepo) GetDeliveriesByNotificationId(notificationId uint, db *gorm.DB) (deliveries []*models.NotificationDelivery, err error) {
+	err = db.Table("notification_deliveries").Where("notification_id = ?", notificationId).Order("student_email").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (*NotificationDeliveryRepo) DeleteAllDeliveries(db *gorm.DB) error {
+	return db.Exec("DELETE FROM notification_deliveries").Error
+}
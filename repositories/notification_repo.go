@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"time"
+)
+
+type NotificationRepo struct{}
+
+func NewNotificationRepo() *NotificationRepo {
+	return &NotificationRepo{}
+}
+
+// CreateNotification stores the notification together with an inbox entry for each recipient
+func (*NotificationRepo) CreateNotification(notification *models.Notification, recipientEmails []string, db *gorm.DB) (err error) {
+	if err = db.Omit("Teacher").Create(notification).Error; err != nil || len(recipientEmails) == 0 {
+		return err
+	}
+
+	recipients := helpers.Map(recipientEmails, func(studentEmail string) *models.NotificationRecipient {
+		return &models.NotificationRecipient{NotificationID: notification.ID, StudentEmail: studentEmail}
+	})
+	err = db.Omit("Notification", "Student").Create(&recipients).Error
+	return err
+}
+
+func (*NotificationRepo) GetNotificationById(notificationId uint, db *gorm.DB) (notification *models.Notification, err error) {
+	notification = &models.Notification{}
+	err = db.Table("notifications").Where("id = ?", notificationId).Find(&notification).Error
+	if notification.ID == 0 {
+		return nil, err
+	}
+	return notification, err
+}
+
+// GetRecipientEmails returns the emails of the recipients of a notification after afterStudentEmail, sorted by email
+func (*NotificationRepo) GetRecipientEmails(notificationId uint, afterStudentEmail string, limit int, db *gorm.DB) (studentEmails []string, err error) {
+	studentEmails = make([]string, 0)
+	err = db.Table("notification_recipients").Where("notification_id = ?", notificationId).
+		Scopes(emailPage("student_email", afterStudentEmail, limit)).
+		Order("student_email").Pluck("student_email", &studentEmails).Error
+	return studentEmails, err
+}
+
+// GetInboxByStudentEmail returns the newest entries of the inbox of a student first, starting before the notification
+// with id beforeNotificationId unless it is 0, with their notification preloaded
+func (*NotificationRepo) GetInboxByStudentEmail(studentEmail string, unreadOnly bool, beforeNotificationId uint, limit int, db *gorm.DB) (recipients []*models.NotificationRecipient, err error) {
+	err = db.Table("notification_recipients").Scopes(inbox(studentEmail, unreadOnly), idPage("notification_recipients.notification_id", beforeNotificationId, limit)).
+		Preload("Notification").Order("notification_recipients.notification_id DESC").Find(&recipients).Error
+	return recipients, err
+}
+
+func (*NotificationRepo) CountInboxByStudentEmail(studentEmail string, unreadOnly bool, db *gorm.DB) (count int64, err error) {
+	err = db.Table("notification_recipients").Scopes(inbox(studentEmail, unreadOnly)).Count(&count).Error
+	return count, err
+}
+
+func (*NotificationRepo) GetInboxEntry(notificationId uint, studentEmail string, db *gorm.DB) (recipient *models.NotificationRecipient, err error) {
+	recipient = &models.NotificationRecipient{}
+	err = db.Table("notification_recipients").Where("notification_id = ? AND student_email = ?", notificationId, studentEmail).Find(&recipient).Error
+	if recipient.NotificationID == 0 {
+		return nil, err
+	}
+	return recipient, err
+}
+
+// MarkInboxEntryAsRead sets when the notification was read, unless it was already read before
+func (*NotificationRepo) MarkInboxEntryAsRead(notificationId uint, studentEmail string, readAt time.Time, db *gorm.DB) (err error) {
+	err = db.Table("notification_recipients").Where("notification_id = ? AND student_email = ? AND read_at IS NULL", notificationId, studentEmail).Update("read_at", readAt).Error
+	return err
+}
+
+func (*NotificationRepo) DeleteAllNotifications(db *gorm.DB) error {
+	if err := db.Exec("DELETE FROM notification_recipients").Error; err != nil {
+		return err
+	}
+	return db.Exec("DELETE FROM notifications").Error
+}
+
+func inbox(studentEmail string, unreadOnly bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("notification_recipients.student_email = ?", studentEmail)
+		if unreadOnly {
+			db = db.Where("notification_recipients.read_at IS NULL")
+		}
+		return db
+	}
+}
@@ -18,21 +18,102 @@ func (*RegisterRelationshipRepo) CreateOneTeacherToManyStudentsRegisterRelations
 	registerRelationships := helpers.Map(studentEmails, func(studentEmail string) *models.RegisterRelationship {
 		return &models.RegisterRelationship{TeacherEmail: teacherEmail, StudentEmail: studentEmail}
 	})
-	err = db.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(&registerRelationships).Error
+	err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&registerRelationships).Error
 
 	return err
 }
 
 func (*RegisterRelationshipRepo) GetRelationshipsByTeacherEmail(teacherEmail string, db *gorm.DB) (relationships []*models.RegisterRelationship, err error) {
-	err = db.Table("register_relationships").Preload("Teacher").Preload("RegisteredStudent").Where("teacher_email = ?", teacherEmail).Find(&relationships).Error
+	err = db.Table("register_relationships").Preload("Teacher").Preload("RegisteredStudent").Where("teacher_email = ?", teacherEmail).Order("student_email, teacher_email").Find(&relationships).Error
 	return relationships, err
 }
 
 func (*RegisterRelationshipRepo) GetRelationshipsByTeacherEmails(teacherEmails []string, db *gorm.DB) (relationships []*models.RegisterRelationship, err error) {
-	err = db.Table("register_relationships").Preload("Teacher").Preload("RegisteredStudent").Where("teacher_email in ?", teacherEmails).Find(&relationships).Error
+	err = db.Table("register_relationships").Preload("Teacher").Preload("RegisteredStudent").Where("teacher_email in ?", teacherEmails).Order("student_email, teacher_email").Find(&relationships).Error
 	return relationships, err
 }
 
+// GetTeacherEmailsByStudentEmail returns the emails of the teachers the student is registered to, sorted by email
+func (*RegisterRelationshipRepo) GetTeacherEmailsByStudentEmail(studentEmail string, db *gorm.DB) (teacherEmails []string, err error) {
+	teacherEmails = make([]string, 0)
+	err = db.Table("register_relationships").Where("student_email = ?", studentEmail).Order("teacher_email").Pluck("teacher_email", &teacherEmails).Error
+	return teacherEmails, err
+}
+
+// GetStudentEmailsRegisteredToAtLeast returns the emails of the students registered to at least minTeacherCount of
+// the teachers, sorted by email. The students are counted by the database, grouping the relationships by student.
+// Only the emails after afterStudentEmail are returned, at most limit of them unless limit is not positive.
+func (*RegisterRelationshipRepo) GetStudentEmailsRegisteredToAtLeast(teacherEmails []string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) (studentEmails []string, err error) {
+	uniqueTeacherEmails := helpers.RemoveDuplicatesInStringSlice(teacherEmails)
+	studentEmails = make([]string, 0)
+	if len(uniqueTeacherEmails) == 0 {
+		return studentEmails, nil
+	}
+
+	err = db.Table("register_relationships").Where("teacher_email IN ?", uniqueTeacherEmails).
+		Scopes(emailPage("student_email", afterStudentEmail, limit)).
+		Group("student_email").Having("COUNT(DISTINCT teacher_email) >= ?", minTeacherCount).
+		Order("student_email").Pluck("student_email", &studentEmails).Error
+	return studentEmails, err
+}
+
+// GetStudentEmailsRegisteredOnlyTo returns the emails of the students registered to the teacher but to none of the
+// other teachers, sorted and paged like GetStudentEmailsRegisteredToAtLeast
+func (*RegisterRelationshipRepo) GetStudentEmailsRegisteredOnlyTo(teacherEmail string, otherTeacherEmails []string, afterStudentEmail string, limit int, db *gorm.DB) (studentEmails []string, err error) {
+	studentEmails = make([]string, 0)
+	query := db.Table("register_relationships").Where("teacher_email = ?", teacherEmail)
+	if len(otherTeacherEmails) > 0 {
+		otherStudents := db.Table("register_relationships").Select("student_email").Where("teacher_email IN ?", otherTeacherEmails)
+		query = query.Where("student_email NOT IN (?)", otherStudents)
+	}
+
+	err = query.Scopes(emailPage("student_email", afterStudentEmail, limit)).
+		Order("student_email").Pluck("student_email", &studentEmails).Error
+	return studentEmails, err
+}
+
+// GetRelationshipsAfter returns at most limit relationships sorted by teacher and then student, starting after the
+// relationship between afterTeacherEmail and afterStudentEmail. The teachers and students are not preloaded.
+func (*RegisterRelationshipRepo) GetRelationshipsAfter(afterTeacherEmail string, afterStudentEmail string, limit int, db *gorm.DB) (relationships []*models.RegisterRelationship, err error) {
+	relationships = make([]*models.RegisterRelationship, 0)
+	err = db.Table("register_relationships").
+		Where("teacher_email > ? OR (teacher_email = ? AND student_email > ?)", afterTeacherEmail, afterTeacherEmail, afterStudentEmail).
+		Order("teacher_email, student_email").Limit(limit).Find(&relationships).Error
+	return relationships, err
+}
+
+func (*RegisterRelationshipRepo) DeleteRegisterRelationships(teacherEmail string, studentEmails []string, db *gorm.DB) error {
+	return db.Exec("DELETE FROM register_relationships WHERE teacher_email = ? AND student_email in ?", teacherEmail, studentEmails).Error
+}
+
 func (*RegisterRelationshipRepo) DeleteAllRegisterRelationships(db *gorm.DB) error {
 	return db.Exec("DELETE FROM register_relationships").Error
 }
+
+// emailPage restricts a query to the rows whose email column sorts after afterEmail, at most limit of them unless
+// limit is not positive
+func emailPage(column string, afterEmail string, limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if afterEmail != "" {
+			db = db.Where(column+" > ?", afterEmail)
+		}
+		if limit > 0 {
+			db = db.Limit(limit)
+		}
+		return db
+	}
+}
+
+// idPage restricts a query to the rows whose id column is below beforeId unless it is 0, at most limit of them unless
+// limit is not positive
+func idPage(column string, beforeId uint, limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if beforeId != 0 {
+			db = db.Where(column+" < ?", beforeId)
+		}
+		if limit > 0 {
+			db = db.Limit(limit)
+		}
+		return db
+	}
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/models"
+	"time"
+)
+
+type StudentRepository interface {
+	CreateStudentsIfNotExist(studentEmails []string, db *gorm.DB) error
+	UpdateStudent(studentToUpdate *models.Student, db *gorm.DB) error
+	UpdateStudentSuspensionStatus(studentEmail string, isSuspended bool, db *gorm.DB) error
+	DeleteAllStudents(db *gorm.DB) error
+	GetStudentsByEmails(studentEmails []string, db *gorm.DB) ([]*models.Student, error)
+	GetStudentsAfterEmail(afterEmail string, limit int, db *gorm.DB) ([]*models.Student, error)
+	GetStudentByEmail(studentEmail string, db *gorm.DB) (*models.Student, error)
+	UpdateStudentProfile(studentEmail string, profile *models.Profile, db *gorm.DB) error
+	DeleteStudent(studentEmail string, db *gorm.DB) error
+}
+
+type TeacherRepository interface {
+	CreateTeachersIfNotExist(emails []string, db *gorm.DB) error
+	DeleteAllTeachers(db *gorm.DB) error
+	GetTeachersByEmails(teacherEmails []string, db *gorm.DB) ([]*models.Teacher, error)
+	GetTeachersAfterEmail(afterEmail string, limit int, db *gorm.DB) ([]*models.Teacher, error)
+	GetTeacherByEmail(teacherEmail string, db *gorm.DB) (*models.Teacher, error)
+	UpdateTeacherProfile(teacherEmail string, profile *models.Profile, db *gorm.DB) error
+	DeleteTeacher(teacherEmail string, db *gorm.DB) error
+}
+
+type RegistrationRepository interface {
+	CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists(teacherEmail string, studentEmails []string, db *gorm.DB) error
+	GetRelationshipsByTeacherEmail(teacherEmail string, db *gorm.DB) ([]*models.RegisterRelationship, error)
+	GetRelationshipsByTeacherEmails(teacherEmails []string, db *gorm.DB) ([]*models.RegisterRelationship, error)
+	GetStudentEmailsRegisteredToAtLeast(teacherEmails []string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error)
+	GetRelationshipsAfter(afterTeacherEmail string, afterStudentEmail string, limit int, db *gorm.DB) ([]*models.RegisterRelationship, error)
+	GetStudentEmailsRegisteredOnlyTo(teacherEmail string, otherTeacherEmails []string, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error)
+	GetTeacherEmailsByStudentEmail(studentEmail string, db *gorm.DB) ([]string, error)
+	DeleteRegisterRelationships(teacherEmail string, studentEmails []string, db *gorm.DB) error
+	DeleteAllRegisterRelationships(db *gorm.DB) error
+}
+
+type StudentSuspensionRepository interface {
+	CreateSuspension(suspension *models.StudentSuspension, db *gorm.DB) error
+	GetActiveSuspensionByStudentEmail(studentEmail string, at time.Time, db *gorm.DB) (*models.StudentSuspension, error)
+	GetActivelySuspendedStudentEmails(studentEmails []string, at time.Time, db *gorm.DB) ([]string, error)
+	GetExpiredSuspensions(at time.Time, db *gorm.DB) ([]*models.StudentSuspension, error)
+	LiftSuspension(suspensionId uint, liftedAt time.Time, db *gorm.DB) error
+	UpdateSuspensionUntil(suspensionId uint, until *time.Time, db *gorm.DB) error
+	LiftActiveSuspensions(studentEmail string, liftedAt time.Time, db *gorm.DB) error
+	GetSuspensionsByStudentEmail(studentEmail string, db *gorm.DB) ([]*models.StudentSuspension, error)
+	DeleteAllSuspensions(db *gorm.DB) error
+}
+
+type NotificationRepository interface {
+	CreateNotification(notification *models.Notification, recipientEmails []string, db *gorm.DB) error
+	GetNotificationById(notificationId uint, db *gorm.DB) (*models.Notification, error)
+	GetRecipientEmails(notificationId uint, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error)
+	GetInboxByStudentEmail(studentEmail string, unreadOnly bool, beforeNotificationId uint, limit int, db *gorm.DB) ([]*models.NotificationRecipient, error)
+	CountInboxByStudentEmail(studentEmail string, unreadOnly bool, db *gorm.DB) (int64, error)
+	GetInboxEntry(notificationId uint, studentEmail string, db *gorm.DB) (*models.NotificationRecipient, error)
+	MarkInboxEntryAsRead(notificationId uint, studentEmail string, readAt time.Time, db *gorm.DB) error
+	DeleteAllNotifications(db *gorm.DB) error
+}
+
+type NotificationDeliveryRepository interface {
+	CreateDeliveries(notificationId uint, studentEmails []string, nextAttemptAt time.Time, db *gorm.DB) error
+	GetDueDeliveries(at time.Time, limit int, db *gorm.DB) ([]*models.NotificationDelivery, error)
+	UpdateDelivery(delivery *models.NotificationDelivery, db *gorm.DB) error
+	GetDeliveriesByNotificationId(notificationId uint, db *gorm.DB) ([]*models.NotificationDelivery, error)
+	DeleteAllDeliveries(db *gorm.DB) error
+}
+
+type WebhookSubscriptionRepository interface {
+	CreateSubscription(subscription *models.WebhookSubscription, db *gorm.DB) error
+	GetSubscriptionById(subscriptionId uint, db *gorm.DB) (*models.WebhookSubscription, error)
+	GetAllSubscriptions(db *gorm.DB) ([]*models.WebhookSubscription, error)
+	DeleteSubscription(subscriptionId uint, db *gorm.DB) error
+	DeleteAllSubscriptions(db *gorm.DB) error
+}
+
+type WebhookDeliveryRepository interface {
+	CreateWebhookDeliveries(deliveries []*models.WebhookDelivery, db *gorm.DB) error
+	GetDueWebhookDeliveries(at time.Time, limit int, db *gorm.DB) ([]*models.WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *models.WebhookDelivery, db *gorm.DB) error
+	GetWebhookDeliveriesBySubscriptionId(subscriptionId uint, beforeId uint, limit int, db *gorm.DB) ([]*models.WebhookDelivery, error)
+	CountWebhookDeliveriesBySubscriptionId(subscriptionId uint, db *gorm.DB) (int64, error)
+	DeleteWebhookDeliveriesBySubscriptionId(subscriptionId uint, db *gorm.DB) error
+	DeleteAllWebhookDeliveries(db *gorm.DB) error
+}
+
+// CourseRepository stores the courses together with their sections, the teachers assigned to the sections and the
+// students enrolled in them
+type CourseRepository interface {
+	SaveCourse(course *models.Course, db *gorm.DB) error
+	GetCourseByCode(courseCode string, db *gorm.DB) (*models.Course, error)
+	GetAllCourses(db *gorm.DB) ([]*models.Course, error)
+	CreateSectionIfNotExists(courseCode string, sectionName string, db *gorm.DB) (*models.Section, error)
+	GetSection(courseCode string, sectionName string, db *gorm.DB) (*models.Section, error)
+	GetSectionsByCourseCode(courseCode string, db *gorm.DB) ([]*models.Section, error)
+	GetSectionsByTeacherEmail(teacherEmail string, courseCode string, db *gorm.DB) ([]*models.Section, error)
+	AssignTeachersToSectionIfNotAssigned(sectionId uint, teacherEmails []string, db *gorm.DB) error
+	GetTeacherEmailsBySectionId(sectionId uint, db *gorm.DB) ([]string, error)
+	EnrollStudentsIfNotEnrolled(sectionId uint, studentEmails []string, db *gorm.DB) error
+	GetStudentEmailsBySectionId(sectionId uint, db *gorm.DB) ([]string, error)
+	GetStudentEmailsTaughtByAtLeast(teacherEmails []string, courseCode string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error)
+	GetStudentEmailsTaughtOnlyBy(teacherEmail string, otherTeacherEmails []string, courseCode string, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error)
+	DeleteAllCourses(db *gorm.DB) error
+}
+
+// AssignmentRepository stores the assignments of the teachers and the latest submission of each student for them
+type AssignmentRepository interface {
+	CreateAssignment(assignment *models.Assignment, db *gorm.DB) error
+	GetAssignmentById(assignmentId uint, db *gorm.DB) (*models.Assignment, error)
+	GetAssignmentsByTeacherEmail(teacherEmail string, db *gorm.DB) ([]*models.Assignment, error)
+	SaveSubmission(submission *models.Submission, db *gorm.DB) error
+	GetSubmissionsByAssignmentIds(assignmentIds []uint, db *gorm.DB) ([]*models.Submission, error)
+	DeleteAllAssignments(db *gorm.DB) error
+}
+
+// GradebookRepository stores the weighted grade categories of the teachers and the grades they give their students
+type GradebookRepository interface {
+	SaveCategory(category *models.GradeCategory, db *gorm.DB) error
+	GetCategory(teacherEmail string, name string, db *gorm.DB) (*models.GradeCategory, error)
+	GetCategoriesByTeacherEmail(teacherEmail string, db *gorm.DB) ([]*models.GradeCategory, error)
+	CreateGrade(grade *models.Grade, db *gorm.DB) error
+	GetGrades(teacherEmail string, studentEmail string, db *gorm.DB) ([]*models.Grade, error)
+	DeleteAllGrades(db *gorm.DB) error
+}
+
+// AttendanceRepository stores the attendance teachers record for their students, by date. The dates of a range are
+// formatted like the dates of the attendances, and an empty date leaves its end of the range open.
+type AttendanceRepository interface {
+	SaveAttendances(attendances []*models.Attendance, db *gorm.DB) error
+	GetAttendancesByDate(teacherEmail string, date string, db *gorm.DB) ([]*models.Attendance, error)
+	GetAttendancesBetween(teacherEmail string, fromDate string, toDate string, db *gorm.DB) ([]*models.Attendance, error)
+	GetStudentAttendancesBetween(teacherEmail string, studentEmail string, fromDate string, toDate string, db *gorm.DB) ([]*models.Attendance, error)
+	DeleteAllAttendances(db *gorm.DB) error
+}
+
+// Transactor runs a unit of work atomically against the store backing the repositories
+type Transactor interface {
+	Transaction(db *gorm.DB, function func(tx *gorm.DB) error) error
+}
+
+// Repositories bundles the repositories and transactor that a transaction manager operates on
+type Repositories struct {
+	Students          StudentRepository
+	Teachers          TeacherRepository
+	Registrations     RegistrationRepository
+	Suspensions       StudentSuspensionRepository
+	Notifications     NotificationRepository
+	Deliveries        NotificationDeliveryRepository
+	Webhooks          WebhookSubscriptionRepository
+	WebhookDeliveries WebhookDeliveryRepository
+	Courses           CourseRepository
+	Assignments       AssignmentRepository
+	Gradebooks        GradebookRepository
+	Attendances       AttendanceRepository
+	Transactor        Transactor
+}
+
+// NewSqlRepositories returns repositories backed by the gorm connection passed into each call
+func NewSqlRepositories() *Repositories {
+	return &Repositories{
+		Students:          NewStudentRepo(),
+		Teachers:          NewTeacherRepo(),
+		Registrations:     NewRegisterRelationshipRepo(),
+		Suspensions:       NewStudentSuspensionRepo(),
+		Notifications:     NewNotificationRepo(),
+		Deliveries:        NewNotificationDeliveryRepo(),
+		Webhooks:          NewWebhookSubscriptionRepo(),
+		WebhookDeliveries: NewWebhookDeliveryRepo(),
+		Courses:           NewCourseRepo(),
+		Assignments:       NewAssignmentRepo(),
+		Gradebooks:        NewGradebookRepo(),
+		Attendances:       NewAttendanceRepo(),
+		Transactor:        &GormTransactor{},
+	}
+}
+
+// NewInMemoryRepositories returns repositories sharing a fresh in-memory store, the gorm connection passed
+// into each call is ignored and may be nil
+func NewInMemoryRepositories() *Repositories {
+	store := NewInMemoryStore()
+	return &Repositories{
+		Students:          &InMemoryStudentRepo{store: store},
+		Teachers:          &InMemoryTeacherRepo{store: store},
+		Registrations:     &InMemoryRegisterRelationshipRepo{store: store},
+		Suspensions:       &InMemoryStudentSuspensionRepo{store: store},
+		Notifications:     &InMemoryNotificationRepo{store: store},
+		Deliveries:        &InMemoryNotificationDeliveryRepo{store: store},
+		Webhooks:          &InMemoryWebhookSubscriptionRepo{store: store},
+		WebhookDeliveries: &InMemoryWebhookDeliveryRepo{store: store},
+		Courses:           &InMemoryCourseRepo{store: store},
+		Assignments:       &InMemoryAssignmentRepo{store: store},
+		Gradebooks:        &InMemoryGradebookRepo{store: store},
+		Attendances:       &InMemoryAttendanceRepo{store: store},
+		Transactor:        store,
+	}
+}
+
+type GormTransactor struct{}
+
+func (*GormTransactor) Transaction(db *gorm.DB, function func(tx *gorm.DB) error) error {
+	return db.Transaction(function)
+}
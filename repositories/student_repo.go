@@ -18,7 +18,7 @@ func (*StudentRepo) CreateStudentsIfNotExist(studentEmails []string, db *gorm.DB
 	students := helpers.Map(studentEmails, func(studentEmail string) *models.Student {
 		return &models.Student{Email: studentEmail, IsSuspended: false}
 	})
-	err = db.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(students).Error
+	err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(students).Error
 	return err
 }
 
@@ -30,12 +30,25 @@ func (*StudentRepo) UpdateStudent(studentToUpdate *models.Student, db *gorm.DB)
 	return err
 }
 
+// UpdateStudentSuspensionStatus sets the suspension flag explicitly, as UpdateStudent skips false values
+func (*StudentRepo) UpdateStudentSuspensionStatus(studentEmail string, isSuspended bool, db *gorm.DB) (err error) {
+	err = db.Table("students").Where("email = ?", studentEmail).Update("is_suspended", isSuspended).Error
+	return err
+}
+
 func (*StudentRepo) DeleteAllStudents(db *gorm.DB) error {
 	return db.Exec("DELETE FROM students").Error
 }
 
 func (*StudentRepo) GetStudentsByEmails(studentEmails []string, db *gorm.DB) (students []*models.Student, err error) {
-	err = db.Table("students").Where("students.email in ?", studentEmails).Find(&students).Error
+	err = db.Table("students").Where("students.email in ?", studentEmails).Order("students.email").Find(&students).Error
+	return students, err
+}
+
+// GetStudentsAfterEmail returns at most limit students sorted by email, starting after afterEmail
+func (*StudentRepo) GetStudentsAfterEmail(afterEmail string, limit int, db *gorm.DB) (students []*models.Student, err error) {
+	students = make([]*models.Student, 0)
+	err = db.Table("students").Scopes(emailPage("email", afterEmail, limit)).Order("email").Find(&students).Error
 	return students, err
 }
 
@@ -47,3 +60,33 @@ func (*StudentRepo) GetStudentByEmail(studentEmail string, db *gorm.DB) (student
 	}
 	return student, err
 }
+
+// UpdateStudentProfile replaces every field of the profile of the student, including the empty ones
+func (*StudentRepo) UpdateStudentProfile(studentEmail string, profile *models.Profile, db *gorm.DB) (err error) {
+	err = db.Table("students").Where("email = ?", studentEmail).Updates(profileColumns(profile)).Error
+	return err
+}
+
+// DeleteStudent deletes the student together with everything recorded for them: their registrations, suspensions,
+// notifications, enrollments, submissions, grades and attendances
+func (*StudentRepo) DeleteStudent(studentEmail string, db *gorm.DB) error {
+	for _, table := range []string{"notification_deliveries", "notification_recipients", "student_suspensions", "register_relationships",
+		"enrollments", "submissions", "grades", "attendances"} {
+		if err := db.Exec("DELETE FROM "+table+" WHERE student_email = ?", studentEmail).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec("DELETE FROM students WHERE email = ?", studentEmail).Error
+}
+
+// profileColumns maps the columns of a profile to their values, so that updating them also clears the empty ones
+func profileColumns(profile *models.Profile) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         profile.Name,
+		"display_name": profile.DisplayName,
+		"grade_level":  profile.GradeLevel,
+		"homeroom":     profile.Homeroom,
+		"phone":        profile.Phone,
+		"metadata":     profile.Metadata,
+	}
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/models"
+	"time"
+)
+
+type StudentSuspensionRepo struct{}
+
+func NewStudentSuspensionRepo() *StudentSuspensionRepo {
+	return &StudentSuspensionRepo{}
+}
+
+func (*StudentSuspensionRepo) CreateSuspension(suspension *models.StudentSuspension, db *gorm.DB) (err error) {
+	err = db.Omit("Student").Create(suspension).Error
+	return err
+}
+
+// activeAt restricts a query to suspensions that have not been lifted and have not expired at the given time
+func activeAt(at time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("lifted_at IS NULL AND (until IS NULL OR until > ?)", at)
+	}
+}
+
+func (*StudentSuspensionRepo) GetActiveSuspensionByStudentEmail(studentEmail string, at time.Time, db *gorm.DB) (suspension *models.StudentSuspension, err error) {
+	suspension = &models.StudentSuspension{}
+	err = db.Table("student_suspensions").Scopes(activeAt(at)).Where("student_email = ?", studentEmail).Order("suspended_at, id").Limit(1).Find(&suspension).Error
+	if suspension.ID == 0 {
+		return nil, err
+	}
+	return suspension, err
+}
+
+func (*StudentSuspensionRepo) GetActivelySuspendedStudentEmails(studentEmails []string, at time.Time, db *gorm.DB) (suspendedStudentEmails []string, err error) {
+	err = db.Table("student_suspensions").Scopes(activeAt(at)).Where("student_email in ?", studentEmails).Distinct().Order("student_email").Pluck("student_email", &suspendedStudentEmails).Error
+	return suspendedStudentEmails, err
+}
+
+// GetExpiredSuspensions returns suspensions whose end has passed at the given time but that have not been lifted yet
+func (*StudentSuspensionRepo) GetExpiredSuspensions(at time.Time, db *gorm.DB) (suspensions []*models.StudentSuspension, err error) {
+	err = db.Table("student_suspensions").Where("lifted_at IS NULL AND until <= ?", at).Order("until, id").Find(&suspensions).Error
+	return suspensions, err
+}
+
+func (*StudentSuspensionRepo) LiftSuspension(suspensionId uint, liftedAt time.Time, db *gorm.DB) (err error) {
+	err = db.Table("student_suspensions").Where("id = ?", suspensionId).Update("lifted_at", liftedAt).Error
+	return err
+}
+
+// UpdateSuspensionUntil changes when the suspension ends, a nil until makes it last until it is lifted
+func (*StudentSuspensionRepo) UpdateSuspensionUntil(suspensionId uint, until *time.Time, db *gorm.DB) (err error) {
+	err = db.Table("student_suspensions").Where("id = ?", suspensionId).Update("until", until).Error
+	return err
+}
+
+func (*StudentSuspensionRepo) LiftActiveSuspensions(studentEmail string, liftedAt time.Time, db *gorm.DB) (err error) {
+	err = db.Table("student_suspensions").Where("student_email = ? AND lifted_at IS NULL", studentEmail).Update("lifted_at", liftedAt).Error
+	return err
+}
+
+func (*StudentSuspensionRepo) GetSuspensionsByStudentEmail(studentEmail string, db *gorm.DB) (suspensions []*models.StudentSuspension, err error) {
+	err = db.Table("student_suspensions").Where("student_email = ?", studentEmail).Order("suspended_at, id").Find(&suspensions).Error
+	return suspensions, err
+}
+
+func (*StudentSuspensionRepo) DeleteAllSuspensions(db *gorm.DB) error {
+	return db.Exec("DELETE FROM student_suspensions").Error
+}
@@ -18,7 +18,7 @@ func (*TeacherRepo) CreateTeachersIfNotExist(emails []string, db *gorm.DB) (err
 	teachers := helpers.Map(emails, func(email string) *models.Teacher {
 		return &models.Teacher{Email: email}
 	})
-	err = db.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(teachers).Error
+	err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(teachers).Error
 	return err
 }
 
@@ -27,7 +27,14 @@ func (*TeacherRepo) DeleteAllTeachers(db *gorm.DB) error {
 }
 
 func (*TeacherRepo) GetTeachersByEmails(teacherEmails []string, db *gorm.DB) (teachers []*models.Teacher, err error) {
-	err = db.Table("teachers").Where("teachers.email in ?", teacherEmails).Find(&teachers).Error
+	err = db.Table("teachers").Where("teachers.email in ?", teacherEmails).Order("teachers.email").Find(&teachers).Error
+	return teachers, err
+}
+
+// GetTeachersAfterEmail returns at most limit teachers sorted by email, starting after afterEmail
+func (*TeacherRepo) GetTeachersAfterEmail(afterEmail string, limit int, db *gorm.DB) (teachers []*models.Teacher, err error) {
+	teachers = make([]*models.Teacher, 0)
+	err = db.Table("teachers").Scopes(emailPage("email", afterEmail, limit)).Order("email").Find(&teachers).Error
 	return teachers, err
 }
 
@@ -39,3 +46,32 @@ func (*TeacherRepo) GetTeacherByEmail(teacherEmail string, db *gorm.DB) (teacher
 	}
 	return teacher, err
 }
+
+// UpdateTeacherProfile replaces every field of the profile of the teacher, including the empty ones
+func (*TeacherRepo) UpdateTeacherProfile(teacherEmail string, profile *models.Profile, db *gorm.DB) (err error) {
+	err = db.Table("teachers").Where("email = ?", teacherEmail).Updates(profileColumns(profile)).Error
+	return err
+}
+
+// DeleteTeacher deletes the teacher together with everything they recorded: their notifications, assignments, grade
+// categories and grades, attendances, registrations and assignments to sections
+func (*TeacherRepo) DeleteTeacher(teacherEmail string, db *gorm.DB) error {
+	for _, statement := range []string{
+		"DELETE FROM notification_deliveries WHERE notification_id IN (SELECT id FROM notifications WHERE teacher_email = ?)",
+		"DELETE FROM notification_recipients WHERE notification_id IN (SELECT id FROM notifications WHERE teacher_email = ?)",
+		"DELETE FROM notifications WHERE teacher_email = ?",
+		"DELETE FROM grades WHERE teacher_email = ?",
+		"DELETE FROM grade_categories WHERE teacher_email = ?",
+		"DELETE FROM submissions WHERE assignment_id IN (SELECT id FROM assignments WHERE teacher_email = ?)",
+		"DELETE FROM assignments WHERE teacher_email = ?",
+		"DELETE FROM attendances WHERE teacher_email = ?",
+		"DELETE FROM section_teachers WHERE teacher_email = ?",
+		"DELETE FROM register_relationships WHERE teacher_email = ?",
+		"DELETE FROM teachers WHERE email = ?",
+	} {
+		if err := db.Exec(statement, teacherEmail).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
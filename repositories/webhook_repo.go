@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/models"
+	"time"
+)
+
+type WebhookSubscriptionRepo struct{}
+
+func NewWebhookSubscriptionRepo() *WebhookSubscriptionRepo {
+	return &WebhookSubscriptionRepo{}
+}
+
+func (*WebhookSubscriptionRepo) CreateSubscription(subscription *models.WebhookSubscription, db *gorm.DB) (err error) {
+	err = db.Create(subscription).Error
+	return err
+}
+
+func (*WebhookSubscriptionRepo) GetSubscriptionById(subscriptionId uint, db *gorm.DB) (subscription *models.WebhookSubscription, err error) {
+	subscription = &models.WebhookSubscription{}
+	err = db.Table("webhook_subscriptions").Where("id = ?", subscriptionId).Find(&subscription).Error
+	if subscription.ID == 0 {
+		return nil, err
+	}
+	return subscription, err
+}
+
+func (*WebhookSubscriptionRepo) GetAllSubscriptions(db *gorm.DB) (subscriptions []*models.WebhookSubscription, err error) {
+	err = db.Table("webhook_subscriptions").Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (*WebhookSubscriptionRepo) DeleteSubscription(subscriptionId uint, db *gorm.DB) error {
+	return db.Where("id = ?", subscriptionId).Delete(&models.WebhookSubscription{}).Error
+}
+
+func (*WebhookSubscriptionRepo) DeleteAllSubscriptions(db *gorm.DB) error {
+	return db.Exec("DELETE FROM webhook_subscriptions").Error
+}
+
+type WebhookDeliveryRepo struct{}
+
+func NewWebhookDeliveryRepo() *WebhookDeliveryRepo {
+	return &WebhookDeliveryRepo{}
+}
+
+func (*WebhookDeliveryRepo) CreateWebhookDeliveries(deliveries []*models.WebhookDelivery, db *gorm.DB) (err error) {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err = db.Omit("Subscription").Create(&deliveries).Error
+	return err
+}
+
+// GetDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due, oldest first, with their
+// subscription preloaded
+func (*WebhookDeliveryRepo) GetDueWebhookDeliveries(at time.Time, limit int, db *gorm.DB) (deliveries []*models.WebhookDelivery, err error) {
+	err = db.Table("webhook_deliveries").Preload("Subscription").Where("status = ? AND next_attempt_at <= ?", models.DELIVERY_PENDING, at).
+		Order("next_attempt_at, id").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (*WebhookDeliveryRepo) UpdateWebhookDelivery(delivery *models.WebhookDelivery, db *gorm.DB) (err error) {
+	err = db.Table("webhook_deliveries").Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"response_status": delivery.ResponseStatus,
+		"last_error":      delivery.LastError,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"delivered_at":    delivery.DeliveredAt,
+	}).Error
+	return err
+}
+
+// GetWebhookDeliveriesBySubscriptionId returns the newest deliveries to a subscription first, starting before the
+// delivery with id beforeId unless it is 0
+func (*WebhookDeliveryRepo) GetWebhookDeliveriesBySubscriptionId(subscriptionId uint, beforeId uint, limit int, db *gorm.DB) (deliveries []*models.WebhookDelivery, err error) {
+	err = db.Table("webhook_deliveries").Where("subscription_id = ?", subscriptionId).
+		Scopes(idPage("id", beforeId, limit)).Order("id DESC").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (*WebhookDeliveryRepo) CountWebhookDeliveriesBySubscriptionId(subscriptionId uint, db *gorm.DB) (count int64, err error) {
+	err = db.Table("webhook_deliveries").Where("subscription_id = ?", subscriptionId).Count(&count).Error
+	return count, err
+}
+
+func (*WebhookDeliveryRepo) DeleteWebhookDeliveriesBySubscriptionId(subscriptionId uint, db *gorm.DB) error {
+	return db.Where("subscription_id = ?", subscriptionId).Delete(&models.WebhookDelivery{}).Error
+}
+
+func (*WebhookDeliveryRepo) DeleteAllWebhookDeliveries(db *gorm.DB) error {
+	return db.Exec("DELETE FROM webhook_deliveries").Error
+}
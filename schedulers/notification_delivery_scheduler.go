@@ -0,0 +1,50 @@
+package schedulers
+
+import (
+	"learning-management-system/database"
+	"learning-management-system/notifiers"
+	"learning-management-system/transaction_managers"
+	"log"
+	"time"
+)
+
+// NotificationDeliveryScheduler delivers queued notifications through a notifier, right after they are queued and
+// periodically to retry failed deliveries
+type NotificationDeliveryScheduler struct {
+	connection         *database.Connection
+	transactionManager *transaction_managers.TransactionManager
+	notifier           notifiers.Notifier
+	maxAttempts        int
+	retryBackoff       time.Duration
+	runner             *periodicRunner
+}
+
+func NewNotificationDeliveryScheduler(connection *database.Connection, transactionManager *transaction_managers.TransactionManager, notifier notifiers.Notifier,
+	interval time.Duration, maxAttempts int, retryBackoff time.Duration) *NotificationDeliveryScheduler {
+	scheduler := &NotificationDeliveryScheduler{
+		connection:         connection,
+		transactionManager: transactionManager,
+		notifier:           notifier,
+		maxAttempts:        maxAttempts,
+		retryBackoff:       retryBackoff,
+	}
+	scheduler.runner = newPeriodicRunner(interval, transactionManager.NotificationsQueued(), scheduler.deliverPendingNotifications)
+	return scheduler
+}
+
+// Start delivers pending notifications right away, then whenever notifications are queued and once every interval
+// until Stop is called
+func (scheduler *NotificationDeliveryScheduler) Start() {
+	scheduler.runner.start()
+}
+
+// Stop waits for a run in progress to finish and stops the scheduler
+func (scheduler *NotificationDeliveryScheduler) Stop() {
+	scheduler.runner.stopAndWait()
+}
+
+func (scheduler *NotificationDeliveryScheduler) deliverPendingNotifications() {
+	if err := scheduler.transactionManager.DeliverPendingNotifications(scheduler.notifier, scheduler.maxAttempts, scheduler.retryBackoff, time.Now(), scheduler.connection); err != nil {
+		log.Printf("Error delivering notifications : error=%v", err)
+	}
+}
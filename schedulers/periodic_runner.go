@@ -0,0 +1,51 @@
+package schedulers
+
+import (
+	"sync"
+	"time"
+)
+
+// periodicRunner runs a job right away and then once every interval, or earlier whenever trigger receives a value,
+// until it is stopped
+type periodicRunner struct {
+	interval time.Duration
+	trigger  <-chan struct{}
+	job      func()
+	stop     chan struct{}
+	stopped  sync.WaitGroup
+}
+
+func newPeriodicRunner(interval time.Duration, trigger <-chan struct{}, job func()) *periodicRunner {
+	return &periodicRunner{
+		interval: interval,
+		trigger:  trigger,
+		job:      job,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (runner *periodicRunner) start() {
+	runner.stopped.Add(1)
+	go func() {
+		defer runner.stopped.Done()
+
+		ticker := time.NewTicker(runner.interval)
+		defer ticker.Stop()
+
+		for {
+			runner.job()
+			select {
+			case <-ticker.C:
+			case <-runner.trigger:
+			case <-runner.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopAndWait waits for a run in progress to finish and stops the runner
+func (runner *periodicRunner) stopAndWait() {
+	close(runner.stop)
+	runner.stopped.Wait()
+}
@@ -0,0 +1,40 @@
+package schedulers
+
+import (
+	"learning-management-system/database"
+	"learning-management-system/transaction_managers"
+	"log"
+	"time"
+)
+
+// SuspensionExpiryScheduler periodically lifts suspensions whose end has passed
+type SuspensionExpiryScheduler struct {
+	connection         *database.Connection
+	transactionManager *transaction_managers.TransactionManager
+	runner             *periodicRunner
+}
+
+func NewSuspensionExpiryScheduler(connection *database.Connection, transactionManager *transaction_managers.TransactionManager, interval time.Duration) *SuspensionExpiryScheduler {
+	scheduler := &SuspensionExpiryScheduler{
+		connection:         connection,
+		transactionManager: transactionManager,
+	}
+	scheduler.runner = newPeriodicRunner(interval, nil, scheduler.liftExpiredSuspensions)
+	return scheduler
+}
+
+// Start lifts expired suspensions right away and then once every interval until Stop is called
+func (scheduler *SuspensionExpiryScheduler) Start() {
+	scheduler.runner.start()
+}
+
+// Stop waits for a run in progress to finish and stops the scheduler
+func (scheduler *SuspensionExpiryScheduler) Stop() {
+	scheduler.runner.stopAndWait()
+}
+
+func (scheduler *SuspensionExpiryScheduler) liftExpiredSuspensions() {
+	if err := scheduler.transactionManager.LiftExpiredSuspensions(time.Now(), scheduler.connection); err != nil {
+		log.Printf("Error lifting expired suspensions : error=%v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package schedulers
+
+import (
+	"learning-management-system/database"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/webhooks"
+	"log"
+	"time"
+)
+
+// WebhookDeliveryScheduler posts queued webhook events to their subscriptions, right after they are queued and
+// periodically to retry failed deliveries
+type WebhookDeliveryScheduler struct {
+	connection         *database.Connection
+	transactionManager *transaction_managers.TransactionManager
+	sender             webhooks.Sender
+	maxAttempts        int
+	retryBackoff       time.Duration
+	runner             *periodicRunner
+}
+
+func NewWebhookDeliveryScheduler(connection *database.Connection, transactionManager *transaction_managers.TransactionManager, sender webhooks.Sender,
+	interval time.Duration, maxAttempts int, retryBackoff time.Duration) *WebhookDeliveryScheduler {
+	scheduler := &WebhookDeliveryScheduler{
+		connection:         connection,
+		transactionManager: transactionManager,
+		sender:             sender,
+		maxAttempts:        maxAttempts,
+		retryBackoff:       retryBackoff,
+	}
+	scheduler.runner = newPeriodicRunner(interval, transactionManager.WebhooksQueued(), scheduler.deliverPendingWebhooks)
+	return scheduler
+}
+
+// Start delivers pending webhooks right away, then whenever webhooks are queued and once every interval
+// until Stop is called
+func (scheduler *WebhookDeliveryScheduler) Start() {
+	scheduler.runner.start()
+}
+
+// Stop waits for a run in progress to finish and stops the scheduler
+func (scheduler *WebhookDeliveryScheduler) Stop() {
+	scheduler.runner.stopAndWait()
+}
+
+func (scheduler *WebhookDeliveryScheduler) deliverPendingWebhooks() {
+	if err := scheduler.transactionManager.DeliverPendingWebhooks(scheduler.sender, scheduler.maxAttempts, scheduler.retryBackoff, time.Now(), scheduler.connection); err != nil {
+		log.Printf("Error delivering webhooks : error=%v", err)
+	}
+}
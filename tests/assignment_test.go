@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"fmt"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"testing"
+	"time"
+)
+
+func createTestAssignment(teacherEmail string, title string, dueAt time.Time, t *testing.T) *types.AssignmentResponse {
+	assignment := &types.AssignmentResponse{}
+	testPostJson(fmt.Sprintf(`{"title": %q, "description": "chapter 1", "due_at": %q, "max_score": 10}`, title, dueAt.Format(time.RFC3339)),
+		"/api/v2/teachers/"+teacherEmail+"/assignments", 201, assignment, t)
+	return assignment
+}
+
+func TestAssignmentsAndSubmissions(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", "studenthon@gmail.com", "studentbob@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "/api/register", 204, "", t)
+
+	past := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	future := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	overdue := createTestAssignment("teacherken@gmail.com", "quiz", past, t)
+	upcoming := createTestAssignment("teacherken@gmail.com", "essay", future, t)
+	assertEquals(t, "teacherken@gmail.com", overdue.TeacherEmail)
+	assertEquals(t, 10, overdue.MaxScore)
+	assertEquals(t, true, overdue.DueAt.Equal(past))
+
+	retrieved := &types.AssignmentResponse{}
+	testGetJson(fmt.Sprintf("/api/v1/assignments/%d", upcoming.AssignmentId), 200, retrieved, t)
+	assertEquals(t, "essay", retrieved.Title)
+	assignments := &types.RetrieveAssignmentsResponse{}
+	testGetJson("/api/teachers/teacherken@gmail.com/assignments", 200, assignments, t)
+	assertEquals(t, 2, len(assignments.Assignments))
+	assertEquals(t, overdue.AssignmentId, assignments.Assignments[0].AssignmentId)
+
+	// the submission after the due date is accepted as late, submitting again replaces the work
+	submission := &types.SubmissionResponse{}
+	testRequestJson("PUT", fmt.Sprintf("/api/assignments/%d/submissions/studentjon@gmail.com", overdue.AssignmentId), `{"text": "answers"}`, 200, submission, t)
+	assertEquals(t, models.WORK_LATE, submission.Status)
+	assertEquals(t, "answers", submission.Text)
+	testRequestJson("PUT", fmt.Sprintf("/api/assignments/%d/submissions/studentjon@gmail.com", upcoming.AssignmentId), `{"file": "s3://essays/jon.pdf"}`, 200, submission, t)
+	assertEquals(t, models.WORK_SUBMITTED, submission.Status)
+	assertEquals(t, "s3://essays/jon.pdf", submission.FileReference)
+	submission = &types.SubmissionResponse{}
+	testRequestJson("PUT", fmt.Sprintf("/api/v1/assignments/%d/submissions/studentjon@gmail.com", upcoming.AssignmentId), `{"text": "draft 2"}`, 200, submission, t)
+	assertEquals(t, "", submission.FileReference)
+
+	submissions := &types.RetrieveSubmissionsResponse{}
+	testGetJson(fmt.Sprintf("/api/assignments/%d/submissions", upcoming.AssignmentId), 200, submissions, t)
+	assertEquals(t, 2, len(submissions.Submissions))
+	submission = submissions.Submissions[0]
+	assertEquals(t, "studenthon@gmail.com", submission.StudentEmail)
+	assertEquals(t, models.WORK_PENDING, submission.Status)
+	assertEquals(t, (*time.Time)(nil), submission.SubmittedAt)
+	submission = submissions.Submissions[1]
+	assertEquals(t, "studentjon@gmail.com", submission.StudentEmail)
+	assertEquals(t, models.WORK_SUBMITTED, submission.Status)
+	assertEquals(t, "draft 2", submission.Text)
+	assertEquals(t, "", submission.FileReference)
+
+	statuses := func(status string) []string {
+		submissions := &types.RetrieveSubmissionsResponse{}
+		testGetJson("/api/v2/teachers/teacherken@gmail.com/submissions?status="+status, 200, submissions, t)
+		result := make([]string, 0)
+		for _, submission := range submissions.Submissions {
+			result = append(result, fmt.Sprintf("%d %s %s", submission.AssignmentId, submission.StudentEmail, submission.Status))
+		}
+		return result
+	}
+	assertEquals(t, []string{
+		fmt.Sprintf("%d studenthon@gmail.com missing", overdue.AssignmentId),
+		fmt.Sprintf("%d studentjon@gmail.com late", overdue.AssignmentId),
+		fmt.Sprintf("%d studenthon@gmail.com pending", upcoming.AssignmentId),
+		fmt.Sprintf("%d studentjon@gmail.com submitted", upcoming.AssignmentId),
+	}, statuses(""))
+	assertEquals(t, []string{fmt.Sprintf("%d studentjon@gmail.com late", overdue.AssignmentId)}, statuses(models.WORK_LATE))
+	assertEquals(t, []string{fmt.Sprintf("%d studenthon@gmail.com pending", upcoming.AssignmentId)}, statuses(models.WORK_PENDING))
+
+	// the work submitted before a student is unregistered is kept, while the work they no longer owe is not listed
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "/api/unregister", 200,
+		`{"unregistered":["studentjon@gmail.com","studenthon@gmail.com"],"not_registered":[]}`, t)
+	assertEquals(t, []string{
+		fmt.Sprintf("%d studentjon@gmail.com late", overdue.AssignmentId),
+		fmt.Sprintf("%d studentjon@gmail.com submitted", upcoming.AssignmentId),
+	}, statuses(""))
+}
+
+func TestAssignmentErrors(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", "studentbob@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com"]}`, "/api/register", 204, "", t)
+	assignment := createTestAssignment("teacherken@gmail.com", "quiz", time.Now().Add(time.Hour), t)
+	submissionPath := fmt.Sprintf("/api/assignments/%d/submissions/", assignment.AssignmentId)
+
+	testProblem("POST", "/api/teachers/teacherken@gmail.com/assignments", `{"due_at": "2022-09-01T08:00:00Z", "max_score": 10}`, "", 400,
+		problems.MISSING_FIELD, "The required field title is not supplied", t)
+	testProblem("POST", "/api/teachers/teacherken@gmail.com/assignments", `{"title": "quiz", "due_at": "2022-09-01T08:00:00Z", "max_score": -1}`, "", 400,
+		problems.INVALID_FIELD, "The field max_score must be a positive number", t)
+	testProblem("POST", "/api/teachers/teacherbob@gmail.com/assignments", `{"title": "quiz", "due_at": "2022-09-01T08:00:00Z", "max_score": 10}`, "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherbob@gmail.com does not exist in the database", t)
+	testProblem("GET", "/api/assignments/999", "", "", 400, problems.ASSIGNMENT_NOT_FOUND, "Assignment with id 999 does not exist in the database", t)
+	testProblem("GET", "/api/teachers/teacherken@gmail.com/submissions?status=graded", "", "", 400,
+		problems.INVALID_STATUS, "The status graded is not one of [pending missing submitted late]", t)
+
+	testProblem("PUT", submissionPath+"studentjon@gmail.com", `{}`, "", 400, problems.MISSING_FIELD, "A text or a file must be provided", t)
+	testProblem("PUT", submissionPath+"studentkim@gmail.com", `{"text": "answers"}`, "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+	problem := testProblem("PUT", submissionPath+"studentbob@gmail.com", `{"text": "answers"}`, "", 400,
+		problems.ASSIGNMENT_NOT_FOUND, fmt.Sprintf("Assignment with id %d is not assigned to student studentbob@gmail.com", assignment.AssignmentId), t)
+	assertEquals(t, []string{"studentbob@gmail.com"}, problem.Errors[0].Emails)
+	testProblem("PUT", "/api/assignments/999/submissions/studentjon@gmail.com", `{"text": "answers"}`, "", 400,
+		problems.ASSIGNMENT_NOT_FOUND, "Assignment with id 999 does not exist in the database", t)
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"fmt"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"testing"
+)
+
+func TestAttendance(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", "studenthon@gmail.com", "studentbob@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com", "studentbob@gmail.com"]}`, "/api/register", 204, "", t)
+
+	testRequestWithToken("PUT", "/api/teachers/teacherken@gmail.com/attendance/2022-09-01",
+		`{"students": {"studentjon@gmail.com": "present", "studenthon@gmail.com": "late"}}`, "", 200,
+		`{"teacher":"teacherken@gmail.com","date":"2022-09-01","students":[{"student":"studenthon@gmail.com","status":"late"},`+
+			`{"student":"studentjon@gmail.com","status":"present"}],"unrecorded":["studentbob@gmail.com"]}`, t)
+	rollCall := &types.RollCallResponse{}
+	testRequestJson("PUT", "/api/v1/teachers/teacherken@gmail.com/attendance/2022-09-02",
+		`{"students": {"studentjon@gmail.com": "absent", "studenthon@gmail.com": "present", "studentbob@gmail.com": "excused"}}`, 200, rollCall, t)
+	assertEquals(t, 3, len(rollCall.Students))
+	assertEquals(t, 0, len(rollCall.UnrecordedStudentEmails))
+	testRequestJson("PUT", "/api/v2/teachers/teacherken@gmail.com/attendance/2022-09-03",
+		`{"students": {"studentjon@gmail.com": "absent", "studenthon@gmail.com": "present", "studentbob@gmail.com": "absent"}}`, 200, rollCall, t)
+
+	// recording a student again on the same date replaces their attendance, the others keep theirs
+	testRequestJson("PUT", "/api/teachers/teacherken@gmail.com/attendance/2022-09-03", `{"students": {"studentjon@gmail.com": "excused"}}`, 200, rollCall, t)
+	testGet("/api/teachers/teacherken@gmail.com/attendance/2022-09-03", 200,
+		`{"teacher":"teacherken@gmail.com","date":"2022-09-03","students":[{"student":"studentbob@gmail.com","status":"absent"},`+
+			`{"student":"studenthon@gmail.com","status":"present"},{"student":"studentjon@gmail.com","status":"excused"}],"unrecorded":[]}`, t)
+
+	testGet("/api/v2/teachers/teacherken@gmail.com/students/studentjon@gmail.com/attendance", 200,
+		`{"teacher":"teacherken@gmail.com","student":"studentjon@gmail.com","present":1,"absent":1,"late":0,"excused":1,"rate":50,"records":[`+
+			`{"date":"2022-09-01","status":"present"},{"date":"2022-09-02","status":"absent"},{"date":"2022-09-03","status":"excused"}]}`, t)
+	attendance := &types.StudentAttendanceResponse{}
+	testGetJson("/api/teachers/teacherken@gmail.com/students/studenthon@gmail.com/attendance?from=2022-09-02&to=2022-09-02", 200, attendance, t)
+	assertEquals(t, 1, len(attendance.Records))
+	assertEquals(t, 100.0, *attendance.Rate)
+
+	rates := func(query string) []string {
+		response := &types.RetrieveAttendanceRatesResponse{}
+		testGetJson("/api/teachers/teacherken@gmail.com/attendance"+query, 200, response, t)
+		result := make([]string, 0)
+		for _, student := range response.Students {
+			rate := "null"
+			if student.Rate != nil {
+				rate = fmt.Sprint(*student.Rate)
+			}
+			result = append(result, student.StudentEmail+" "+rate)
+		}
+		return result
+	}
+	assertEquals(t, []string{"studentbob@gmail.com 0", "studenthon@gmail.com 100", "studentjon@gmail.com 50"}, rates(""))
+	assertEquals(t, []string{"studentbob@gmail.com 0", "studentjon@gmail.com 50"}, rates("?below=75"))
+	assertEquals(t, []string{"studentbob@gmail.com 0", "studentjon@gmail.com 0"}, rates("?from=2022-09-02&below=75"))
+	assertEquals(t, []string{"studentbob@gmail.com null", "studenthon@gmail.com 100", "studentjon@gmail.com 100"}, rates("?to=2022-09-01"))
+	assertEquals(t, []string{}, rates("?to=2022-09-01&below=100"))
+
+	// a student who is unregistered keeps their attendance, but is no longer expected on the next dates
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentbob@gmail.com"]}`, "/api/unregister", 200,
+		`{"unregistered":["studentbob@gmail.com"],"not_registered":[]}`, t)
+	assertEquals(t, []string{"studentbob@gmail.com 0", "studenthon@gmail.com 100", "studentjon@gmail.com 50"}, rates(""))
+	testGetJson("/api/teachers/teacherken@gmail.com/attendance/2022-09-04", 200, rollCall, t)
+	assertEquals(t, 0, len(rollCall.Students))
+	assertEquals(t, []string{"studenthon@gmail.com", "studentjon@gmail.com"}, rollCall.UnrecordedStudentEmails)
+}
+
+func TestAttendanceErrors(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", "studentbob@gmail.com", "studenthon@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com"]}`, "/api/register", 204, "", t)
+	rollCallPath := "/api/teachers/teacherken@gmail.com/attendance/"
+
+	testProblem("PUT", rollCallPath+"2022-09-01", `{}`, "", 400, problems.MISSING_FIELD, "The required field students is not supplied", t)
+	testProblem("PUT", rollCallPath+"2022-13-01", `{"students": {"studentjon@gmail.com": "present"}}`, "", 400,
+		problems.INVALID_FIELD, "The field date must be a date formatted as YYYY-MM-DD", t)
+	problem := testProblem("PUT", rollCallPath+"2022-09-01", `{"students": {"studentjon@gmail.com": "sick"}}`, "", 400,
+		problems.INVALID_STATUS, "The status sick is not one of [present absent late excused]", t)
+	assertEquals(t, []string{"studentjon@gmail.com"}, problem.Errors[0].Emails)
+	testProblem("PUT", rollCallPath+"2022-09-01", `{"students": {"studentkim@gmail.com": "present"}}`, "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+	testProblem("PUT", "/api/teachers/teacherbob@gmail.com/attendance/2022-09-01", `{"students": {"studentjon@gmail.com": "present"}}`, "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherbob@gmail.com does not exist in the database", t)
+	problem = testProblem("PUT", rollCallPath+"2022-09-01", `{"students": {"studentjon@gmail.com": "present", "studentbob@gmail.com": "present", "studenthon@gmail.com": "late"}}`, "", 400,
+		problems.REGISTRATION_NOT_FOUND, "Students studentbob@gmail.com, studenthon@gmail.com are not registered to teacher teacherken@gmail.com", t)
+	assertEquals(t, []string{"studentbob@gmail.com", "studenthon@gmail.com"}, problem.Errors[0].Emails)
+
+	// a roll call that fails records nothing
+	testGet("/api/teachers/teacherken@gmail.com/attendance/2022-09-01", 200,
+		`{"teacher":"teacherken@gmail.com","date":"2022-09-01","students":[],"unrecorded":["studentjon@gmail.com"]}`, t)
+
+	testProblem("GET", "/api/teachers/teacherken@gmail.com/attendance/yesterday", "", "", 400,
+		problems.INVALID_FIELD, "The field date must be a date formatted as YYYY-MM-DD", t)
+	testProblem("GET", "/api/teachers/teacherken@gmail.com/attendance?below=150", "", "", 400,
+		problems.INVALID_FIELD, "The field below must be a percentage between 0 and 100", t)
+	testProblem("GET", "/api/teachers/teacherken@gmail.com/students/studentjon@gmail.com/attendance?from=09/01/2022", "", "", 400,
+		problems.INVALID_FIELD, "The field from must be a date formatted as YYYY-MM-DD", t)
+	testProblem("GET", "/api/teachers/teacherken@gmail.com/students/studentkim@gmail.com/attendance", "", "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+}
@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"learning-management-system/auth"
+	"learning-management-system/helpers"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testHmacSecret = []byte("a test secret that is long enough")
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, subject string, role string, expiresAt time.Time) string {
+	claims := &auth.Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:  subject,
+			Issuer:   "lms-test",
+			Audience: jwt.ClaimStrings{"lms"},
+		},
+	}
+	// a zero expiresAt leaves the exp claim out
+	if !expiresAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	}
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("could not sign token: %s", err.Error())
+	}
+	return token
+}
+
+func TestHmacAuthenticatorValidatesTokens(t *testing.T) {
+	authenticator := auth.NewHmacAuthenticator(testHmacSecret, "lms-test", "lms")
+	expiresAt := time.Now().Add(time.Hour)
+
+	principal, err := authenticator.Authenticate(signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", auth.ROLE_TEACHER, expiresAt))
+	if err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+	assertEquals(t, &auth.Principal{Email: "test@gmail.com", Role: auth.ROLE_TEACHER}, principal)
+
+	invalidTokens := map[string]string{
+		"expired":      signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", auth.ROLE_TEACHER, time.Now().Add(-time.Minute)),
+		"wrong secret": signTestToken(t, jwt.SigningMethodHS256, []byte("another secret that is long enough"), "test@gmail.com", auth.ROLE_TEACHER, expiresAt),
+		"wrong method": signTestToken(t, jwt.SigningMethodHS512, testHmacSecret, "test@gmail.com", auth.ROLE_TEACHER, expiresAt),
+		"unknown role": signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", "student", expiresAt),
+		"no subject":   signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "", auth.ROLE_ADMIN, expiresAt),
+		"no expiry":    signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", auth.ROLE_TEACHER, time.Time{}),
+		"garbage":      "not.a.token",
+	}
+	for name, token := range invalidTokens {
+		if _, err := authenticator.Authenticate(token); err == nil {
+			t.Errorf("%s token should be rejected", name)
+		}
+	}
+
+	if _, err := auth.NewHmacAuthenticator(testHmacSecret, "another-issuer", "").Authenticate(signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", auth.ROLE_ADMIN, expiresAt)); err == nil {
+		t.Errorf("token from another issuer should be rejected")
+	}
+	if _, err := auth.NewHmacAuthenticator(testHmacSecret, "", "another-audience").Authenticate(signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", auth.ROLE_ADMIN, expiresAt)); err == nil {
+		t.Errorf("token for another audience should be rejected")
+	}
+}
+
+func TestRsaAuthenticatorValidatesTokens(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+	publicKeyDer, _ := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	publicKeyFile := filepath.Join(t.TempDir(), "jwt.pub")
+	os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDer}), 0600)
+
+	publicKey, err := auth.LoadRsaPublicKey(publicKeyFile)
+	if err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+	authenticator := auth.NewRsaAuthenticator(publicKey, "", "")
+	expiresAt := time.Now().Add(time.Hour)
+
+	principal, err := authenticator.Authenticate(signTestToken(t, jwt.SigningMethodRS256, privateKey, "admin@gmail.com", auth.ROLE_ADMIN, expiresAt))
+	if err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+	assertEquals(t, true, principal.IsAdmin())
+
+	// a token signed with the public key as hmac secret must not pass as RS256
+	if _, err := authenticator.Authenticate(signTestToken(t, jwt.SigningMethodHS256, publicKeyDer, "admin@gmail.com", auth.ROLE_ADMIN, expiresAt)); err == nil {
+		t.Errorf("HS256 token should be rejected")
+	}
+}
+
+func TestAuthEnforcesRoles(t *testing.T) {
+	SetUpTestServerWithAuth(auth.NewHmacAuthenticator(testHmacSecret, "", ""))
+	expiresAt := time.Now().Add(time.Hour)
+	admin := signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "admin@gmail.com", auth.ROLE_ADMIN, expiresAt)
+	teacher := signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test@gmail.com", auth.ROLE_TEACHER, expiresAt)
+	otherTeacher := signTestToken(t, jwt.SigningMethodHS256, testHmacSecret, "test2@gmail.com", auth.ROLE_TEACHER, expiresAt)
+
+	testRequestWithToken("GET", "/api/commonstudents?teacher=test%40gmail.com", "", "", 401, `{"message":"The Authorization header must hold a Bearer token"}`, t)
+	testRequestWithToken("GET", "/api/commonstudents?teacher=test%40gmail.com", "", signTestToken(t, jwt.SigningMethodHS256, []byte("another secret that is long enough"), "test@gmail.com", auth.ROLE_TEACHER, expiresAt), 401,
+		`{"message":"The token is invalid: signature is invalid"}`, t)
+
+	testRequestWithToken("POST", "/api/populateteachers", `{"teachers": ["test@gmail.com", "test2@gmail.com"]}`, teacher, 403, `{"message":"This endpoint requires the admin role"}`, t)
+	testRequestWithToken("POST", "/api/populateteachers", `{"teachers": ["test@gmail.com", "test2@gmail.com"]}`, admin, 204, "", t)
+	testRequestWithToken("POST", "/api/populatestudents", `{"students": ["test1@gmail.com", "test2@gmail.com"]}`, admin, 204, "", t)
+
+	testRequestWithToken("POST", "/api/register", `{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, teacher, 204, "", t)
+	testRequestWithToken("POST", "/api/register", `{"teacher": "test2@gmail.com", "students":["test1@gmail.com"]}`, teacher, 403,
+		`{"message":"Teacher test@gmail.com cannot act as teacher test2@gmail.com"}`, t)
+	testRequestWithToken("POST", "/api/register", `{"teacher": "test2@gmail.com", "students":["test1@gmail.com"]}`, admin, 204, "", t)
+	testRequestWithToken("POST", "/api/unregister", `{"teacher": "test2@gmail.com", "students":["test1@gmail.com"]}`, teacher, 403,
+		`{"message":"Teacher test@gmail.com cannot act as teacher test2@gmail.com"}`, t)
+
+	testRequestWithToken("POST", "/api/suspend", `{"student":"test2@gmail.com"}`, teacher, 403, `{"message":"This endpoint requires the admin role"}`, t)
+	testRequestWithToken("POST", "/api/suspend", `{"student":"test2@gmail.com"}`, admin, 204, "", t)
+	testProblem("POST", "/api/suspend", `{"student":"test1@gmail.com","suspended_by":"test@gmail.com"}`, admin, 403,
+		problems.FORBIDDEN, "admin@gmail.com cannot suspend students on behalf of test@gmail.com", t)
+	suspensions := &types.RetrieveStudentSuspensionsResponse{}
+	testRequestJsonWithToken("GET", "/api/students/test2%40gmail.com/suspensions", "", admin, 200, suspensions, t)
+	assertEquals(t, "admin@gmail.com", suspensions.Suspensions[0].SuspendedBy)
+
+	testRequestWithToken("POST", "/api/retrievefornotifications", `{"teacher":"test@gmail.com", "notification":"hello"}`, teacher, 200, `{"recipients":["test1@gmail.com"]}`, t)
+	testRequestWithToken("POST", "/api/retrievefornotifications", `{"teacher":"test2@gmail.com", "notification":"hello"}`, teacher, 403,
+		`{"message":"Teacher test@gmail.com cannot act as teacher test2@gmail.com"}`, t)
+
+	// cursors are not signed, so paging the recipients of a notification authorizes its teacher again
+	cursor := helpers.EncodeCursor(&helpers.Cursor{NotificationId: 1, After: "a@gmail.com"})
+	testRequestWithToken("GET", "/api/retrievefornotifications?cursor="+cursor, "", teacher, 200, `{"recipients":["test1@gmail.com"]}`, t)
+	testProblem("GET", "/api/retrievefornotifications?cursor="+cursor, "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testRequestWithToken("GET", "/api/v2/notifications/1/recipients", "", teacher, 200, `{"id":1,"recipients":["test1@gmail.com"]}`, t)
+	testProblem("GET", "/api/v2/notifications/1/recipients", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/v2/notifications/1/deliveries", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/notifications/1/deliveries", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	deliveries := &types.RetrieveNotificationDeliveriesResponse{}
+	testRequestJsonWithToken("GET", "/api/notifications/1/deliveries", "", teacher, 200, deliveries, t)
+	assertEquals(t, 1, len(deliveries.Deliveries))
+	testRequestWithToken("GET", "/api/commonstudents?teacher=test2%40gmail.com", "", teacher, 200, `{"students":["test1@gmail.com"]}`, t)
+
+	// only the teachers of a student and admins may read the inbox of the student
+	testRequestWithToken("GET", "/api/students/test2%40gmail.com/notifications", "", teacher, 200, `{"student":"test2@gmail.com","notifications":[],"total":0,"unread":0}`, t)
+	testProblem("GET", "/api/students/test2%40gmail.com/notifications", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act on student test2@gmail.com, who is not registered to them", t)
+	testProblem("POST", "/api/students/test2%40gmail.com/notifications/1/read", `{}`, otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act on student test2@gmail.com, who is not registered to them", t)
+	testRequestWithToken("POST", "/api/students/test1%40gmail.com/notifications/1/read", `{}`, otherTeacher, 204, "", t)
+
+	// only the teacher who set an assignment and admins may read it, submit to it and list its submissions
+	assignment := &types.AssignmentResponse{}
+	testRequestJsonWithToken("POST", "/api/teachers/test@gmail.com/assignments", `{"title":"Essay","due_at":"2022-09-01T08:00:00Z","max_score":10}`, teacher, 201, assignment, t)
+	assignmentPath := fmt.Sprintf("/api/assignments/%d", assignment.AssignmentId)
+	testProblem("GET", "/api/teachers/test@gmail.com/assignments", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/teachers/test@gmail.com/submissions", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	// teachers are authorized before their existence is checked, so other teachers cannot tell which emails exist
+	testProblem("GET", "/api/teachers/nobody@gmail.com/assignments", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher nobody@gmail.com", t)
+	testProblem("GET", "/api/teachers/nobody@gmail.com/submissions", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher nobody@gmail.com", t)
+	testProblem("GET", assignmentPath, "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", assignmentPath+"/submissions", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("PUT", assignmentPath+"/submissions/test1@gmail.com", `{"text":"answers"}`, otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	submission := &types.SubmissionResponse{}
+	testRequestJsonWithToken("PUT", assignmentPath+"/submissions/test1@gmail.com", `{"text":"answers"}`, teacher, 200, submission, t)
+	assertEquals(t, "late", submission.Status)
+	submissions := &types.RetrieveSubmissionsResponse{}
+	testRequestJsonWithToken("GET", assignmentPath+"/submissions?status=late", "", admin, 200, submissions, t)
+	assertEquals(t, 1, len(submissions.Submissions))
+
+	// only the teacher and admins may read the gradebooks of a teacher, and only the teachers of a student and admins
+	// may read the report card of the student
+	testProblem("GET", "/api/teachers/test@gmail.com/categories", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/teachers/nobody@gmail.com/categories", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher nobody@gmail.com", t)
+	testProblem("GET", "/api/students/nobody@gmail.com/reportcard", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act on student nobody@gmail.com, who is not registered to them", t)
+	testProblem("GET", "/api/teachers/test@gmail.com/students/test1@gmail.com/gradebook", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testRequestWithToken("GET", "/api/teachers/test@gmail.com/students/test1@gmail.com/gradebook", "", teacher, 200,
+		`{"teacher":"test@gmail.com","student":"test1@gmail.com","categories":[],"average":null}`, t)
+	testProblem("GET", "/api/students/test2@gmail.com/reportcard", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act on student test2@gmail.com, who is not registered to them", t)
+	reportCard := &types.ReportCardResponse{}
+	testRequestJsonWithToken("GET", "/api/students/test1@gmail.com/reportcard", "", otherTeacher, 200, reportCard, t)
+	assertEquals(t, 2, len(reportCard.Teachers))
+
+	// only the teacher and admins may read the attendance of a teacher
+	testProblem("GET", "/api/teachers/test@gmail.com/attendance", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/teachers/test@gmail.com/attendance/2022-09-01", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/teachers/test@gmail.com/students/test1@gmail.com/attendance", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher test@gmail.com", t)
+	testProblem("GET", "/api/teachers/nobody@gmail.com/attendance", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher nobody@gmail.com", t)
+	testProblem("GET", "/api/teachers/nobody@gmail.com/attendance/2022-09-01", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act as teacher nobody@gmail.com", t)
+	rollCall := &types.RollCallResponse{}
+	testRequestJsonWithToken("GET", "/api/teachers/test@gmail.com/attendance/2022-09-01", "", teacher, 200, rollCall, t)
+	assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, rollCall.UnrecordedStudentEmails)
+
+	testRequestWithToken("PATCH", "/api/teachers/test@gmail.com", `{"name":"Test"}`, teacher, 200, `{"email":"test@gmail.com","name":"Test"}`, t)
+	testProblem("PATCH", "/api/teachers/test2@gmail.com", `{"name":"Test"}`, teacher, 403,
+		problems.FORBIDDEN, "Teacher test@gmail.com cannot act as teacher test2@gmail.com", t)
+	testProblem("PATCH", "/api/students/test1@gmail.com", `{"name":"Test"}`, teacher, 403, problems.FORBIDDEN, "This endpoint requires the admin role", t)
+	testProblem("DELETE", "/api/teachers/test@gmail.com", "", teacher, 403, problems.FORBIDDEN, "This endpoint requires the admin role", t)
+	testRequestWithToken("GET", "/api/students/test1@gmail.com", "", teacher, 200, `{"email":"test1@gmail.com","suspended":false}`, t)
+	testProblem("GET", "/api/students/test2@gmail.com", "", otherTeacher, 403,
+		problems.FORBIDDEN, "Teacher test2@gmail.com cannot act on student test2@gmail.com, who is not registered to them", t)
+	testRequestWithToken("GET", "/api/students/test2@gmail.com", "", admin, 200, `{"email":"test2@gmail.com","suspended":true}`, t)
+
+	testRequestWithToken("DELETE", "/api/clear", "", teacher, 403, `{"message":"This endpoint requires the admin role"}`, t)
+	testRequestWithToken("DELETE", "/api/clear", "", admin, 204, "", t)
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"learning-management-system/problems"
+	"testing"
+)
+
+func setUpCourses(prefix string, t *testing.T) {
+	testDeletePath(prefix+"/clear", 204, "", t)
+	testRequestWithToken("POST", prefix+"/populateteachers", `{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`, "", 204, "", t)
+	testRequestWithToken("POST", prefix+"/populatestudents", `{"students": ["studentjon@gmail.com", "studenthon@gmail.com", "studentbob@gmail.com"]}`, "", 204, "", t)
+	testRequestWithToken("PUT", prefix+"/courses/MA101", `{"name": "Algebra"}`, "", 200, `{"code":"MA101","name":"Algebra"}`, t)
+	testRequestWithToken("PUT", prefix+"/courses/MA101/sections/A", `{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`, "", 200,
+		`{"course":"MA101","name":"A","teachers":["teacherjoe@gmail.com","teacherken@gmail.com"],"students":[]}`, t)
+	testRequestWithToken("PUT", prefix+"/courses/MA101/sections/B", `{"teachers": ["teacherken@gmail.com"]}`, "", 200,
+		`{"course":"MA101","name":"B","teachers":["teacherken@gmail.com"],"students":[]}`, t)
+}
+
+func TestCoursesAndSections(t *testing.T) {
+	SetUpTestServer()
+	setUpCourses("/api/v1", t)
+
+	// saving a course again renames it and keeps its sections
+	testRequestWithToken("PUT", "/api/v1/courses/MA101", `{"name": "Linear Algebra"}`, "", 200, `{"code":"MA101","name":"Linear Algebra"}`, t)
+	testRequestWithToken("PUT", "/api/v1/courses/CS101", `{"name": "Programming"}`, "", 200, `{"code":"CS101","name":"Programming"}`, t)
+	testGet("/api/v1/courses", 200, `{"courses":[{"code":"CS101","name":"Programming"},{"code":"MA101","name":"Linear Algebra"}]}`, t)
+
+	testRequestWithToken("POST", "/api/v1/courses/MA101/sections/B/students", `{"students": ["studentjon@gmail.com", "studentbob@gmail.com"]}`, "", 204, "", t)
+	testGet("/api/v1/courses/MA101/sections/B", 200,
+		`{"course":"MA101","name":"B","teachers":["teacherken@gmail.com"],"students":["studentbob@gmail.com","studentjon@gmail.com"]}`, t)
+	testGet("/api/v2/courses/MA101", 200, `{"code":"MA101","name":"Linear Algebra","sections":[`+
+		`{"course":"MA101","name":"A","teachers":["teacherjoe@gmail.com","teacherken@gmail.com"],"students":[]},`+
+		`{"course":"MA101","name":"B","teachers":["teacherken@gmail.com"],"students":["studentbob@gmail.com","studentjon@gmail.com"]}]}`, t)
+
+	// enrolling into a section does not register the students to its teachers
+	testGet("/api/v1/commonstudents?teacher=teacherken%40gmail.com", 200, `{"students":[]}`, t)
+
+	testProblem("GET", "/api/v1/courses/PH101", "", "", 400, problems.COURSE_NOT_FOUND, "Course with code PH101 does not exist in the database", t)
+	testProblem("GET", "/api/v1/courses/MA101/sections/C", "", "", 400, problems.SECTION_NOT_FOUND, "Section C of course MA101 does not exist in the database", t)
+	testProblem("PUT", "/api/v1/courses/PH101/sections/A", `{"teachers": ["teacherken@gmail.com"]}`, "", 400,
+		problems.COURSE_NOT_FOUND, "Course with code PH101 does not exist in the database", t)
+	testProblem("PUT", "/api/v1/courses/MA101/sections/A", `{"teachers": ["teacherbob@gmail.com"]}`, "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherbob@gmail.com does not exist in the database", t)
+	testProblem("POST", "/api/v1/courses/MA101/sections/A/students", `{"students": ["studentkim@gmail.com"]}`, "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+	testProblem("PUT", "/api/v1/courses/MA101", `{}`, "", 400, problems.MISSING_FIELD, "The required field name is not supplied", t)
+}
+
+func TestRegisterStudentsToTeacherInCourse(t *testing.T) {
+	SetUpTestServer()
+	setUpCourses("/api", t)
+
+	// teacherjoe teaches a single section of the course, so it can be left out
+	testPost(`{"teacher": "teacherjoe@gmail.com", "students": ["studentjon@gmail.com"], "course": "MA101"}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studenthon@gmail.com"], "course": "MA101", "section": "B"}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentbob@gmail.com"]}`, "/api/register", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/teachers/teacherken@gmail.com/students", `{"students": ["studentjon@gmail.com"], "course": "MA101", "section": "A"}`, "", 204, "", t)
+
+	testGet("/api/courses/MA101/sections/A", 200,
+		`{"course":"MA101","name":"A","teachers":["teacherjoe@gmail.com","teacherken@gmail.com"],"students":["studentjon@gmail.com"]}`, t)
+	testGet("/api/courses/MA101/sections/B", 200,
+		`{"course":"MA101","name":"B","teachers":["teacherken@gmail.com"],"students":["studenthon@gmail.com"]}`, t)
+
+	// without a course every registration counts, with one only the teachers of the sections of the students do
+	testGet("/api/commonstudents?teacher=teacherken%40gmail.com", 200,
+		`{"students":["studentbob@gmail.com","studenthon@gmail.com","studentjon@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=teacherken%40gmail.com&course=MA101", 200,
+		`{"students":["studenthon@gmail.com","studentjon@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=teacherken%40gmail.com&teacher=teacherjoe%40gmail.com&course=MA101", 200,
+		`{"students":["studentjon@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=teacherken%40gmail.com&teacher=teacherjoe%40gmail.com&course=MA101&mode=only", 200,
+		`{"students":["studenthon@gmail.com"]}`, t)
+	testGet("/api/v2/students?teacher=teacherken%40gmail.com&course=MA101&mode=any&limit=1", 200,
+		`{"students":["studenthon@gmail.com"],"next_cursor":"eyJhIjoic3R1ZGVudGhvbkBnbWFpbC5jb20ifQ"}`, t)
+
+	testProblem("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&course=PH101", "", "", 400,
+		problems.COURSE_NOT_FOUND, "Course with code PH101 does not exist in the database", t)
+	testProblem("POST", "/api/register", `{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com"], "course": "MA101"}`, "", 400,
+		problems.MISSING_FIELD, "The required field section is not supplied", t)
+	testProblem("POST", "/api/register", `{"teacher": "teacherjoe@gmail.com", "students": ["studentjon@gmail.com"], "course": "MA101", "section": "B"}`, "", 400,
+		problems.SECTION_NOT_FOUND, "Teacher teacherjoe@gmail.com is not assigned to section B of course MA101", t)
+	testProblem("POST", "/api/register", `{"teacher": "teacherjoe@gmail.com", "students": ["studentjon@gmail.com"], "section": "A"}`, "", 400,
+		problems.MISSING_FIELD, "The required field course is not supplied", t)
+
+	testRequestWithToken("PUT", "/api/courses/CS101", `{"name": "Programming"}`, "", 200, `{"code":"CS101","name":"Programming"}`, t)
+	testProblem("POST", "/api/register", `{"teacher": "teacherjoe@gmail.com", "students": ["studentjon@gmail.com"], "course": "CS101"}`, "", 400,
+		problems.SECTION_NOT_FOUND, "Teacher teacherjoe@gmail.com is not assigned to a section of course CS101", t)
+
+	// a failed registration with a course registers nobody
+	testGet("/api/commonstudents?teacher=teacherjoe%40gmail.com", 200, `{"students":["studentjon@gmail.com"]}`, t)
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"learning-management-system/types"
+	"testing"
+)
+
+func TestExportRoster(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com", "test7@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test2@gmail.com","test1@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "test7@gmail.com", "students":["test1@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"student":"test2@gmail.com"}`, "/api/suspend", 204, "", t)
+
+	csvExport := "record,teacher,student,suspended\n" +
+		"teacher,test7@gmail.com,,\n" +
+		"teacher,test@gmail.com,,\n" +
+		"student,,test1@gmail.com,false\n" +
+		"student,,test2@gmail.com,true\n" +
+		"student,,test3@gmail.com,false\n" +
+		"registration,test7@gmail.com,test1@gmail.com,\n" +
+		"registration,test@gmail.com,test1@gmail.com,\n" +
+		"registration,test@gmail.com,test2@gmail.com,\n"
+	testGet("/api/export?format=csv", 200, csvExport, t)
+
+	testGet("/api/export", 200, `{"teachers":[{"email":"test7@gmail.com"},{"email":"test@gmail.com"}],`+
+		`"students":[{"email":"test1@gmail.com","suspended":false},{"email":"test2@gmail.com","suspended":true},{"email":"test3@gmail.com","suspended":false}],`+
+		`"registrations":[{"teacher":"test7@gmail.com","student":"test1@gmail.com"},{"teacher":"test@gmail.com","student":"test1@gmail.com"},{"teacher":"test@gmail.com","student":"test2@gmail.com"}]}`, t)
+
+	testGet("/api/export?format=ndjson", 200, `{"type":"teacher","email":"test7@gmail.com"}`+"\n"+
+		`{"type":"teacher","email":"test@gmail.com"}`+"\n"+
+		`{"type":"student","email":"test1@gmail.com","suspended":false}`+"\n"+
+		`{"type":"student","email":"test2@gmail.com","suspended":true}`+"\n"+
+		`{"type":"student","email":"test3@gmail.com","suspended":false}`+"\n"+
+		`{"type":"registration","teacher":"test7@gmail.com","student":"test1@gmail.com"}`+"\n"+
+		`{"type":"registration","teacher":"test@gmail.com","student":"test1@gmail.com"}`+"\n"+
+		`{"type":"registration","teacher":"test@gmail.com","student":"test2@gmail.com"}`+"\n", t)
+
+	testGet("/api/export?format=xml", 400, `{"message":"The format xml is not one of [csv json ndjson]"}`, t)
+
+	// the csv export can be imported back, apart from the suspensions
+	testDelete(t)
+	testGet("/api/export", 200, `{"teachers":[],"students":[],"registrations":[]}`, t)
+	report := &types.ImportRosterResponse{}
+	testPostCsv(csvExport, "/api/import", 200, report, t)
+	assertEquals(t, 3, report.Registrations)
+	testGet("/api/commonstudents?teacher=test%40gmail.com", 200, `{"students":["test1@gmail.com","test2@gmail.com"]}`, t)
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"fmt"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"testing"
+	"time"
+)
+
+func TestGradebookAndReportCard(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "teacherjoe@gmail.com", "students": ["studentjon@gmail.com"]}`, "/api/register", 204, "", t)
+
+	category := &types.GradeCategoryResponse{}
+	testRequestJson("PUT", "/api/teachers/teacherken@gmail.com/categories/homework", `{"weight": 50}`, 200, category, t)
+	assertEquals(t, 50.0, category.Weight)
+	testRequestJson("PUT", "/api/v1/teachers/teacherken@gmail.com/categories/homework", `{"weight": 40}`, 200, category, t)
+	assertEquals(t, 40.0, category.Weight)
+	testRequestJson("PUT", "/api/v2/teachers/teacherken@gmail.com/categories/exams", `{"weight": 60}`, 200, category, t)
+	testRequestJson("PUT", "/api/teachers/teacherken@gmail.com/categories/projects", `{"weight": 20}`, 200, category, t)
+	testRequestJson("PUT", "/api/teachers/teacherjoe@gmail.com/categories/labs", `{"weight": 1}`, 200, category, t)
+
+	categories := &types.RetrieveGradeCategoriesResponse{}
+	testGetJson("/api/teachers/teacherken@gmail.com/categories", 200, categories, t)
+	assertEquals(t, 3, len(categories.Categories))
+	assertEquals(t, "exams", categories.Categories[0].Name)
+	assertEquals(t, 40.0, categories.Categories[1].Weight)
+
+	// a grade for an assignment takes its title and max score from the assignment
+	quiz := createTestAssignment("teacherken@gmail.com", "quiz", time.Now().Add(-time.Hour), t)
+	grade := &types.GradeResponse{}
+	testRequestJson("POST", "/api/teachers/teacherken@gmail.com/students/studentjon@gmail.com/grades",
+		fmt.Sprintf(`{"category": "homework", "assignment": %d, "score": 8}`, quiz.AssignmentId), 201, grade, t)
+	assertEquals(t, "quiz", grade.Title)
+	assertEquals(t, 10.0, grade.MaxScore)
+	assertEquals(t, quiz.AssignmentId, *grade.AssignmentId)
+	testRequestJson("POST", "/api/v2/teachers/teacherken@gmail.com/students/studentjon@gmail.com/grades",
+		`{"category": "homework", "title": "worksheet", "score": 16, "max_score": 20}`, 201, grade, t)
+	testRequestJson("POST", "/api/teachers/teacherken@gmail.com/students/studentjon@gmail.com/grades",
+		`{"category": "exams", "title": "midterm", "score": 45, "max_score": 50}`, 201, grade, t)
+	testRequestJson("POST", "/api/teachers/teacherjoe@gmail.com/students/studentjon@gmail.com/grades",
+		`{"category": "labs", "title": "lab 1", "score": 7, "max_score": 10}`, 201, grade, t)
+	assertEquals(t, (*uint)(nil), grade.AssignmentId)
+
+	// homework averages 24 points out of 30, and the projects without grades are left out of the average
+	gradebook := &types.GradebookResponse{}
+	testGetJson("/api/teachers/teacherken@gmail.com/students/studentjon@gmail.com/gradebook", 200, gradebook, t)
+	assertEquals(t, 3, len(gradebook.Categories))
+	assertEquals(t, 90.0, *gradebook.Categories[0].Average)
+	assertEquals(t, 1, len(gradebook.Categories[0].Grades))
+	assertEquals(t, 80.0, *gradebook.Categories[1].Average)
+	assertEquals(t, []string{"quiz", "worksheet"}, []string{gradebook.Categories[1].Grades[0].Title, gradebook.Categories[1].Grades[1].Title})
+	assertEquals(t, (*float64)(nil), gradebook.Categories[2].Average)
+	assertEquals(t, 86.0, *gradebook.Average)
+	assertEquals(t, "B", gradebook.LetterGrade)
+
+	testGet("/api/v2/teachers/teacherken@gmail.com/students/studenthon@gmail.com/gradebook", 200,
+		`{"teacher":"teacherken@gmail.com","student":"studenthon@gmail.com","categories":[`+
+			`{"name":"exams","weight":60,"average":null,"grades":[]},`+
+			`{"name":"homework","weight":40,"average":null,"grades":[]},`+
+			`{"name":"projects","weight":20,"average":null,"grades":[]}],"average":null}`, t)
+
+	testGet("/api/students/studentjon@gmail.com/reportcard", 200,
+		`{"student":"studentjon@gmail.com","teachers":[`+
+			`{"teacher":"teacherjoe@gmail.com","average":70,"letter_grade":"C"},`+
+			`{"teacher":"teacherken@gmail.com","average":86,"letter_grade":"B"}],"average":78,"letter_grade":"C"}`, t)
+	testGet("/api/v2/students/studenthon@gmail.com/reportcard", 200,
+		`{"student":"studenthon@gmail.com","teachers":[{"teacher":"teacherken@gmail.com","average":null}],"average":null}`, t)
+}
+
+func TestGradebookErrors(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", "studentbob@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com"]}`, "/api/register", 204, "", t)
+	testRequestJson("PUT", "/api/teachers/teacherken@gmail.com/categories/homework", `{"weight": 40}`, 200, &types.GradeCategoryResponse{}, t)
+	assignment := createTestAssignment("teacherjoe@gmail.com", "lab", time.Now().Add(time.Hour), t)
+	gradesPath := "/api/teachers/teacherken@gmail.com/students/"
+
+	testProblem("PUT", "/api/teachers/teacherken@gmail.com/categories/exams", `{"weight": 0}`, "", 400,
+		problems.MISSING_FIELD, "The required field weight is not supplied", t)
+	testProblem("PUT", "/api/teachers/teacherken@gmail.com/categories/exams", `{"weight": -5}`, "", 400,
+		problems.INVALID_FIELD, "The field weight must be a positive number", t)
+	testProblem("PUT", "/api/teachers/teacherbob@gmail.com/categories/exams", `{"weight": 5}`, "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherbob@gmail.com does not exist in the database", t)
+
+	testProblem("POST", gradesPath+"studentjon@gmail.com/grades", `{"category": "homework", "title": "quiz", "max_score": 10}`, "", 400,
+		problems.MISSING_FIELD, "The required field score is not supplied", t)
+	testProblem("POST", gradesPath+"studentjon@gmail.com/grades", `{"category": "homework", "title": "quiz", "score": -1, "max_score": 10}`, "", 400,
+		problems.INVALID_FIELD, "The field score must not be negative", t)
+	testProblem("POST", gradesPath+"studentjon@gmail.com/grades", `{"category": "homework", "score": 8, "max_score": 10}`, "", 400,
+		problems.MISSING_FIELD, "The required field title is not supplied", t)
+	testProblem("POST", gradesPath+"studentjon@gmail.com/grades", `{"category": "exams", "title": "quiz", "score": 8, "max_score": 10}`, "", 400,
+		problems.CATEGORY_NOT_FOUND, "Category exams of teacher teacherken@gmail.com does not exist in the database", t)
+	testProblem("POST", gradesPath+"studentjon@gmail.com/grades", fmt.Sprintf(`{"category": "homework", "assignment": %d, "score": 8}`, assignment.AssignmentId), "", 400,
+		problems.ASSIGNMENT_NOT_FOUND, fmt.Sprintf("Assignment with id %d is not set by teacher teacherken@gmail.com", assignment.AssignmentId), t)
+	problem := testProblem("POST", gradesPath+"studentbob@gmail.com/grades", `{"category": "homework", "title": "quiz", "score": 8, "max_score": 10}`, "", 400,
+		problems.REGISTRATION_NOT_FOUND, "Student studentbob@gmail.com is not registered to teacher teacherken@gmail.com", t)
+	assertEquals(t, []string{"studentbob@gmail.com"}, problem.Errors[0].Emails)
+
+	testProblem("GET", gradesPath+"studentbob@gmail.com/gradebook", "", "", 400,
+		problems.REGISTRATION_NOT_FOUND, "Student studentbob@gmail.com is not registered to teacher teacherken@gmail.com", t)
+	testProblem("GET", gradesPath+"studentkim@gmail.com/gradebook", "", "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+	testProblem("GET", "/api/students/studentkim@gmail.com/reportcard", "", "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"fmt"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"strings"
+	"testing"
+)
+
+func TestImportRoster(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+
+	roster := "Teacher,Student,Class\n" +
+		"test@gmail.com,test1@gmail.com,1A\n" +
+		"test@gmail.com, test2@gmail.com,1A\n" +
+		"test7@gmail.com,test1@gmail.com,2B\n" +
+		",test3@gmail.com,\n" +
+		"test@gmail.com,test1@gmail.com,1A\n"
+
+	report := &types.ImportRosterResponse{}
+	testPostCsv(roster, "/api/import?dry_run=true", 200, report, t)
+	assertEquals(t, &types.ImportRosterResponse{DryRun: true, Rows: 5, Teachers: 2, Students: 3, Registrations: 3, Errors: []*types.ImportRowErrorResponse{}}, report)
+	testGet("/api/commonstudents?teacher=test%40gmail.com", 200, `{"students":[]}`, t)
+
+	report = &types.ImportRosterResponse{}
+	testPostCsv(roster, "/api/import", 200, report, t)
+	assertEquals(t, true, report.Committed)
+	assertEquals(t, 3, report.Registrations)
+	testGet("/api/commonstudents?teacher=test%40gmail.com", 200, `{"students":["test1@gmail.com","test2@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=test7%40gmail.com", 200, `{"students":["test1@gmail.com"]}`, t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"hello @test3@gmail.com"}`, "/api/retrievefornotifications", 200, `{"recipients":["test1@gmail.com","test2@gmail.com","test3@gmail.com"]}`, t)
+
+	// importing the roster again only finds registrations that already exist
+	report = &types.ImportRosterResponse{}
+	testPostCsv(roster+"test7@gmail.com,test2@gmail.com,2B\n", "/api/import", 200, report, t)
+	assertEquals(t, 1, report.Registrations)
+	assertEquals(t, 3, report.ExistingRegistrations)
+}
+
+func TestImportRosterWithInvalidRows(t *testing.T) {
+	SetUpTestServer()
+
+	roster := "teacher,student\n" +
+		"test@gmail.com,test1@gmail.com\n" +
+		"test@gmail.com,test2gmail.com\n" +
+		",\n" +
+		"test@gmail.com,\"test3@gmail.com\n"
+
+	report := &types.ImportRosterResponse{}
+	testPostCsv(roster, "/api/import", 400, report, t)
+	assertEquals(t, false, report.Committed)
+	assertEquals(t, 4, report.Rows)
+	assertEquals(t, 3, report.InvalidRows)
+	assertEquals(t, &types.ImportRowErrorResponse{Line: 3, Code: problems.INVALID_EMAIL, Message: "The email address test2gmail.com has an invalid format"}, report.Errors[0])
+	assertEquals(t, &types.ImportRowErrorResponse{Line: 4, Code: problems.MISSING_FIELD, Message: "A teacher or a student must be provided"}, report.Errors[1])
+	assertEquals(t, 5, report.Errors[2].Line)
+	testGet("/api/commonstudents?teacher=test%40gmail.com", 400, `{"message":"Teacher with email test@gmail.com does not exist in the database"}`, t)
+
+	errorResponse := &types.ProblemResponse{}
+	testPostCsv("email\ntest@gmail.com\n", "/api/import", 400, errorResponse, t)
+	assertEquals(t, "The CSV file must start with a header naming the teacher and student columns", errorResponse.Message)
+	testPost(`{}`, "/api/import", 400, `{"message":"Content-Type header must be multipart/form-data"}`, t)
+}
+
+func TestImportRosterInBatches(t *testing.T) {
+	SetUpTestServer()
+
+	var roster strings.Builder
+	roster.WriteString("teacher,student\n")
+	for i := 0; i < 1200; i++ {
+		roster.WriteString(fmt.Sprintf("teacher%d@gmail.com,student%d@gmail.com\n", i%3, i))
+	}
+
+	report := &types.ImportRosterResponse{}
+	testPostCsv(roster.String(), "/api/import", 200, report, t)
+	assertEquals(t, 3, report.Teachers)
+	assertEquals(t, 1200, report.Students)
+	assertEquals(t, 1200, report.Registrations)
+
+	students := &types.RetrieveRegisteredStudentsResponse{}
+	testGetJson("/api/commonstudents?mode=any&teacher=teacher0%40gmail.com&teacher=teacher1%40gmail.com&limit=100", 200, students, t)
+	assertEquals(t, 100, len(students.StudentEmails))
+	assertEquals(t, true, students.NextCursor != "")
+}
@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"bufio"
+	"errors"
+	"learning-management-system/database"
+	"learning-management-system/models"
+	"learning-management-system/notifiers"
+	"learning-management-system/repositories"
+	"learning-management-system/schedulers"
+	"learning-management-system/transaction_managers"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSmtpServer accepts mail on a local port and records every message it receives
+type fakeSmtpServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	messages []*fakeSmtpMessage
+}
+
+type fakeSmtpMessage struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+func startFakeSmtpServer(t *testing.T) *fakeSmtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not start fake smtp server: %s", err.Error())
+	}
+	server := &fakeSmtpServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *fakeSmtpServer) port() string {
+	_, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	return port
+}
+
+func (server *fakeSmtpServer) received() []*fakeSmtpMessage {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]*fakeSmtpMessage{}, server.messages...)
+}
+
+func (server *fakeSmtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	message := &fakeSmtpMessage{}
+	text.PrintfLine("220 localhost fake smtp")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL":
+			message.from = strings.Trim(strings.TrimPrefix(line[len("MAIL "):], "FROM:"), "<>")
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			recipient := strings.Trim(strings.TrimPrefix(line[len("RCPT "):], "TO:"), "<>")
+			if strings.HasPrefix(recipient, "bounce") {
+				text.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			message.recipients = append(message.recipients, recipient)
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			message.data = string(data)
+			server.mutex.Lock()
+			server.messages = append(server.messages, message)
+			server.mutex.Unlock()
+			message = &fakeSmtpMessage{}
+			text.PrintfLine("250 OK")
+		case "RSET":
+			message = &fakeSmtpMessage{}
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+func TestSmtpNotifierSendsNotification(t *testing.T) {
+	server := startFakeSmtpServer(t)
+	notifier := notifiers.NewSmtpNotifier(&notifiers.SmtpCredentials{Host: "127.0.0.1", Port: server.port(), From: "lms@school.com"})
+	notification := &models.Notification{ID: 1, TeacherEmail: "test@gmail.com", Message: "hello\nworld", SentAt: time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)}
+
+	if err := notifier.Notify("test1@gmail.com", notification); err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+
+	messages := server.received()
+	assertEquals(t, 1, len(messages))
+	assertEquals(t, "lms@school.com", messages[0].from)
+	assertEquals(t, []string{"test1@gmail.com"}, messages[0].recipients)
+
+	headers, err := textproto.NewReader(bufio.NewReader(strings.NewReader(messages[0].data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+	assertEquals(t, "test1@gmail.com", headers.Get("To"))
+	assertEquals(t, "test@gmail.com", headers.Get("Reply-To"))
+	assertEquals(t, "Notification from test@gmail.com", headers.Get("Subject"))
+	assertEquals(t, true, strings.HasSuffix(messages[0].data, "\nhello\nworld\n"))
+}
+
+func TestSmtpNotifierReportsRejectedRecipient(t *testing.T) {
+	server := startFakeSmtpServer(t)
+	notifier := notifiers.NewSmtpNotifier(&notifiers.SmtpCredentials{Host: "127.0.0.1", Port: server.port(), From: "lms@school.com"})
+
+	err := notifier.Notify("bounce@gmail.com", &models.Notification{TeacherEmail: "test@gmail.com", Message: "hello"})
+	assertEquals(t, true, err != nil && strings.Contains(err.Error(), "mailbox unavailable"))
+	assertEquals(t, 0, len(server.received()))
+}
+
+// failingNotifier fails every delivery to the given recipients and records the others
+type failingNotifier struct {
+	mutex             sync.Mutex
+	failingRecipients map[string]bool
+	delivered         []string
+}
+
+func (notifier *failingNotifier) Notify(recipientEmail string, notification *models.Notification) error {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	if notifier.failingRecipients[recipientEmail] {
+		return errors.New("mailbox unavailable")
+	}
+	notifier.delivered = append(notifier.delivered, recipientEmail)
+	return nil
+}
+
+func (notifier *failingNotifier) deliveredTo() []string {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	return append([]string{}, notifier.delivered...)
+}
+
+func TestFailedDeliveriesAreRetriedWithBackoff(t *testing.T) {
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com", "test2@gmail.com"}, connection)
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, connection)
+	transactionManager.RetrieveStudentRecipients("test@gmail.com", "hello", nil, connection)
+
+	notifier := &failingNotifier{failingRecipients: map[string]bool{"test2@gmail.com": true}}
+	now := time.Now()
+	backoff := time.Minute
+
+	if err := transactionManager.DeliverPendingNotifications(notifier, 3, backoff, now, connection); err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+	assertEquals(t, []string{"test1@gmail.com"}, notifier.deliveredTo())
+
+	// the second attempt is due after the backoff and the third after twice the backoff
+	transactionManager.DeliverPendingNotifications(notifier, 3, backoff, now.Add(backoff-time.Second), connection)
+	transactionManager.DeliverPendingNotifications(notifier, 3, backoff, now.Add(backoff), connection)
+	transactionManager.DeliverPendingNotifications(notifier, 3, backoff, now.Add(2*backoff), connection)
+	transactionManager.DeliverPendingNotifications(notifier, 3, backoff, now.Add(3*backoff), connection)
+	transactionManager.DeliverPendingNotifications(notifier, 3, backoff, now.Add(time.Hour), connection)
+
+	deliveries, userError, dbError := transactionManager.RetrieveNotificationDeliveries(1, connection)
+	assertEquals(t, nil, userError)
+	assertEquals(t, nil, dbError)
+	assertEquals(t, 2, len(deliveries))
+	assertEquals(t, models.DELIVERY_SENT, deliveries[0].Status)
+	assertEquals(t, 1, deliveries[0].Attempts)
+	assertEquals(t, models.DELIVERY_FAILED, deliveries[1].Status)
+	assertEquals(t, 3, deliveries[1].Attempts)
+	assertEquals(t, "mailbox unavailable", deliveries[1].LastError)
+	assertEquals(t, []string{"test1@gmail.com"}, notifier.deliveredTo())
+
+	_, userError, _ = transactionManager.RetrieveNotificationDeliveries(2, connection)
+	assertEquals(t, "Notification with id 2 does not exist in the database", userError.Error())
+}
+
+func TestQueuedNotificationsAreDeliveredByScheduler(t *testing.T) {
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com"}, connection)
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com"}, connection)
+
+	notifier := &failingNotifier{}
+	// the interval is long enough that only the queued signal can trigger the delivery
+	scheduler := schedulers.NewNotificationDeliveryScheduler(connection, transactionManager, notifier, time.Hour, 3, time.Minute)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	transactionManager.RetrieveStudentRecipients("test@gmail.com", "hello", nil, connection)
+
+	deadline := time.Now().Add(time.Second)
+	for len(notifier.deliveredTo()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("notification was not delivered by the scheduler")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEquals(t, []string{"test1@gmail.com"}, notifier.deliveredTo())
+}
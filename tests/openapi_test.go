@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"io/ioutil"
+	"learning-management-system/openapi"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestOpenApiDocument(t *testing.T) {
+	SetUpTestServer()
+
+	document := &openapi.Document{}
+	testGetJson("/api/openapi.json", 200, document, t)
+
+	assertEquals(t, openapi.OPENAPI_VERSION, document.OpenApi)
+	assertEquals(t, "RegisterStudentsToTeacher", document.Paths["/api/register"]["post"].OperationId)
+	assertEquals(t, "#/components/schemas/RegisterStudentsToTeacherRequest", document.Paths["/api/register"]["post"].RequestBody.Content["application/json"].Schema.Ref)
+	assertEquals(t, []string{"teacher", "students"}, document.Components.Schemas["RegisterStudentsToTeacherRequest"].Required)
+	assertEquals(t, "#/components/schemas/ProblemResponse", document.Paths["/api/register"]["post"].Responses["400"].Content["application/problem+json"].Schema.Ref)
+
+	parameter := document.Paths["/api/students/{email}/notifications"]["get"].Parameters[0]
+	assertEquals(t, &openapi.Parameter{Name: "email", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}, parameter)
+	assertEquals(t, "array", document.Paths["/api/commonstudents"]["get"].Parameters[0].Schema.Type)
+	assertEquals(t, "binary", document.Paths["/api/import"]["post"].RequestBody.Content["multipart/form-data"].Schema.Properties["file"].Format)
+}
+
+func TestSwaggerUi(t *testing.T) {
+	SetUpTestServer()
+
+	resp, err := http.Get(testServerUrl + "/api/docs")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	assertEquals(t, 200, resp.StatusCode)
+	assertEquals(t, true, strings.Contains(string(body), `url: "/api/openapi.json"`))
+	assertEquals(t, false, strings.Contains(string(body), "https://"))
+
+	// the assets of the page are served with it rather than loaded from a CDN
+	for path, contentType := range map[string]string{
+		"/api/docs/swagger-ui.css":       "text/css; charset=utf-8",
+		"/api/docs/swagger-ui-bundle.js": "application/javascript; charset=utf-8",
+	} {
+		assetResp, err := http.Get(testServerUrl + path)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		asset, _ := ioutil.ReadAll(assetResp.Body)
+		assetResp.Body.Close()
+		assertEquals(t, 200, assetResp.StatusCode)
+		assertEquals(t, contentType, assetResp.Header.Get("Content-Type"))
+		assertEquals(t, true, strings.Contains(string(body), `"`+path+`"`))
+		assertEquals(t, true, len(asset) > 0)
+	}
+
+	notFoundResp, err := http.Get(testServerUrl + "/api/docs/swagger-ui.js.map")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	notFoundResp.Body.Close()
+	assertEquals(t, 404, notFoundResp.StatusCode)
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+
+	// the populate endpoints take bare emails and objects with a profile in the same list
+	testPost(`{"teachers": ["teacherken@gmail.com", {"email": "teacherjoe@gmail.com", "name": "Joe Lim", "homeroom": "7A"}]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com", {"email": "studenthon@gmail.com", "name": "Hon Tan", "display_name": "Hon", "grade_level": "7",`+
+		` "homeroom": "7A", "phone": "+65 6123 4567", "metadata": {"house": "red"}}]}`, "/api/v2/students", 204, "", t)
+
+	testGet("/api/students/studentjon@gmail.com", 200, `{"email":"studentjon@gmail.com","suspended":false}`, t)
+	testGet("/api/v1/students/studenthon@gmail.com", 200, `{"email":"studenthon@gmail.com","suspended":false,"name":"Hon Tan","display_name":"Hon",`+
+		`"grade_level":"7","homeroom":"7A","phone":"+65 6123 4567","metadata":{"house":"red"}}`, t)
+	testGet("/api/v2/teachers/teacherjoe@gmail.com", 200, `{"email":"teacherjoe@gmail.com","name":"Joe Lim","homeroom":"7A"}`, t)
+
+	// a bare email creates a missing person but keeps the profile of an existing one, an object replaces it
+	testPost(`{"students": ["studenthon@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	student := &types.StudentResponse{}
+	testGetJson("/api/students/studenthon@gmail.com", 200, student, t)
+	assertEquals(t, "Hon Tan", student.Name)
+	testPost(`{"teachers": [{"email": "teacherjoe@gmail.com", "name": "Joe Lim"}]}`, "/api/populateteachers", 204, "", t)
+	testGet("/api/teachers/teacherjoe@gmail.com", 200, `{"email":"teacherjoe@gmail.com","name":"Joe Lim"}`, t)
+
+	// a patch changes the fields it holds, an empty string clears a field and an empty object clears the metadata
+	testRequestWithToken("PATCH", "/api/students/studenthon@gmail.com", `{"grade_level": "8", "homeroom": "", "metadata": {}}`, "", 200,
+		`{"email":"studenthon@gmail.com","suspended":false,"name":"Hon Tan","display_name":"Hon","grade_level":"8","phone":"+65 6123 4567"}`, t)
+	testRequestWithToken("PATCH", "/api/v2/teachers/teacherken@gmail.com", `{"display_name": "Mr Ken", "metadata": {"subject": "math"}}`, "", 200,
+		`{"email":"teacherken@gmail.com","display_name":"Mr Ken","metadata":{"subject":"math"}}`, t)
+	testRequestWithToken("PATCH", "/api/v2/teachers/teacherken@gmail.com", `{"phone": "6123 4567"}`, "", 200,
+		`{"email":"teacherken@gmail.com","display_name":"Mr Ken","phone":"6123 4567","metadata":{"subject":"math"}}`, t)
+
+	// deleting a student deletes their registrations, the teacher keeps their other students
+	testPost(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "/api/register", 204, "", t)
+	testRequestWithToken("DELETE", "/api/students/studenthon@gmail.com", "", "", 204, "", t)
+	testProblem("GET", "/api/students/studenthon@gmail.com", "", "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studenthon@gmail.com does not exist in the database", t)
+	testGet("/api/commonstudents?teacher=teacherken%40gmail.com", 200, `{"students":["studentjon@gmail.com"]}`, t)
+
+	// deleting a teacher deletes their registrations, their students are kept
+	testRequestWithToken("DELETE", "/api/v1/teachers/teacherken@gmail.com", "", "", 204, "", t)
+	testProblem("GET", "/api/teachers/teacherken@gmail.com", "", "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherken@gmail.com does not exist in the database", t)
+	testGet("/api/students/studentjon@gmail.com", 200, `{"email":"studentjon@gmail.com","suspended":false}`, t)
+}
+
+func TestProfileErrors(t *testing.T) {
+	SetUpTestServer()
+	testDeletePath("/api/clear", 204, "", t)
+	testPost(`{"teachers": ["teacherken@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"students": ["studentjon@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+
+	testProblem("GET", "/api/students/studentjon", "", "", 400, problems.INVALID_EMAIL, "The email address studentjon has an invalid format", t)
+	testProblem("PATCH", "/api/students/studentkim@gmail.com", `{"name": "Kim"}`, "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentkim@gmail.com does not exist in the database", t)
+	testProblem("PATCH", "/api/students/studentjon@gmail.com", `{"phone": "call me"}`, "", 400,
+		problems.INVALID_FIELD, "The field phone must be a phone number", t)
+	testProblem("DELETE", "/api/teachers/teacherbob@gmail.com", "", "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherbob@gmail.com does not exist in the database", t)
+
+	problem := testProblem("POST", "/api/populatestudents", `{"students": ["studentbob@gmail.com", {"email": "studentkim@gmail.com", "phone": "12"}]}`, "", 400,
+		problems.INVALID_FIELD, "The field phone must be a phone number", t)
+	assertEquals(t, []string{"studentkim@gmail.com"}, problem.Errors[0].Emails)
+	testProblem("POST", "/api/populatestudents", `{"students": [{"name": "Kim"}]}`, "", 400, problems.INVALID_EMAIL, "The email address  has an invalid format", t)
+	testProblem("POST", "/api/populateteachers", `{"teachers": [42]}`, "", 400, problems.INVALID_FIELD, "The field email must be a string", t)
+
+	// a populate that fails creates nobody
+	testProblem("GET", "/api/students/studentbob@gmail.com", "", "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentbob@gmail.com does not exist in the database", t)
+}
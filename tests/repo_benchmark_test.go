@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/repositories"
+	"sort"
+	"testing"
+)
+
+const benchmarkTeacherCount = 5
+const benchmarkStudentCount = 2000
+
+// setUpBenchmarkDb registers every student to the first teacher and every other student to each further teacher,
+// so that the common students of all teachers are a sixteenth of the class
+func setUpBenchmarkDb(b *testing.B) (*repositories.Repositories, *gorm.DB, []string) {
+	connection := SetUpTestDb(&database.Credentials{Driver: database.SQLITE, Name: database.SQLITE_IN_MEMORY})
+	if connection.GetDb() == nil {
+		b.Fatal("could not open in-memory sqlite database")
+	}
+	db := connection.GetDb()
+	repos := repositories.NewSqlRepositories()
+
+	studentEmails := make([]string, benchmarkStudentCount)
+	for i := range studentEmails {
+		studentEmails[i] = fmt.Sprintf("student%d@gmail.com", i)
+	}
+	teacherEmails := make([]string, benchmarkTeacherCount)
+	for i := range teacherEmails {
+		teacherEmails[i] = fmt.Sprintf("teacher%d@gmail.com", i)
+	}
+	repos.Students.CreateStudentsIfNotExist(studentEmails, db)
+	repos.Teachers.CreateTeachersIfNotExist(teacherEmails, db)
+
+	for i, teacherEmail := range teacherEmails {
+		registered := make([]string, 0)
+		for j, studentEmail := range studentEmails {
+			if j%(1<<i) == 0 || i == 0 {
+				registered = append(registered, studentEmail)
+			}
+		}
+		if err := repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists(teacherEmail, registered, db.Session(&gorm.Session{CreateBatchSize: 500})); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return repos, db, teacherEmails
+}
+
+// commonStudentEmailsInGo is how common students used to be computed, loading every relationship of the teachers
+// with their preloads and counting in Go
+func commonStudentEmailsInGo(repos *repositories.Repositories, teacherEmails []string, db *gorm.DB) ([]string, error) {
+	relationships, err := repos.Registrations.GetRelationshipsByTeacherEmails(teacherEmails, db)
+	if err != nil {
+		return nil, err
+	}
+
+	studentEmailCountMap := make(map[string]int)
+	for _, relationship := range relationships {
+		studentEmailCountMap[relationship.StudentEmail]++
+	}
+
+	commonStudents := make([]string, 0)
+	for studentEmail, count := range studentEmailCountMap {
+		if count == len(teacherEmails) {
+			commonStudents = append(commonStudents, studentEmail)
+		}
+	}
+	sort.Strings(commonStudents)
+	return commonStudents, nil
+}
+
+func BenchmarkCommonStudentEmails(b *testing.B) {
+	repos, db, teacherEmails := setUpBenchmarkDb(b)
+
+	expected, _ := commonStudentEmailsInGo(repos, teacherEmails, db)
+	actual, _ := repos.Registrations.GetStudentEmailsRegisteredToAtLeast(teacherEmails, len(teacherEmails), "", 0, db)
+	if len(expected) != benchmarkStudentCount>>(benchmarkTeacherCount-1) || fmt.Sprint(expected) != fmt.Sprint(actual) {
+		b.Fatalf("both approaches should find the same %d students", len(expected))
+	}
+
+	b.Run("InGo", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := commonStudentEmailsInGo(repos, teacherEmails, db); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("InDatabase", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repos.Registrations.GetStudentEmailsRegisteredToAtLeast(teacherEmails, len(teacherEmails), "", 0, db); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
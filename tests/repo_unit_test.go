@@ -1,187 +1,1024 @@
 package tests
 
 import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
 	"learning-management-system/models"
 	"learning-management-system/repositories"
 	"testing"
+	"time"
 )
 
 func TestCreateAndRetrieveStudent(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com"}, db)
-	student, err := studentRepo.GetStudentByEmail("test1@gmail.com", db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
-	assertEquals(t, &models.Student{Email: "test1@gmail.com"}, student)
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com"}, db)
+		student, err := studentRepo.GetStudentByEmail("test1@gmail.com", db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, &models.Student{Email: "test1@gmail.com"}, student)
+	})
 }
 
 func TestCreateAndRetrieveTeacher(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	teacherRepo := repositories.NewTeacherRepo()
-	teacherRepo.CreateTeachersIfNotExist([]string{"test1@gmail.com"}, db)
-	teacher, err := teacherRepo.GetTeacherByEmail("test1@gmail.com", db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
-	assertEquals(t, &models.Teacher{Email: "test1@gmail.com"}, teacher)
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		teacherRepo := repos.Teachers
+		teacherRepo.CreateTeachersIfNotExist([]string{"test1@gmail.com"}, db)
+		teacher, err := teacherRepo.GetTeacherByEmail("test1@gmail.com", db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, &models.Teacher{Email: "test1@gmail.com"}, teacher)
+	})
 }
 
 func TestUpdateStudent(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com"}, db)
-	student := &models.Student{Email: "test1@gmail.com", IsSuspended: true}
-	studentRepo.UpdateStudent(student, db)
-	updatedStudent, err := studentRepo.GetStudentByEmail("test1@gmail.com", db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
-	expectedStudent := &models.Student{Email: "test1@gmail.com", IsSuspended: true}
-	assertEquals(t, expectedStudent, updatedStudent)
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com"}, db)
+		student := &models.Student{Email: "test1@gmail.com", IsSuspended: true}
+		studentRepo.UpdateStudent(student, db)
+		updatedStudent, err := studentRepo.GetStudentByEmail("test1@gmail.com", db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		expectedStudent := &models.Student{Email: "test1@gmail.com", IsSuspended: true}
+		assertEquals(t, expectedStudent, updatedStudent)
+	})
 }
 
 func TestCreateAndRetrieveMultipleStudents(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	students, err := studentRepo.GetStudentsByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		students, err := studentRepo.GetStudentsByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
 
-	expectedStudents := []*models.Student{
-		{Email: "test1@gmail.com", IsSuspended: false}, {Email: "test2@gmail.com", IsSuspended: false},
-	}
+		expectedStudents := []*models.Student{
+			{Email: "test1@gmail.com", IsSuspended: false}, {Email: "test2@gmail.com", IsSuspended: false},
+		}
 
-	assertEquals(t, expectedStudents, students)
+		assertEquals(t, expectedStudents, students)
+	})
 }
 
 func TestCreateAndRetrieveMultipleTeachers(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	teacherRepo := repositories.NewTeacherRepo()
-	teacherRepo.CreateTeachersIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	teachers, err := teacherRepo.GetTeachersByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		teacherRepo := repos.Teachers
+		teacherRepo.CreateTeachersIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		teachers, err := teacherRepo.GetTeachersByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
 
-	expectedTeachers := []*models.Teacher{
-		{Email: "test1@gmail.com"}, {Email: "test2@gmail.com"},
-	}
+		expectedTeachers := []*models.Teacher{
+			{Email: "test1@gmail.com"}, {Email: "test2@gmail.com"},
+		}
 
-	assertEquals(t, expectedTeachers, teachers)
+		assertEquals(t, expectedTeachers, teachers)
+	})
 }
 
 func TestDeleteAllStudents(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	studentRepo.DeleteAllStudents(db)
-	students, err := studentRepo.GetStudentsByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		studentRepo.DeleteAllStudents(db)
+		students, err := studentRepo.GetStudentsByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
 
-	assertEquals(t, 0, len(students))
+		assertEquals(t, 0, len(students))
+	})
 }
 
 func TestDeleteAllTeachers(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	teacherRepo := repositories.NewTeacherRepo()
-	teacherRepo.CreateTeachersIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	teacherRepo.DeleteAllTeachers(db)
-	teachers, err := teacherRepo.GetTeachersByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		teacherRepo := repos.Teachers
+		teacherRepo.CreateTeachersIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		teacherRepo.DeleteAllTeachers(db)
+		teachers, err := teacherRepo.GetTeachersByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
 
-	assertEquals(t, 0, len(teachers))
+		assertEquals(t, 0, len(teachers))
+	})
 }
 
 func TestCreateAndRetrieveRegisterRelation(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	teacherRepo := repositories.NewTeacherRepo()
-	relationshipRepo := repositories.NewRegisterRelationshipRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
-	teacherRepo.CreateTeachersIfNotExist([]string{"test3@gmail.com"}, db)
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		teacherRepo := repos.Teachers
+		relationshipRepo := repos.Registrations
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		teacherRepo.CreateTeachersIfNotExist([]string{"test3@gmail.com"}, db)
 
-	relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test3@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test3@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
 
-	relationships, err := relationshipRepo.GetRelationshipsByTeacherEmail("test3@gmail.com", db)
+		relationships, err := relationshipRepo.GetRelationshipsByTeacherEmail("test3@gmail.com", db)
 
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
 
-	expectedRelationships := []*models.RegisterRelationship{
-		{TeacherEmail: "test3@gmail.com", StudentEmail: "test1@gmail.com", RegisteredStudent: models.Student{Email: "test1@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test3@gmail.com"}},
-		{TeacherEmail: "test3@gmail.com", StudentEmail: "test2@gmail.com", RegisteredStudent: models.Student{Email: "test2@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test3@gmail.com"}},
-	}
+		expectedRelationships := []*models.RegisterRelationship{
+			{TeacherEmail: "test3@gmail.com", StudentEmail: "test1@gmail.com", RegisteredStudent: models.Student{Email: "test1@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test3@gmail.com"}},
+			{TeacherEmail: "test3@gmail.com", StudentEmail: "test2@gmail.com", RegisteredStudent: models.Student{Email: "test2@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test3@gmail.com"}},
+		}
 
-	assertEquals(t, expectedRelationships, relationships)
+		assertEquals(t, expectedRelationships, relationships)
+	})
 }
 
 func TestGetRegisterRelationshipsByTeacherEmails(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	teacherRepo := repositories.NewTeacherRepo()
-	relationshipRepo := repositories.NewRegisterRelationshipRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
-	teacherRepo.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		teacherRepo := repos.Teachers
+		relationshipRepo := repos.Registrations
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		teacherRepo.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
 
-	relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
 
-	relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com", "test3@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com", "test3@gmail.com"}, db)
 
-	relationships, err := relationshipRepo.GetRelationshipsByTeacherEmails([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		relationships, err := relationshipRepo.GetRelationshipsByTeacherEmails([]string{"test4@gmail.com", "test5@gmail.com"}, db)
 
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
-	}
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
 
-	expectedRelationships := []*models.RegisterRelationship{
-		{TeacherEmail: "test4@gmail.com", StudentEmail: "test1@gmail.com", RegisteredStudent: models.Student{Email: "test1@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test4@gmail.com"}},
-		{TeacherEmail: "test5@gmail.com", StudentEmail: "test1@gmail.com", RegisteredStudent: models.Student{Email: "test1@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test5@gmail.com"}},
-		{TeacherEmail: "test4@gmail.com", StudentEmail: "test2@gmail.com", RegisteredStudent: models.Student{Email: "test2@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test4@gmail.com"}},
-		{TeacherEmail: "test5@gmail.com", StudentEmail: "test3@gmail.com", RegisteredStudent: models.Student{Email: "test3@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test5@gmail.com"}},
-	}
-	assertEquals(t, expectedRelationships, relationships)
+		expectedRelationships := []*models.RegisterRelationship{
+			{TeacherEmail: "test4@gmail.com", StudentEmail: "test1@gmail.com", RegisteredStudent: models.Student{Email: "test1@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test4@gmail.com"}},
+			{TeacherEmail: "test5@gmail.com", StudentEmail: "test1@gmail.com", RegisteredStudent: models.Student{Email: "test1@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test5@gmail.com"}},
+			{TeacherEmail: "test4@gmail.com", StudentEmail: "test2@gmail.com", RegisteredStudent: models.Student{Email: "test2@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test4@gmail.com"}},
+			{TeacherEmail: "test5@gmail.com", StudentEmail: "test3@gmail.com", RegisteredStudent: models.Student{Email: "test3@gmail.com", IsSuspended: false}, Teacher: models.Teacher{Email: "test5@gmail.com"}},
+		}
+		assertEquals(t, expectedRelationships, relationships)
+	})
+}
+
+func TestGetStudentEmailsRegisteredToAtLeast(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		relationshipRepo := repos.Registrations
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com", "test6@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test3@gmail.com", "test1@gmail.com", "test2@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test3@gmail.com", "test1@gmail.com"}, db)
+
+		studentEmails, err := relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test4@gmail.com", "test5@gmail.com"}, 2, "", 0, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test1@gmail.com", "test3@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test4@gmail.com", "test5@gmail.com", "test4@gmail.com"}, 2, "", 0, db)
+		assertEquals(t, []string{"test1@gmail.com", "test3@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test4@gmail.com"}, 1, "", 0, db)
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test4@gmail.com", "test6@gmail.com"}, 2, "", 0, db)
+		assertEquals(t, []string{}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{}, 0, "", 0, db)
+		assertEquals(t, []string{}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test4@gmail.com"}, 1, "", 2, db)
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test4@gmail.com"}, 1, "test2@gmail.com", 2, db)
+		assertEquals(t, []string{"test3@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredToAtLeast([]string{"test5@gmail.com", "test6@gmail.com"}, 1, "", 0, db)
+		assertEquals(t, []string{"test1@gmail.com", "test3@gmail.com"}, studentEmails)
+	})
+}
+
+func TestGetStudentEmailsRegisteredOnlyTo(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		relationshipRepo := repos.Registrations
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com", "test6@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test3@gmail.com", "test1@gmail.com", "test2@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test6@gmail.com", []string{"test3@gmail.com"}, db)
+
+		studentEmails, err := relationshipRepo.GetStudentEmailsRegisteredOnlyTo("test4@gmail.com", []string{"test5@gmail.com", "test6@gmail.com"}, "", 0, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test2@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredOnlyTo("test4@gmail.com", []string{"test5@gmail.com"}, "test1@gmail.com", 1, db)
+		assertEquals(t, []string{"test2@gmail.com"}, studentEmails)
+
+		studentEmails, _ = relationshipRepo.GetStudentEmailsRegisteredOnlyTo("test4@gmail.com", []string{}, "", 0, db)
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, studentEmails)
+	})
+}
+
+func TestGetRecipientEmails(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com"}, db)
+		notification := &models.Notification{TeacherEmail: "test4@gmail.com", Message: "hello", SentAt: time.Now()}
+		repos.Notifications.CreateNotification(notification, []string{"test3@gmail.com", "test1@gmail.com", "test2@gmail.com"}, db)
+
+		recipientEmails, err := repos.Notifications.GetRecipientEmails(notification.ID, "", 2, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, recipientEmails)
+
+		recipientEmails, _ = repos.Notifications.GetRecipientEmails(notification.ID, "test2@gmail.com", 2, db)
+		assertEquals(t, []string{"test3@gmail.com"}, recipientEmails)
+
+		recipientEmails, _ = repos.Notifications.GetRecipientEmails(notification.ID+1, "", 0, db)
+		assertEquals(t, []string{}, recipientEmails)
+	})
 }
 
 func TestDeleteAllRegisterRelationships(t *testing.T) {
-	connection, _ := SetUpTestDb()
-	db := connection.GetDb()
-	studentRepo := repositories.NewStudentRepo()
-	teacherRepo := repositories.NewTeacherRepo()
-	relationshipRepo := repositories.NewRegisterRelationshipRepo()
-	studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
-	teacherRepo.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		teacherRepo := repos.Teachers
+		relationshipRepo := repos.Registrations
+		studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		teacherRepo.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
+
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com", "test3@gmail.com"}, db)
+
+		if err := relationshipRepo.DeleteAllRegisterRelationships(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		relationships, err := relationshipRepo.GetRelationshipsByTeacherEmails([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		assertEquals(t, 0, len(relationships))
+	})
+}
+
+func TestCreateExistingStudentsAndRegisterRelationshipsIsIgnored(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		studentRepo := repos.Students
+		teacherRepo := repos.Teachers
+		relationshipRepo := repos.Registrations
+		if err := studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		studentRepo.UpdateStudent(&models.Student{Email: "test1@gmail.com", IsSuspended: true}, db)
+		if err := studentRepo.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		teacherRepo.CreateTeachersIfNotExist([]string{"test3@gmail.com"}, db)
+		if err := teacherRepo.CreateTeachersIfNotExist([]string{"test3@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test3@gmail.com", []string{"test1@gmail.com"}, db)
+		if err := relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test3@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		students, err := studentRepo.GetStudentsByEmails([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		expectedStudents := []*models.Student{
+			{Email: "test1@gmail.com", IsSuspended: true}, {Email: "test2@gmail.com", IsSuspended: false},
+		}
+		assertEquals(t, expectedStudents, students)
+
+		relationships, err := relationshipRepo.GetRelationshipsByTeacherEmail("test3@gmail.com", db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 2, len(relationships))
+	})
+}
+
+func TestCreateLiftAndRetrieveStudentSuspensions(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		suspensionRepo := repos.Suspensions
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		firstSuspendedAt := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+		secondSuspendedAt := time.Date(2022, 2, 1, 8, 0, 0, 0, time.UTC)
+		liftedAt := time.Date(2022, 1, 15, 8, 0, 0, 0, time.UTC)
+
+		suspensionRepo.CreateSuspension(&models.StudentSuspension{StudentEmail: "test1@gmail.com", SuspendedBy: "test3@gmail.com", Reason: "late", SuspendedAt: firstSuspendedAt}, db)
+		suspensionRepo.CreateSuspension(&models.StudentSuspension{StudentEmail: "test2@gmail.com", SuspendedAt: firstSuspendedAt}, db)
+		if err := suspensionRepo.LiftActiveSuspensions("test1@gmail.com", liftedAt, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		suspensionRepo.CreateSuspension(&models.StudentSuspension{StudentEmail: "test1@gmail.com", Reason: "again", SuspendedAt: secondSuspendedAt}, db)
+
+		suspensions, err := suspensionRepo.GetSuspensionsByStudentEmail("test1@gmail.com", db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 2, len(suspensions))
+		assertEquals(t, "late", suspensions[0].Reason)
+		assertEquals(t, "test3@gmail.com", suspensions[0].SuspendedBy)
+		assertEquals(t, true, suspensions[0].SuspendedAt.Equal(firstSuspendedAt))
+		assertEquals(t, true, suspensions[0].LiftedAt != nil && suspensions[0].LiftedAt.Equal(liftedAt))
+		assertEquals(t, "again", suspensions[1].Reason)
+		assertEquals(t, true, suspensions[1].LiftedAt == nil)
+
+		activeSuspension, err := suspensionRepo.GetActiveSuspensionByStudentEmail("test1@gmail.com", secondSuspendedAt, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, "again", activeSuspension.Reason)
+
+		if err := suspensionRepo.DeleteAllSuspensions(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		activeSuspension, err = suspensionRepo.GetActiveSuspensionByStudentEmail("test2@gmail.com", secondSuspendedAt, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, (*models.StudentSuspension)(nil), activeSuspension)
+	})
+}
+
+func TestTimeBoundedStudentSuspensions(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		suspensionRepo := repos.Suspensions
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		suspendedAt := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+		until := time.Date(2022, 1, 8, 8, 0, 0, 0, time.UTC)
 
-	relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
+		suspensionRepo.CreateSuspension(&models.StudentSuspension{StudentEmail: "test1@gmail.com", SuspendedAt: suspendedAt, Until: &until}, db)
+		suspensionRepo.CreateSuspension(&models.StudentSuspension{StudentEmail: "test2@gmail.com", SuspendedAt: suspendedAt}, db)
 
-	relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com", "test3@gmail.com"}, db)
+		suspendedStudentEmails, err := suspensionRepo.GetActivelySuspendedStudentEmails([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, until.Add(-time.Second), db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, suspendedStudentEmails)
 
-	if err := relationshipRepo.DeleteAllRegisterRelationships(db); err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
+		suspendedStudentEmails, err = suspensionRepo.GetActivelySuspendedStudentEmails([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, until, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test2@gmail.com"}, suspendedStudentEmails)
+
+		expiredSuspensions, err := suspensionRepo.GetExpiredSuspensions(until, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 1, len(expiredSuspensions))
+		assertEquals(t, "test1@gmail.com", expiredSuspensions[0].StudentEmail)
+
+		if err := suspensionRepo.LiftSuspension(expiredSuspensions[0].ID, until, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		expiredSuspensions, err = suspensionRepo.GetExpiredSuspensions(until, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 0, len(expiredSuspensions))
+
+		// the end of a suspension can be moved, or removed so that it lasts until it is lifted
+		activeSuspension, _ := suspensionRepo.GetActiveSuspensionByStudentEmail("test2@gmail.com", until, db)
+		if err := suspensionRepo.UpdateSuspensionUntil(activeSuspension.ID, &until, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		suspendedStudentEmails, _ = suspensionRepo.GetActivelySuspendedStudentEmails([]string{"test2@gmail.com"}, until, db)
+		assertEquals(t, 0, len(suspendedStudentEmails))
+		if err := suspensionRepo.UpdateSuspensionUntil(activeSuspension.ID, nil, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		suspendedStudentEmails, _ = suspensionRepo.GetActivelySuspendedStudentEmails([]string{"test2@gmail.com"}, until, db)
+		assertEquals(t, []string{"test2@gmail.com"}, suspendedStudentEmails)
+	})
+}
+
+func TestDeleteRegisterRelationships(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		relationshipRepo := repos.Registrations
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		relationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com"}, db)
+
+		if err := relationshipRepo.DeleteRegisterRelationships("test4@gmail.com", []string{"test1@gmail.com", "test3@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		relationships, err := relationshipRepo.GetRelationshipsByTeacherEmails([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		relationshipKeys := helpers.Map(relationships, func(relationship *models.RegisterRelationship) string {
+			return relationship.TeacherEmail + "/" + relationship.StudentEmail
+		})
+		assertEquals(t, []string{"test5@gmail.com/test1@gmail.com", "test4@gmail.com/test2@gmail.com"}, relationshipKeys)
+	})
+}
+
+func TestCreateAndRetrieveNotificationInbox(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		notificationRepo := repos.Notifications
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test3@gmail.com"}, db)
+		firstSentAt := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+		secondSentAt := time.Date(2022, 1, 2, 8, 0, 0, 0, time.UTC)
+
+		first := &models.Notification{TeacherEmail: "test3@gmail.com", Message: "first", SentAt: firstSentAt}
+		second := &models.Notification{TeacherEmail: "test3@gmail.com", Message: "second", SentAt: secondSentAt}
+		if err := notificationRepo.CreateNotification(first, []string{"test1@gmail.com", "test2@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		if err := notificationRepo.CreateNotification(second, []string{"test1@gmail.com"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		notificationRepo.CreateNotification(&models.Notification{TeacherEmail: "test3@gmail.com", Message: "nobody", SentAt: secondSentAt}, []string{}, db)
+
+		inbox, err := notificationRepo.GetInboxByStudentEmail("test1@gmail.com", false, 0, 10, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		messages := helpers.Map(inbox, func(entry *models.NotificationRecipient) string {
+			return entry.Notification.Message
+		})
+		assertEquals(t, []string{"second", "first"}, messages)
+		assertEquals(t, "test3@gmail.com", inbox[1].Notification.TeacherEmail)
+
+		inbox, _ = notificationRepo.GetInboxByStudentEmail("test1@gmail.com", false, second.ID, 1, db)
+		assertEquals(t, 1, len(inbox))
+		assertEquals(t, first.ID, inbox[0].NotificationID)
+
+		entry, _ := notificationRepo.GetInboxEntry(second.ID, "test2@gmail.com", db)
+		assertEquals(t, (*models.NotificationRecipient)(nil), entry)
+
+		readAt := time.Date(2022, 1, 3, 8, 0, 0, 0, time.UTC)
+		if err := notificationRepo.MarkInboxEntryAsRead(second.ID, "test1@gmail.com", readAt, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		notificationRepo.MarkInboxEntryAsRead(second.ID, "test1@gmail.com", readAt.Add(time.Hour), db)
+		entry, _ = notificationRepo.GetInboxEntry(second.ID, "test1@gmail.com", db)
+		assertEquals(t, true, entry.ReadAt != nil && entry.ReadAt.Equal(readAt))
+
+		unread, _ := notificationRepo.CountInboxByStudentEmail("test1@gmail.com", true, db)
+		total, _ := notificationRepo.CountInboxByStudentEmail("test1@gmail.com", false, db)
+		assertEquals(t, int64(1), unread)
+		assertEquals(t, int64(2), total)
+
+		inbox, _ = notificationRepo.GetInboxByStudentEmail("test1@gmail.com", true, 0, 10, db)
+		assertEquals(t, 1, len(inbox))
+		assertEquals(t, "first", inbox[0].Notification.Message)
+
+		if err := notificationRepo.DeleteAllNotifications(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		total, _ = notificationRepo.CountInboxByStudentEmail("test1@gmail.com", false, db)
+		assertEquals(t, int64(0), total)
+	})
+}
+
+func TestCreateDueAndUpdateNotificationDeliveries(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		deliveryRepo := repos.Deliveries
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test3@gmail.com"}, db)
+		sentAt := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+		notification := &models.Notification{TeacherEmail: "test3@gmail.com", Message: "hello", SentAt: sentAt}
+		repos.Notifications.CreateNotification(notification, []string{"test1@gmail.com", "test2@gmail.com"}, db)
+
+		if err := deliveryRepo.CreateDeliveries(notification.ID, []string{"test2@gmail.com", "test1@gmail.com"}, sentAt, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		deliveryRepo.CreateDeliveries(notification.ID, []string{"test1@gmail.com"}, sentAt, db)
+
+		due, err := deliveryRepo.GetDueDeliveries(sentAt.Add(-time.Second), 10, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 0, len(due))
+
+		due, _ = deliveryRepo.GetDueDeliveries(sentAt, 10, db)
+		assertEquals(t, 2, len(due))
+		assertEquals(t, "test1@gmail.com", due[0].StudentEmail)
+		assertEquals(t, "hello", due[0].Notification.Message)
+
+		deliveredAt := sentAt.Add(time.Minute)
+		due[0].Status = models.DELIVERY_SENT
+		due[0].Attempts = 1
+		due[0].DeliveredAt = &deliveredAt
+		due[1].Attempts = 1
+		due[1].LastError = "mailbox unavailable"
+		due[1].NextAttemptAt = sentAt.Add(time.Hour)
+		for _, delivery := range due {
+			if err := deliveryRepo.UpdateDelivery(delivery, db); err != nil {
+				t.Errorf("Error throwned: %s", err.Error())
+			}
+		}
+
+		due, _ = deliveryRepo.GetDueDeliveries(sentAt.Add(time.Minute), 10, db)
+		assertEquals(t, 0, len(due))
+		due, _ = deliveryRepo.GetDueDeliveries(sentAt.Add(time.Hour), 1, db)
+		assertEquals(t, 1, len(due))
+		assertEquals(t, "test2@gmail.com", due[0].StudentEmail)
+
+		deliveries, _ := deliveryRepo.GetDeliveriesByNotificationId(notification.ID, db)
+		assertEquals(t, 2, len(deliveries))
+		assertEquals(t, models.DELIVERY_SENT, deliveries[0].Status)
+		assertEquals(t, true, deliveries[0].DeliveredAt != nil && deliveries[0].DeliveredAt.Equal(deliveredAt))
+		assertEquals(t, models.DELIVERY_PENDING, deliveries[1].Status)
+		assertEquals(t, "mailbox unavailable", deliveries[1].LastError)
+
+		if err := deliveryRepo.DeleteAllDeliveries(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		deliveries, _ = deliveryRepo.GetDeliveriesByNotificationId(notification.ID, db)
+		assertEquals(t, 0, len(deliveries))
+	})
+}
+
+func TestCreateAndRetrieveWebhookSubscriptionsAndDeliveries(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		subscriptionRepo := repos.Webhooks
+		deliveryRepo := repos.WebhookDeliveries
+		createdAt := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+
+		first := &models.WebhookSubscription{Url: "http://localhost/first", EventTypes: "students.registered,student.suspended", Secret: "s1", CreatedAt: createdAt}
+		second := &models.WebhookSubscription{Url: "http://localhost/second", EventTypes: "notification.sent", Secret: "s2", CreatedAt: createdAt}
+		for _, subscription := range []*models.WebhookSubscription{first, second} {
+			if err := subscriptionRepo.CreateSubscription(subscription, db); err != nil {
+				t.Errorf("Error throwned: %s", err.Error())
+			}
+		}
+
+		subscriptions, err := subscriptionRepo.GetAllSubscriptions(db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 2, len(subscriptions))
+		assertEquals(t, []string{"students.registered", "student.suspended"}, subscriptions[0].Events())
+		assertEquals(t, true, subscriptions[1].IsSubscribedTo("notification.sent"))
+
+		subscription, _ := subscriptionRepo.GetSubscriptionById(second.ID, db)
+		assertEquals(t, "s2", subscription.Secret)
+		subscription, _ = subscriptionRepo.GetSubscriptionById(second.ID+1, db)
+		assertEquals(t, (*models.WebhookSubscription)(nil), subscription)
+
+		deliveries := []*models.WebhookDelivery{
+			{SubscriptionID: first.ID, EventType: "students.registered", Payload: `{"n":1}`, Status: models.DELIVERY_PENDING, NextAttemptAt: createdAt, CreatedAt: createdAt},
+			{SubscriptionID: first.ID, EventType: "student.suspended", Payload: `{"n":2}`, Status: models.DELIVERY_PENDING, NextAttemptAt: createdAt, CreatedAt: createdAt},
+			{SubscriptionID: second.ID, EventType: "notification.sent", Payload: `{"n":3}`, Status: models.DELIVERY_PENDING, NextAttemptAt: createdAt.Add(time.Hour), CreatedAt: createdAt},
+		}
+		if err := deliveryRepo.CreateWebhookDeliveries(deliveries, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		due, err := deliveryRepo.GetDueWebhookDeliveries(createdAt, 10, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, 2, len(due))
+		assertEquals(t, `{"n":1}`, due[0].Payload)
+		assertEquals(t, "http://localhost/first", due[0].Subscription.Url)
+
+		deliveredAt := createdAt.Add(time.Minute)
+		due[0].Status = models.DELIVERY_SENT
+		due[0].Attempts = 1
+		due[0].ResponseStatus = 200
+		due[0].DeliveredAt = &deliveredAt
+		if err := deliveryRepo.UpdateWebhookDelivery(due[0], db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		due, _ = deliveryRepo.GetDueWebhookDeliveries(createdAt.Add(time.Hour), 10, db)
+		assertEquals(t, 2, len(due))
+		assertEquals(t, "student.suspended", due[0].EventType)
+
+		deliveryLog, _ := deliveryRepo.GetWebhookDeliveriesBySubscriptionId(first.ID, 0, 10, db)
+		assertEquals(t, 2, len(deliveryLog))
+		assertEquals(t, "student.suspended", deliveryLog[0].EventType)
+		assertEquals(t, 200, deliveryLog[1].ResponseStatus)
+		deliveryLog, _ = deliveryRepo.GetWebhookDeliveriesBySubscriptionId(first.ID, deliveryLog[0].ID, 1, db)
+		assertEquals(t, 1, len(deliveryLog))
+		assertEquals(t, models.DELIVERY_SENT, deliveryLog[0].Status)
+		count, _ := deliveryRepo.CountWebhookDeliveriesBySubscriptionId(first.ID, db)
+		assertEquals(t, int64(2), count)
+
+		if err := deliveryRepo.DeleteWebhookDeliveriesBySubscriptionId(first.ID, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		if err := subscriptionRepo.DeleteSubscription(first.ID, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		subscriptions, _ = subscriptionRepo.GetAllSubscriptions(db)
+		assertEquals(t, 1, len(subscriptions))
+		count, _ = deliveryRepo.CountWebhookDeliveriesBySubscriptionId(second.ID, db)
+		assertEquals(t, int64(1), count)
+
+		deliveryRepo.DeleteAllWebhookDeliveries(db)
+		subscriptionRepo.DeleteAllSubscriptions(db)
+		subscriptions, _ = subscriptionRepo.GetAllSubscriptions(db)
+		assertEquals(t, 0, len(subscriptions))
+	})
+}
+
+func TestGetRosterAfter(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		repos.Students.CreateStudentsIfNotExist([]string{"test3@gmail.com", "test1@gmail.com", "test2@gmail.com"}, db)
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test5@gmail.com", "test4@gmail.com"}, db)
+		repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test2@gmail.com", "test1@gmail.com"}, db)
+		repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com"}, db)
+
+		students, err := repos.Students.GetStudentsAfterEmail("test1@gmail.com", 10, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []*models.Student{{Email: "test2@gmail.com"}, {Email: "test3@gmail.com"}}, students)
+
+		teachers, _ := repos.Teachers.GetTeachersAfterEmail("", 1, db)
+		assertEquals(t, []*models.Teacher{{Email: "test4@gmail.com"}}, teachers)
+
+		relationships, err := repos.Registrations.GetRelationshipsAfter("", "", 2, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []*models.RegisterRelationship{
+			{TeacherEmail: "test4@gmail.com", StudentEmail: "test1@gmail.com"},
+			{TeacherEmail: "test4@gmail.com", StudentEmail: "test2@gmail.com"},
+		}, relationships)
+
+		relationships, _ = repos.Registrations.GetRelationshipsAfter("test4@gmail.com", "test2@gmail.com", 2, db)
+		assertEquals(t, []*models.RegisterRelationship{{TeacherEmail: "test5@gmail.com", StudentEmail: "test1@gmail.com"}}, relationships)
+	})
+}
+
+func TestCreateAndRetrieveCoursesAndSections(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		courseRepo := repos.Courses
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+
+		if err := courseRepo.SaveCourse(&models.Course{Code: "MA101", Name: "Algebra"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		courseRepo.SaveCourse(&models.Course{Code: "CS101", Name: "Programming"}, db)
+		courseRepo.SaveCourse(&models.Course{Code: "MA101", Name: "Linear Algebra"}, db)
+
+		courses, _ := courseRepo.GetAllCourses(db)
+		assertEquals(t, []*models.Course{{Code: "CS101", Name: "Programming"}, {Code: "MA101", Name: "Linear Algebra"}}, courses)
+		course, _ := courseRepo.GetCourseByCode("PH101", db)
+		assertEquals(t, (*models.Course)(nil), course)
+
+		sectionA, err := courseRepo.CreateSectionIfNotExists("MA101", "A", db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		sameSection, _ := courseRepo.CreateSectionIfNotExists("MA101", "A", db)
+		assertEquals(t, sectionA.ID, sameSection.ID)
+		sectionB, _ := courseRepo.CreateSectionIfNotExists("MA101", "B", db)
+		courseRepo.CreateSectionIfNotExists("CS101", "A", db)
+
+		sections, _ := courseRepo.GetSectionsByCourseCode("MA101", db)
+		assertEquals(t, []string{"A", "B"}, helpers.Map(sections, func(section *models.Section) string { return section.Name }))
+		section, _ := courseRepo.GetSection("MA101", "C", db)
+		assertEquals(t, (*models.Section)(nil), section)
+
+		courseRepo.AssignTeachersToSectionIfNotAssigned(sectionA.ID, []string{"test5@gmail.com", "test4@gmail.com"}, db)
+		courseRepo.AssignTeachersToSectionIfNotAssigned(sectionA.ID, []string{"test4@gmail.com"}, db)
+		courseRepo.AssignTeachersToSectionIfNotAssigned(sectionB.ID, []string{"test4@gmail.com"}, db)
+		teacherEmails, _ := courseRepo.GetTeacherEmailsBySectionId(sectionA.ID, db)
+		assertEquals(t, []string{"test4@gmail.com", "test5@gmail.com"}, teacherEmails)
+
+		sections, _ = courseRepo.GetSectionsByTeacherEmail("test4@gmail.com", "MA101", db)
+		assertEquals(t, []uint{sectionA.ID, sectionB.ID}, helpers.Map(sections, func(section *models.Section) uint { return section.ID }))
+		sections, _ = courseRepo.GetSectionsByTeacherEmail("test4@gmail.com", "CS101", db)
+		assertEquals(t, 0, len(sections))
+
+		courseRepo.EnrollStudentsIfNotEnrolled(sectionA.ID, []string{"test2@gmail.com", "test1@gmail.com"}, db)
+		courseRepo.EnrollStudentsIfNotEnrolled(sectionA.ID, []string{"test1@gmail.com"}, db)
+		studentEmails, _ := courseRepo.GetStudentEmailsBySectionId(sectionA.ID, db)
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, studentEmails)
+
+		if err := courseRepo.DeleteAllCourses(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		courses, _ = courseRepo.GetAllCourses(db)
+		assertEquals(t, 0, len(courses))
+		studentEmails, _ = courseRepo.GetStudentEmailsBySectionId(sectionA.ID, db)
+		assertEquals(t, []string{}, studentEmails)
+	})
+}
+
+func TestGetStudentEmailsTaughtInCourse(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		courseRepo := repos.Courses
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com", "test6@gmail.com"}, db)
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+		courseRepo.SaveCourse(&models.Course{Code: "MA101", Name: "Algebra"}, db)
+		courseRepo.SaveCourse(&models.Course{Code: "CS101", Name: "Programming"}, db)
+
+		sectionA, _ := courseRepo.CreateSectionIfNotExists("MA101", "A", db)
+		sectionB, _ := courseRepo.CreateSectionIfNotExists("MA101", "B", db)
+		otherSection, _ := courseRepo.CreateSectionIfNotExists("CS101", "A", db)
+		courseRepo.AssignTeachersToSectionIfNotAssigned(sectionA.ID, []string{"test4@gmail.com", "test5@gmail.com"}, db)
+		courseRepo.AssignTeachersToSectionIfNotAssigned(sectionB.ID, []string{"test4@gmail.com"}, db)
+		courseRepo.AssignTeachersToSectionIfNotAssigned(otherSection.ID, []string{"test5@gmail.com", "test6@gmail.com"}, db)
+		courseRepo.EnrollStudentsIfNotEnrolled(sectionA.ID, []string{"test1@gmail.com", "test2@gmail.com"}, db)
+		courseRepo.EnrollStudentsIfNotEnrolled(sectionB.ID, []string{"test3@gmail.com"}, db)
+		courseRepo.EnrollStudentsIfNotEnrolled(otherSection.ID, []string{"test3@gmail.com"}, db)
+
+		studentEmails, err := courseRepo.GetStudentEmailsTaughtByAtLeast([]string{"test4@gmail.com", "test5@gmail.com"}, "MA101", 2, "", 0, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, studentEmails)
+
+		studentEmails, _ = courseRepo.GetStudentEmailsTaughtByAtLeast([]string{"test4@gmail.com"}, "MA101", 1, "test1@gmail.com", 1, db)
+		assertEquals(t, []string{"test2@gmail.com"}, studentEmails)
+
+		// the student of the other course is not taught by test5 in this one
+		studentEmails, _ = courseRepo.GetStudentEmailsTaughtByAtLeast([]string{"test5@gmail.com", "test6@gmail.com"}, "MA101", 1, "", 0, db)
+		assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, studentEmails)
+
+		studentEmails, _ = courseRepo.GetStudentEmailsTaughtByAtLeast([]string{}, "MA101", 0, "", 0, db)
+		assertEquals(t, []string{}, studentEmails)
+
+		studentEmails, err = courseRepo.GetStudentEmailsTaughtOnlyBy("test4@gmail.com", []string{"test5@gmail.com"}, "MA101", "", 0, db)
+		if err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, []string{"test3@gmail.com"}, studentEmails)
+
+		studentEmails, _ = courseRepo.GetStudentEmailsTaughtOnlyBy("test4@gmail.com", []string{}, "MA101", "test1@gmail.com", 0, db)
+		assertEquals(t, []string{"test2@gmail.com", "test3@gmail.com"}, studentEmails)
+	})
+}
+
+func TestCreateAndRetrieveAssignmentsAndSubmissions(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		assignmentRepo := repos.Assignments
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+
+		dueAt := time.Date(2022, 9, 1, 8, 0, 0, 0, time.UTC)
+		later := &models.Assignment{TeacherEmail: "test4@gmail.com", Title: "essay", DueAt: dueAt.Add(24 * time.Hour), MaxScore: 10, CreatedAt: dueAt}
+		earlier := &models.Assignment{TeacherEmail: "test4@gmail.com", Title: "quiz", DueAt: dueAt, MaxScore: 5, CreatedAt: dueAt}
+		other := &models.Assignment{TeacherEmail: "test5@gmail.com", Title: "lab", DueAt: dueAt, MaxScore: 20, CreatedAt: dueAt}
+		for _, assignment := range []*models.Assignment{later, earlier, other} {
+			if err := assignmentRepo.CreateAssignment(assignment, db); err != nil {
+				t.Errorf("Error throwned: %s", err.Error())
+			}
+		}
+
+		assignment, _ := assignmentRepo.GetAssignmentById(earlier.ID, db)
+		assertEquals(t, "quiz", assignment.Title)
+		assertEquals(t, 5, assignment.MaxScore)
+		assignment, _ = assignmentRepo.GetAssignmentById(other.ID+1, db)
+		assertEquals(t, (*models.Assignment)(nil), assignment)
+
+		assignments, _ := assignmentRepo.GetAssignmentsByTeacherEmail("test4@gmail.com", db)
+		assertEquals(t, []string{"quiz", "essay"}, helpers.Map(assignments, func(assignment *models.Assignment) string { return assignment.Title }))
+
+		submittedAt := dueAt.Add(-time.Hour)
+		assignmentRepo.SaveSubmission(&models.Submission{AssignmentID: earlier.ID, StudentEmail: "test2@gmail.com", Text: "first", SubmittedAt: submittedAt}, db)
+		assignmentRepo.SaveSubmission(&models.Submission{AssignmentID: earlier.ID, StudentEmail: "test1@gmail.com", FileReference: "s3://bucket/quiz.pdf", SubmittedAt: submittedAt}, db)
+		assignmentRepo.SaveSubmission(&models.Submission{AssignmentID: other.ID, StudentEmail: "test1@gmail.com", Text: "lab", SubmittedAt: submittedAt}, db)
+		if err := assignmentRepo.SaveSubmission(&models.Submission{AssignmentID: earlier.ID, StudentEmail: "test2@gmail.com", Text: "second", SubmittedAt: dueAt}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		submissions, _ := assignmentRepo.GetSubmissionsByAssignmentIds([]uint{earlier.ID, later.ID}, db)
+		assertEquals(t, 2, len(submissions))
+		assertEquals(t, "test1@gmail.com", submissions[0].StudentEmail)
+		assertEquals(t, "s3://bucket/quiz.pdf", submissions[0].FileReference)
+		assertEquals(t, "second", submissions[1].Text)
+		assertEquals(t, true, submissions[1].SubmittedAt.Equal(dueAt))
+
+		submissions, _ = assignmentRepo.GetSubmissionsByAssignmentIds([]uint{}, db)
+		assertEquals(t, 0, len(submissions))
+
+		if err := assignmentRepo.DeleteAllAssignments(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assignments, _ = assignmentRepo.GetAssignmentsByTeacherEmail("test4@gmail.com", db)
+		assertEquals(t, 0, len(assignments))
+		submissions, _ = assignmentRepo.GetSubmissionsByAssignmentIds([]uint{earlier.ID, other.ID}, db)
+		assertEquals(t, 0, len(submissions))
+	})
+}
+
+func TestCreateAndRetrieveGradeCategoriesAndGrades(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		gradebookRepo := repos.Gradebooks
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+		repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com"}, db)
+		repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com"}, db)
+
+		teacherEmails, _ := repos.Registrations.GetTeacherEmailsByStudentEmail("test1@gmail.com", db)
+		assertEquals(t, []string{"test4@gmail.com", "test5@gmail.com"}, teacherEmails)
+		teacherEmails, _ = repos.Registrations.GetTeacherEmailsByStudentEmail("test2@gmail.com", db)
+		assertEquals(t, 0, len(teacherEmails))
+
+		homework := &models.GradeCategory{TeacherEmail: "test4@gmail.com", Name: "homework", Weight: 40}
+		exams := &models.GradeCategory{TeacherEmail: "test4@gmail.com", Name: "exams", Weight: 60}
+		for _, category := range []*models.GradeCategory{homework, exams, {TeacherEmail: "test5@gmail.com", Name: "labs", Weight: 100}} {
+			if err := gradebookRepo.SaveCategory(category, db); err != nil {
+				t.Errorf("Error throwned: %s", err.Error())
+			}
+		}
+
+		// saving a category again changes its weight and keeps its id
+		reweighted := &models.GradeCategory{TeacherEmail: "test4@gmail.com", Name: "homework", Weight: 30}
+		if err := gradebookRepo.SaveCategory(reweighted, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		assertEquals(t, homework.ID, reweighted.ID)
+
+		category, _ := gradebookRepo.GetCategory("test4@gmail.com", "homework", db)
+		assertEquals(t, 30.0, category.Weight)
+		category, _ = gradebookRepo.GetCategory("test5@gmail.com", "homework", db)
+		assertEquals(t, (*models.GradeCategory)(nil), category)
+		categories, _ := gradebookRepo.GetCategoriesByTeacherEmail("test4@gmail.com", db)
+		assertEquals(t, []string{"exams", "homework"}, helpers.Map(categories, func(category *models.GradeCategory) string { return category.Name }))
+
+		gradedAt := time.Date(2022, 9, 1, 8, 0, 0, 0, time.UTC)
+		first := &models.Grade{TeacherEmail: "test4@gmail.com", StudentEmail: "test1@gmail.com", CategoryID: homework.ID, Title: "week 1", Score: 8, MaxScore: 10, GradedAt: gradedAt}
+		second := &models.Grade{TeacherEmail: "test4@gmail.com", StudentEmail: "test1@gmail.com", CategoryID: exams.ID, Title: "midterm", Score: 45.5, MaxScore: 50, GradedAt: gradedAt}
+		other := &models.Grade{TeacherEmail: "test4@gmail.com", StudentEmail: "test2@gmail.com", CategoryID: homework.ID, Title: "week 1", Score: 10, MaxScore: 10, GradedAt: gradedAt}
+		for _, grade := range []*models.Grade{first, second, other} {
+			if err := gradebookRepo.CreateGrade(grade, db); err != nil {
+				t.Errorf("Error throwned: %s", err.Error())
+			}
+		}
+
+		grades, _ := gradebookRepo.GetGrades("test4@gmail.com", "test1@gmail.com", db)
+		assertEquals(t, []string{"week 1", "midterm"}, helpers.Map(grades, func(grade *models.Grade) string { return grade.Title }))
+		assertEquals(t, 45.5, grades[1].Score)
+		assertEquals(t, exams.ID, grades[1].CategoryID)
+		grades, _ = gradebookRepo.GetGrades("test5@gmail.com", "test1@gmail.com", db)
+		assertEquals(t, 0, len(grades))
+
+		if err := gradebookRepo.DeleteAllGrades(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		grades, _ = gradebookRepo.GetGrades("test4@gmail.com", "test1@gmail.com", db)
+		assertEquals(t, 0, len(grades))
+		categories, _ = gradebookRepo.GetCategoriesByTeacherEmail("test4@gmail.com", db)
+		assertEquals(t, 0, len(categories))
+	})
+}
+
+func TestSaveAndRetrieveAttendances(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		attendanceRepo := repos.Attendances
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+
+		recordedAt := time.Date(2022, 9, 1, 8, 0, 0, 0, time.UTC)
+		attendance := func(teacherEmail string, studentEmail string, date string, status string) *models.Attendance {
+			return &models.Attendance{TeacherEmail: teacherEmail, StudentEmail: studentEmail, Date: date, Status: status, RecordedAt: recordedAt}
+		}
+		if err := attendanceRepo.SaveAttendances([]*models.Attendance{
+			attendance("test4@gmail.com", "test2@gmail.com", "2022-09-02", models.ATTENDANCE_ABSENT),
+			attendance("test4@gmail.com", "test1@gmail.com", "2022-09-02", models.ATTENDANCE_PRESENT),
+			attendance("test4@gmail.com", "test1@gmail.com", "2022-09-01", models.ATTENDANCE_LATE),
+			attendance("test5@gmail.com", "test1@gmail.com", "2022-09-01", models.ATTENDANCE_EXCUSED),
+		}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		// recording the attendance of a student again on the same date replaces it
+		if err := attendanceRepo.SaveAttendances([]*models.Attendance{attendance("test4@gmail.com", "test2@gmail.com", "2022-09-02", models.ATTENDANCE_EXCUSED)}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		if err := attendanceRepo.SaveAttendances([]*models.Attendance{}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+
+		describe := func(attendances []*models.Attendance) []string {
+			return helpers.Map(attendances, func(attendance *models.Attendance) string {
+				return attendance.StudentEmail + " " + attendance.Date + " " + attendance.Status
+			})
+		}
+		attendances, _ := attendanceRepo.GetAttendancesByDate("test4@gmail.com", "2022-09-02", db)
+		assertEquals(t, []string{"test1@gmail.com 2022-09-02 present", "test2@gmail.com 2022-09-02 excused"}, describe(attendances))
+
+		attendances, _ = attendanceRepo.GetAttendancesBetween("test4@gmail.com", "", "", db)
+		assertEquals(t, []string{"test1@gmail.com 2022-09-01 late", "test1@gmail.com 2022-09-02 present", "test2@gmail.com 2022-09-02 excused"}, describe(attendances))
+		attendances, _ = attendanceRepo.GetAttendancesBetween("test4@gmail.com", "2022-09-02", "", db)
+		assertEquals(t, 2, len(attendances))
+		attendances, _ = attendanceRepo.GetAttendancesBetween("test4@gmail.com", "", "2022-09-01", db)
+		assertEquals(t, []string{"test1@gmail.com 2022-09-01 late"}, describe(attendances))
+
+		attendances, _ = attendanceRepo.GetStudentAttendancesBetween("test4@gmail.com", "test1@gmail.com", "2022-09-01", "2022-09-02", db)
+		assertEquals(t, []string{"test1@gmail.com 2022-09-01 late", "test1@gmail.com 2022-09-02 present"}, describe(attendances))
+		attendances, _ = attendanceRepo.GetStudentAttendancesBetween("test5@gmail.com", "test2@gmail.com", "", "", db)
+		assertEquals(t, 0, len(attendances))
+
+		if err := attendanceRepo.DeleteAllAttendances(db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		attendances, _ = attendanceRepo.GetAttendancesBetween("test5@gmail.com", "", "", db)
+		assertEquals(t, 0, len(attendances))
+	})
+}
+
+func TestUpdateAndDeleteProfiles(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		repos.Teachers.CreateTeachersIfNotExist([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com"}, db)
+
+		profile := &models.Profile{Name: "Jon Tan", GradeLevel: "7", Phone: "+65 6123 4567"}
+		profile.SetMetadata(map[string]string{"house": "red"})
+		if err := repos.Students.UpdateStudentProfile("test1@gmail.com", profile, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		student, _ := repos.Students.GetStudentByEmail("test1@gmail.com", db)
+		assertEquals(t, &models.Student{Email: "test1@gmail.com", Profile: *profile}, student)
+		assertEquals(t, map[string]string{"house": "red"}, student.Profile.MetadataMap())
+
+		// an empty profile clears the fields of the previous one
+		if err := repos.Teachers.UpdateTeacherProfile("test4@gmail.com", &models.Profile{Name: "Ken Lim"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		if err := repos.Teachers.UpdateTeacherProfile("test4@gmail.com", &models.Profile{Homeroom: "7A"}, db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		teacher, _ := repos.Teachers.GetTeacherByEmail("test4@gmail.com", db)
+		assertEquals(t, &models.Teacher{Email: "test4@gmail.com", Profile: models.Profile{Homeroom: "7A"}}, teacher)
+
+		repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test4@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, db)
+		repos.Registrations.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists("test5@gmail.com", []string{"test1@gmail.com"}, db)
+		repos.Attendances.SaveAttendances([]*models.Attendance{
+			{TeacherEmail: "test4@gmail.com", StudentEmail: "test1@gmail.com", Date: "2022-09-01", Status: models.ATTENDANCE_PRESENT},
+			{TeacherEmail: "test5@gmail.com", StudentEmail: "test2@gmail.com", Date: "2022-09-01", Status: models.ATTENDANCE_ABSENT},
+		}, db)
+
+		// deleting a student deletes their registrations and attendances, the others are kept
+		if err := repos.Students.DeleteStudent("test1@gmail.com", db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		student, _ = repos.Students.GetStudentByEmail("test1@gmail.com", db)
+		assertEquals(t, (*models.Student)(nil), student)
+		relationships, _ := repos.Registrations.GetRelationshipsByTeacherEmails([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+		assertEquals(t, []string{"test2@gmail.com"}, helpers.Map(relationships, func(relationship *models.RegisterRelationship) string {
+			return relationship.StudentEmail
+		}))
+		attendances, _ := repos.Attendances.GetAttendancesBetween("test4@gmail.com", "", "", db)
+		assertEquals(t, 0, len(attendances))
+
+		// deleting a teacher deletes what they recorded, their students are kept
+		if err := repos.Teachers.DeleteTeacher("test5@gmail.com", db); err != nil {
+			t.Errorf("Error throwned: %s", err.Error())
+		}
+		teacher, _ = repos.Teachers.GetTeacherByEmail("test5@gmail.com", db)
+		assertEquals(t, (*models.Teacher)(nil), teacher)
+		attendances, _ = repos.Attendances.GetAttendancesBetween("test5@gmail.com", "", "", db)
+		assertEquals(t, 0, len(attendances))
+		students, _ := repos.Students.GetStudentsByEmails([]string{"test2@gmail.com"}, db)
+		assertEquals(t, 1, len(students))
+	})
+}
+
+func TestMigrateBackfillsSuspensions(t *testing.T) {
+	connection := SetUpTestDb(&database.Credentials{Driver: database.SQLITE, Name: database.SQLITE_IN_MEMORY})
+	if connection.GetDb() == nil {
+		t.Fatal("could not open in-memory sqlite database")
 	}
+	db := connection.GetDb()
+	repos := repositories.NewSqlRepositories()
 
-	relationships, err := relationshipRepo.GetRelationshipsByTeacherEmails([]string{"test4@gmail.com", "test5@gmail.com"}, db)
+	// students flagged as suspended before suspensions were recorded have none
+	repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, db)
+	repos.Students.UpdateStudentSuspensionStatus("test1@gmail.com", true, db)
+	repos.Students.UpdateStudentSuspensionStatus("test2@gmail.com", true, db)
+	repos.Suspensions.CreateSuspension(&models.StudentSuspension{StudentEmail: "test2@gmail.com", Reason: "late", SuspendedAt: time.Now()}, db)
 
-	if err != nil {
-		t.Errorf("Error throwned: %s", err.Error())
+	for i := 0; i < 2; i++ {
+		if err := models.Migrate(db); err != nil {
+			t.Fatalf("Error throwned: %s", err.Error())
+		}
 	}
 
-	assertEquals(t, 0, len(relationships))
+	suspendedStudentEmails, _ := repos.Suspensions.GetActivelySuspendedStudentEmails([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, time.Now(), db)
+	assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, suspendedStudentEmails)
+	suspensions, _ := repos.Suspensions.GetSuspensionsByStudentEmail("test1@gmail.com", db)
+	assertEquals(t, 1, len(suspensions))
+	suspensions, _ = repos.Suspensions.GetSuspensionsByStudentEmail("test2@gmail.com", db)
+	assertEquals(t, 1, len(suspensions))
 }
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"learning-management-system/database"
+	"learning-management-system/repositories"
+	"learning-management-system/schedulers"
+	"learning-management-system/transaction_managers"
+	"testing"
+	"time"
+)
+
+func TestExpiredSuspensionsAreLiftedByScheduler(t *testing.T) {
+	repos := repositories.NewInMemoryRepositories()
+	transactionManager := transaction_managers.NewTransactionManager(repos)
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com", "test2@gmail.com"}, connection)
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, connection)
+
+	until := time.Now().Add(50 * time.Millisecond)
+	if err := transactionManager.SuspendStudent("test1@gmail.com", "test@gmail.com", "late", &until, connection); err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+
+	_, recipients, _ := transactionManager.RetrieveStudentRecipients("test@gmail.com", "hello", nil, connection)
+	assertEquals(t, []string{"test2@gmail.com"}, recipients)
+
+	scheduler := schedulers.NewSuspensionExpiryScheduler(connection, transactionManager, 10*time.Millisecond)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	// recipients only depend on the end of the suspension, not on the scheduler having lifted it
+	time.Sleep(time.Until(until))
+	_, recipients, _ = transactionManager.RetrieveStudentRecipients("test@gmail.com", "hello", nil, connection)
+	assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, recipients)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		suspensions, _ := transactionManager.RetrieveStudentSuspensions("test1@gmail.com", connection)
+		if suspensions[0].LiftedAt != nil {
+			assertEquals(t, true, suspensions[0].LiftedAt.Equal(until))
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("suspension was not lifted by the scheduler")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	student, _ := repos.Students.GetStudentByEmail("test1@gmail.com", nil)
+	assertEquals(t, false, student.IsSuspended)
+}
+
+func TestLiftExpiredSuspensionsKeepsOtherActiveSuspensions(t *testing.T) {
+	repos := repositories.NewInMemoryRepositories()
+	transactionManager := transaction_managers.NewTransactionManager(repos)
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com", "test2@gmail.com"}, connection)
+
+	until := time.Now().Add(time.Hour)
+	transactionManager.SuspendStudent("test1@gmail.com", "", "", &until, connection)
+	transactionManager.SuspendStudent("test2@gmail.com", "", "", nil, connection)
+
+	if err := transactionManager.LiftExpiredSuspensions(until.Add(-time.Minute), connection); err != nil {
+		t.Errorf("Error throwned: %s", err.Error())
+	}
+	student, _ := repos.Students.GetStudentByEmail("test1@gmail.com", nil)
+	assertEquals(t, true, student.IsSuspended)
+
+	if err := transactionManager.LiftExpiredSuspensions(until, connection); err != nil {
+		t.Errorf("Error throwned: %s", err.Error())
+	}
+	student, _ = repos.Students.GetStudentByEmail("test1@gmail.com", nil)
+	assertEquals(t, false, student.IsSuspended)
+	student, _ = repos.Students.GetStudentByEmail("test2@gmail.com", nil)
+	assertEquals(t, true, student.IsSuspended)
+}
@@ -1,11 +1,17 @@
 package tests
 
 import (
+	"fmt"
+	"learning-management-system/helpers"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCase1(t *testing.T) {
-	SetUpTestDb()
+	SetUpTestServer()
 	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com", "test4@gmail.com", "test5@gmail.com"]}`, "/api/populatestudents", 204, "", t)
 	testPost(`{"teachers": ["test@gmail.com", "nani@gmail.com"]}`, "/api/populateteachers", 204, "", t)
 	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, "/api/register", 204, "", t)
@@ -23,7 +29,7 @@ func TestCase1(t *testing.T) {
 }
 
 func TestCase2(t *testing.T) {
-	SetUpTestDb()
+	SetUpTestServer()
 	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com"]}`, "/api/populatestudents", 204, "", t)
 	testPost(`{"teachers": ["test@gmail.com", "test7@gmail.com"]}`, "/api/populateteachers", 204, "", t)
 	testGet("/api/commonstudents?teacher=test%40gmail.com&teacher=test7%40gmail.com", 200, `{"students":[]}`, t)
@@ -42,7 +48,7 @@ func TestCase2(t *testing.T) {
 }
 
 func TestCase3(t *testing.T) {
-	SetUpTestDb()
+	SetUpTestServer()
 	testPost(`{"student":"test3@gmail.com"}`, "/api/suspend", 400, `{"message":"Student with email test3@gmail.com does not exist in the database"}`, t)
 	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, "/api/register", 400, `{"message":"Teacher with email test@gmail.com does not exist in the database"}`, t)
 	testPost(`{"teachers": ["test@gmail.com", "nani@gmail.com"]}`, "/api/populateteachers", 204, "", t)
@@ -61,3 +67,309 @@ func TestCase3(t *testing.T) {
 	testDelete(t)
 	testGet("/api/commonstudents", 400, `{"message":"The required field teacher is not supplied"}`, t)
 }
+
+func TestCase4(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"student":"test1@gmail.com", "suspended_by":"test@gmail.com", "reason":"disrupting class"}`, "/api/suspend", 204, "", t)
+	testPost(`{"student":"test1@gmail.com", "reason":"suspending twice"}`, "/api/suspend", 204, "", t)
+	testPost(`{"student":"test1@gmail.com", "suspended_by":"testgmail.com"}`, "/api/suspend", 400, `{"message":"The email address testgmail.com has an invalid format"}`, t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"hello"}`, "/api/retrievefornotifications", 200, `{"recipients":["test2@gmail.com"]}`, t)
+	testPost(`{}`, "/api/unsuspend", 400, `{"message":"The required field student is not supplied"}`, t)
+	testPost(`{"student":"test3@gmail.com"}`, "/api/unsuspend", 400, `{"message":"Student with email test3@gmail.com does not exist in the database"}`, t)
+	testPost(`{"student":"test1@gmail.com"}`, "/api/unsuspend", 204, "", t)
+	testPost(`{"student":"test1@gmail.com"}`, "/api/unsuspend", 204, "", t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"hello"}`, "/api/retrievefornotifications", 200, `{"recipients":["test1@gmail.com","test2@gmail.com"]}`, t)
+	testPost(`{"student":"test1@gmail.com", "reason":"late homework"}`, "/api/suspend", 204, "", t)
+
+	response := &types.RetrieveStudentSuspensionsResponse{}
+	testGetJson("/api/students/test1%40gmail.com/suspensions", 200, response, t)
+	assertEquals(t, "test1@gmail.com", response.StudentEmail)
+	assertEquals(t, 2, len(response.Suspensions))
+	assertEquals(t, "test@gmail.com", response.Suspensions[0].SuspendedBy)
+	assertEquals(t, "disrupting class", response.Suspensions[0].Reason)
+	assertEquals(t, true, response.Suspensions[0].LiftedAt != nil)
+	assertEquals(t, "late homework", response.Suspensions[1].Reason)
+	assertEquals(t, true, response.Suspensions[1].LiftedAt == nil)
+
+	testGet("/api/students/test2%40gmail.com/suspensions", 200, `{"student":"test2@gmail.com","suspensions":[]}`, t)
+	testGet("/api/students/test3%40gmail.com/suspensions", 400, `{"message":"Student with email test3@gmail.com does not exist in the database"}`, t)
+	testGet("/api/students/test3gmail.com/suspensions", 400, `{"message":"The email address test3gmail.com has an invalid format"}`, t)
+}
+
+func TestCase5(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"student":"test1@gmail.com", "until":"2020-01-01T00:00:00Z"}`, "/api/suspend", 400, `{"message":"The field until must be in the future"}`, t)
+	testPost(`{"student":"test1@gmail.com", "until":"2999-01-01T00:00:00Z"}`, "/api/suspend", 204, "", t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"hello @test1@gmail.com"}`, "/api/retrievefornotifications", 200, `{"recipients":["test2@gmail.com"]}`, t)
+
+	response := &types.RetrieveStudentSuspensionsResponse{}
+	testGetJson("/api/students/test1%40gmail.com/suspensions", 200, response, t)
+	assertEquals(t, 1, len(response.Suspensions))
+	assertEquals(t, true, response.Suspensions[0].Until.Equal(time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// suspending the student again only ever extends their suspension instead of recording another one
+	testPost(`{"student":"test1@gmail.com", "until":"2998-01-01T00:00:00Z"}`, "/api/suspend", 204, "", t)
+	testGetJson("/api/students/test1%40gmail.com/suspensions", 200, response, t)
+	assertEquals(t, 1, len(response.Suspensions))
+	assertEquals(t, true, response.Suspensions[0].Until.Equal(time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)))
+	testPost(`{"student":"test1@gmail.com", "until":"3000-01-01T00:00:00Z"}`, "/api/suspend", 204, "", t)
+	testGetJson("/api/students/test1%40gmail.com/suspensions", 200, response, t)
+	assertEquals(t, 1, len(response.Suspensions))
+	assertEquals(t, true, response.Suspensions[0].Until.Equal(time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)))
+	testPost(`{"student":"test1@gmail.com"}`, "/api/suspend", 204, "", t)
+	testGetJson("/api/students/test1%40gmail.com/suspensions", 200, response, t)
+	assertEquals(t, 1, len(response.Suspensions))
+	assertEquals(t, true, response.Suspensions[0].Until == nil)
+	testPost(`{"student":"test1@gmail.com", "until":"2999-01-01T00:00:00Z"}`, "/api/suspend", 204, "", t)
+	testGetJson("/api/students/test1%40gmail.com/suspensions", 200, response, t)
+	assertEquals(t, 1, len(response.Suspensions))
+	assertEquals(t, true, response.Suspensions[0].Until == nil)
+}
+
+func TestCase6(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com", "test7@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "test7@gmail.com", "students":["test1@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com"}`, "/api/unregister", 400, `{"message":"The required field students is not supplied"}`, t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1gmail.com"]}`, "/api/unregister", 400, `{"message":"The email address test1gmail.com has an invalid format"}`, t)
+	testPost(`{"teacher": "test8@gmail.com", "students":["test1@gmail.com"]}`, "/api/unregister", 400, `{"message":"Teacher with email test8@gmail.com does not exist in the database"}`, t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test9@gmail.com"]}`, "/api/unregister", 400, `{"message":"Student with email test9@gmail.com does not exist in the database"}`, t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test3@gmail.com","test1@gmail.com"]}`, "/api/unregister", 200, `{"unregistered":["test1@gmail.com"],"not_registered":["test3@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=test%40gmail.com", 200, `{"students":["test2@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=test7%40gmail.com", 200, `{"students":["test1@gmail.com"]}`, t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com"]}`, "/api/unregister", 200, `{"unregistered":[],"not_registered":["test1@gmail.com"]}`, t)
+}
+
+func TestCase7(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"student":"test2@gmail.com"}`, "/api/suspend", 204, "", t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"first"}`, "/api/retrievefornotifications", 200, `{"recipients":["test1@gmail.com"]}`, t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"second @test3@gmail.com"}`, "/api/retrievefornotifications", 200, `{"recipients":["test1@gmail.com","test3@gmail.com"]}`, t)
+
+	response := &types.RetrieveStudentNotificationsResponse{}
+	testGetJson("/api/students/test1%40gmail.com/notifications", 200, response, t)
+	assertEquals(t, int64(2), response.Total)
+	assertEquals(t, int64(2), response.Unread)
+	assertEquals(t, 2, len(response.Notifications))
+	assertEquals(t, "second @test3@gmail.com", response.Notifications[0].Message)
+	assertEquals(t, "test@gmail.com", response.Notifications[0].TeacherEmail)
+	assertEquals(t, "first", response.Notifications[1].Message)
+	assertEquals(t, false, response.Notifications[1].Read)
+
+	firstNotificationId := response.Notifications[1].NotificationId
+	testPost(`{}`, fmt.Sprintf("/api/students/test1%%40gmail.com/notifications/%d/read", firstNotificationId), 204, "", t)
+	testPost(`{}`, fmt.Sprintf("/api/students/test3%%40gmail.com/notifications/%d/read", firstNotificationId), 400,
+		fmt.Sprintf(`{"message":"Notification with id %d does not exist in the inbox of student test3@gmail.com"}`, firstNotificationId), t)
+
+	response = &types.RetrieveStudentNotificationsResponse{}
+	testGetJson("/api/students/test1%40gmail.com/notifications?limit=1", 200, response, t)
+	assertEquals(t, 1, len(response.Notifications))
+	assertEquals(t, false, response.Notifications[0].Read)
+	nextCursor := response.NextCursor
+
+	response = &types.RetrieveStudentNotificationsResponse{}
+	testGetJson("/api/students/test1%40gmail.com/notifications?limit=1&cursor="+nextCursor, 200, response, t)
+	assertEquals(t, int64(1), response.Unread)
+	assertEquals(t, 1, len(response.Notifications))
+	assertEquals(t, true, response.Notifications[0].Read)
+	assertEquals(t, "", response.NextCursor)
+
+	response = &types.RetrieveStudentNotificationsResponse{}
+	testGetJson("/api/students/test1%40gmail.com/notifications?unread=true", 200, response, t)
+	assertEquals(t, 1, len(response.Notifications))
+	assertEquals(t, "second @test3@gmail.com", response.Notifications[0].Message)
+
+	testGet("/api/students/test2%40gmail.com/notifications", 200, `{"student":"test2@gmail.com","notifications":[],"total":0,"unread":0}`, t)
+	testGet("/api/students/test1%40gmail.com/notifications?limit=101", 400, `{"message":"The field limit must be between 1 and 100"}`, t)
+	// a cursor of a list sorted by email does not page the inbox
+	testProblem("GET", "/api/students/test1%40gmail.com/notifications?cursor=eyJhIjoidGVzdDFAZ21haWwuY29tIn0", "", "", 400,
+		problems.INVALID_CURSOR, "The cursor eyJhIjoidGVzdDFAZ21haWwuY29tIn0 is invalid", t)
+	testGet("/api/students/test9%40gmail.com/notifications", 400, `{"message":"Student with email test9@gmail.com does not exist in the database"}`, t)
+}
+
+func TestCase8(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test2@gmail.com","test1@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher":"test@gmail.com", "notification":"hello"}`, "/api/retrievefornotifications", 200, `{"recipients":["test1@gmail.com","test2@gmail.com"]}`, t)
+
+	testGet("/api/notifications/1/deliveries", 200, `{"id":1,"deliveries":[`+
+		`{"student":"test1@gmail.com","status":"pending","attempts":0,"last_error":"","delivered_at":null},`+
+		`{"student":"test2@gmail.com","status":"pending","attempts":0,"last_error":"","delivered_at":null}]}`, t)
+	testGet("/api/notifications/2/deliveries", 400, `{"message":"Notification with id 2 does not exist in the database"}`, t)
+}
+
+func TestCase9(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+
+	testPost(`{"url":"ftp://hooks.school.com","events":["students.registered"],"secret":"s"}`, "/api/webhooks", 400,
+		`{"message":"The webhook url ftp://hooks.school.com must be an absolute http or https url"}`, t)
+	testPost(`{"url":"http://hooks.school.com","events":["student.expelled"],"secret":"s"}`, "/api/webhooks", 400,
+		`{"message":"The event student.expelled is not one of [students.registered student.suspended notification.sent]"}`, t)
+	testPost(`{"url":"http://hooks.school.com","events":["students.registered"]}`, "/api/webhooks", 400, `{"message":"The required field secret is not supplied"}`, t)
+
+	subscription := &types.WebhookSubscriptionResponse{}
+	testPostJson(`{"url":"http://hooks.school.com/lms","events":["students.registered","students.registered"],"secret":"s"}`, "/api/webhooks", 201, subscription, t)
+	assertEquals(t, "http://hooks.school.com/lms", subscription.Url)
+	assertEquals(t, []string{"students.registered"}, subscription.EventTypes)
+
+	subscriptions := &types.RetrieveWebhookSubscriptionsResponse{}
+	testGetJson("/api/webhooks", 200, subscriptions, t)
+	assertEquals(t, []*types.WebhookSubscriptionResponse{subscription}, subscriptions.Subscriptions)
+
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test2@gmail.com"]}`, "/api/register", 204, "", t)
+
+	deliveriesPath := fmt.Sprintf("/api/webhooks/%d/deliveries", subscription.SubscriptionId)
+	deliveries := &types.RetrieveWebhookDeliveriesResponse{}
+	testGetJson(deliveriesPath+"?limit=1", 200, deliveries, t)
+	assertEquals(t, int64(2), deliveries.Total)
+	assertEquals(t, 1, len(deliveries.Deliveries))
+	assertEquals(t, "students.registered", deliveries.Deliveries[0].EventType)
+	assertEquals(t, "pending", deliveries.Deliveries[0].Status)
+	assertEquals(t, true, deliveries.Deliveries[0].NextAttemptAt != nil)
+	newestDeliveryId, nextCursor := deliveries.Deliveries[0].DeliveryId, deliveries.NextCursor
+
+	deliveries = &types.RetrieveWebhookDeliveriesResponse{}
+	testGetJson(deliveriesPath+"?limit=1&cursor="+nextCursor, 200, deliveries, t)
+	assertEquals(t, 1, len(deliveries.Deliveries))
+	assertEquals(t, true, deliveries.Deliveries[0].DeliveryId < newestDeliveryId)
+	assertEquals(t, "", deliveries.NextCursor)
+
+	testDeletePath(fmt.Sprintf("/api/webhooks/%d", subscription.SubscriptionId), 204, "", t)
+	testDeletePath(fmt.Sprintf("/api/webhooks/%d", subscription.SubscriptionId), 400,
+		fmt.Sprintf(`{"message":"Webhook with id %d does not exist in the database"}`, subscription.SubscriptionId), t)
+	testGet("/api/webhooks", 200, `{"webhooks":[]}`, t)
+}
+
+func TestCase10(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com", "test7@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test3@gmail.com","test1@gmail.com","test2@gmail.com"]}`, "/api/register", 204, "", t)
+
+	students := &types.RetrieveRegisteredStudentsResponse{}
+	testGetJson("/api/commonstudents?teacher=test%40gmail.com&limit=2", 200, students, t)
+	assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, students.StudentEmails)
+	assertEquals(t, true, students.NextCursor != "")
+	testGet("/api/commonstudents?teacher=test%40gmail.com&limit=2&cursor="+students.NextCursor, 200, `{"students":["test3@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=test%40gmail.com&limit=0", 200, `{"students":["test1@gmail.com","test2@gmail.com","test3@gmail.com"]}`, t)
+	testGet("/api/commonstudents?teacher=test%40gmail.com&limit=101", 400, `{"message":"The field limit must be between 1 and 100"}`, t)
+	testGet("/api/commonstudents?teacher=test%40gmail.com&cursor=abc", 400, `{"message":"The cursor abc is invalid"}`, t)
+
+	recipients := &types.RetrieveCommonStudentsResponse{}
+	testPostJson(`{"teacher":"test@gmail.com", "notification":"hello"}`, "/api/retrievefornotifications?limit=2", 200, recipients, t)
+	assertEquals(t, []string{"test1@gmail.com", "test2@gmail.com"}, recipients.StudentEmails)
+	testGet("/api/retrievefornotifications?limit=2&cursor="+recipients.NextCursor, 200, `{"recipients":["test3@gmail.com"]}`, t)
+	testGet("/api/retrievefornotifications", 400, `{"message":"The required field cursor is not supplied"}`, t)
+	testGet("/api/retrievefornotifications?cursor="+students.NextCursor, 400, fmt.Sprintf(`{"message":"The cursor %s is invalid"}`, students.NextCursor), t)
+
+	// without a limit every recipient and common student is returned, even more than a page
+	manyStudentEmails := make([]string, 0)
+	for i := 0; i < helpers.DEFAULT_PAGE_LIMIT+5; i++ {
+		manyStudentEmails = append(manyStudentEmails, fmt.Sprintf("many%02d@gmail.com", i))
+	}
+	manyStudents := `["` + strings.Join(manyStudentEmails, `","`) + `"]`
+	testPost(`{"students": `+manyStudents+`}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teacher": "test7@gmail.com", "students": `+manyStudents+`}`, "/api/register", 204, "", t)
+	testPost(`{"teacher":"test7@gmail.com", "notification":"hello"}`, "/api/retrievefornotifications", 200, `{"recipients":`+manyStudents+`}`, t)
+	testGet("/api/commonstudents?teacher=test7%40gmail.com", 200, `{"students":`+manyStudents+`}`, t)
+	testGet("/api/v1/commonstudents?teacher=test7%40gmail.com", 200, `{"students":`+manyStudents+`}`, t)
+
+	// v2 always pages, a page of DEFAULT_PAGE_LIMIT students at a time unless a limit is given
+	testGetJson("/api/v2/students?teacher=test7%40gmail.com", 200, students, t)
+	assertEquals(t, manyStudentEmails[:helpers.DEFAULT_PAGE_LIMIT], students.StudentEmails)
+	assertEquals(t, true, students.NextCursor != "")
+	testGet("/api/v2/students?teacher=test7%40gmail.com&cursor="+students.NextCursor, 200,
+		`{"students":["`+strings.Join(manyStudentEmails[helpers.DEFAULT_PAGE_LIMIT:], `","`)+`"]}`, t)
+}
+
+func TestCase11(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com", "test2@gmail.com", "test3@gmail.com", "test4@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+	testPost(`{"teachers": ["test@gmail.com", "test7@gmail.com", "test8@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+	testPost(`{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com","test3@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "test7@gmail.com", "students":["test2@gmail.com","test3@gmail.com"]}`, "/api/register", 204, "", t)
+	testPost(`{"teacher": "test8@gmail.com", "students":["test3@gmail.com","test4@gmail.com"]}`, "/api/register", 204, "", t)
+
+	teachers := "teacher=test%40gmail.com&teacher=test7%40gmail.com&teacher=test8%40gmail.com"
+	testGet("/api/commonstudents?"+teachers, 200, `{"students":["test3@gmail.com"]}`, t)
+	testGet("/api/commonstudents?mode=all&"+teachers, 200, `{"students":["test3@gmail.com"]}`, t)
+	testGet("/api/commonstudents?mode=any&"+teachers, 200, `{"students":["test1@gmail.com","test2@gmail.com","test3@gmail.com","test4@gmail.com"]}`, t)
+	testGet("/api/commonstudents?mode=only&"+teachers, 200, `{"students":["test1@gmail.com"]}`, t)
+	testGet("/api/commonstudents?mode=only&teacher=test7%40gmail.com&teacher=test8%40gmail.com", 200, `{"students":["test2@gmail.com"]}`, t)
+	testGet("/api/commonstudents?mode=atleast=2&"+teachers, 200, `{"students":["test2@gmail.com","test3@gmail.com"]}`, t)
+	testGet("/api/commonstudents?mode=any&limit=3&"+teachers, 200, `{"students":["test1@gmail.com","test2@gmail.com","test3@gmail.com"],"next_cursor":"eyJhIjoidGVzdDNAZ21haWwuY29tIn0"}`, t)
+	testGet("/api/commonstudents?mode=atleast=4&"+teachers, 400, `{"message":"The mode atleast=4 must count between 1 and 3 teachers"}`, t)
+	testGet("/api/commonstudents?mode=none&"+teachers, 400, `{"message":"The mode none is not one of [all any only atleast=k]"}`, t)
+}
+
+func TestCase12(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"students": ["test1@gmail.com"]}`, "/api/populatestudents", 204, "", t)
+
+	problem := testProblem("POST", "/api/register", `{"teacher": "tes8gmail.com", "students":["test7gmail.com","test1@gmail.com","test6gmail.com"]}`, "", 400,
+		problems.INVALID_EMAIL, "The email addresses test7gmail.com, test6gmail.com have an invalid format", t)
+	assertEquals(t, []*types.ProblemDetailResponse{
+		{Code: problems.INVALID_EMAIL, Field: "students", Emails: []string{"test7gmail.com", "test6gmail.com"}, Detail: "The email addresses test7gmail.com, test6gmail.com have an invalid format"},
+		{Code: problems.INVALID_EMAIL, Field: "teacher", Emails: []string{"tes8gmail.com"}, Detail: "The email address tes8gmail.com has an invalid format"},
+	}, problem.Errors)
+	// v1 keeps the message of the first invalid email, v2 only describes the problems
+	assertEquals(t, "The email address test7gmail.com has an invalid format", problem.Message)
+	problem = testProblem("POST", "/api/v2/teachers/tes8gmail.com/students", `{"students":["test7gmail.com","test1@gmail.com","test6gmail.com"]}`, "", 400,
+		problems.INVALID_EMAIL, "The email addresses test7gmail.com, test6gmail.com have an invalid format", t)
+	assertEquals(t, "", problem.Message)
+	testPostWithContentTypeHeaderHeader(`{"students": ["test1@gmail.com"]}`, "/api/v2/teachers/test@gmail.com/students", 400,
+		`{"type":"about:blank","title":"Bad Request","status":400,"code":"INVALID_CONTENT_TYPE","detail":"Content-Type header must be application/json",`+
+			`"errors":[{"code":"INVALID_CONTENT_TYPE","detail":"Content-Type header must be application/json"}]}`, "text/html; charset=UTF-8", t)
+	testPostWithContentTypeHeaderHeader(`{"teacher": "test@gmail.com", "students": ["test1@gmail.com"]}`, "/api/v1/register", 400,
+		`{"type":"about:blank","title":"Bad Request","status":400,"code":"INVALID_CONTENT_TYPE","detail":"Content-Type header must be application/json",`+
+			`"errors":[{"code":"INVALID_CONTENT_TYPE","detail":"Content-Type header must be application/json"}],"message":"Content-Type header must be application/json"}`,
+		"text/html; charset=UTF-8", t)
+
+	problem = testProblem("POST", "/api/register", `{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com","test3@gmail.com"]}`, "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email test@gmail.com does not exist in the database", t)
+	assertEquals(t, []*types.ProblemDetailResponse{
+		{Code: problems.TEACHER_NOT_FOUND, Emails: []string{"test@gmail.com"}, Detail: "Teacher with email test@gmail.com does not exist in the database"},
+		{Code: problems.STUDENT_NOT_FOUND, Emails: []string{"test2@gmail.com", "test3@gmail.com"}, Detail: "Students with emails test2@gmail.com, test3@gmail.com do not exist in the database"},
+	}, problem.Errors)
+
+	problem = testProblem("POST", "/api/webhooks", `{}`, "", 400, problems.MISSING_FIELD, "The required field url is not supplied", t)
+	assertEquals(t, []string{"url", "events", "secret"}, helpers.Map(problem.Errors, func(detail *types.ProblemDetailResponse) string { return detail.Field }))
+
+	problem = testProblem("GET", "/api/commonstudents?teacher=test1gmail.com&mode=none&limit=101", "", "", 400,
+		problems.INVALID_EMAIL, "The email address test1gmail.com has an invalid format", t)
+	assertEquals(t, []string{problems.INVALID_EMAIL, problems.INVALID_MODE, problems.INVALID_LIMIT}, helpers.Map(problem.Errors, func(detail *types.ProblemDetailResponse) string { return detail.Code }))
+}
+
+func TestCase13(t *testing.T) {
+	SetUpTestServer()
+	testPost(`{"teachers": ["test@gmail.com"]}`, "/api/populateteachers", 204, "", t)
+
+	french := map[string]string{"Accept-Language": "fr-CA, en;q=0.8"}
+	problem := testProblemWithHeaders("POST", "/api/register", `{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2gmail.com"]}`, french, 400,
+		problems.INVALID_EMAIL, "L'adresse e-mail test2gmail.com a un format invalide", t)
+	assertEquals(t, "students", problem.Errors[0].Field)
+	testProblemWithHeaders("POST", "/api/register", `{"teacher": "test@gmail.com", "students":["test1@gmail.com","test2@gmail.com"]}`, french, 400,
+		problems.STUDENT_NOT_FOUND, "Les élèves avec les e-mails test1@gmail.com, test2@gmail.com n'existent pas dans la base de données", t)
+	testProblemWithHeaders("POST", "/api/register", `{"teacher": "test@gmail.com"}`, french, 400,
+		problems.MISSING_FIELD, "Le champ obligatoire students n'est pas fourni", t)
+
+	// unsupported languages fall back to English
+	testProblemWithHeaders("POST", "/api/register", `{"teacher": "test@gmail.com"}`, map[string]string{"Accept-Language": "de"}, 400,
+		problems.MISSING_FIELD, "The required field students is not supplied", t)
+}
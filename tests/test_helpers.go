@@ -2,63 +2,117 @@ package tests
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"io/ioutil"
+	"learning-management-system/auth"
+	"learning-management-system/config"
 	"learning-management-system/controllers"
 	"learning-management-system/database"
 	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/repositories"
 	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 )
 
-// change accordingly
+// testConfig configures the sql database the repository tests run against, override it with LMS_TEST_*
+// environment variables or a file named by LMS_TEST_CONFIG_FILE
+var testConfig = loadTestConfig()
 
-const TEST_USERNAME string = "root"
-const TEST_PASSWORD string = ""
-const TEST_HOST string = "localhost"
-const TEST_NAME string = "test_lms_db"
-const TEST_PORT string = "3306"
-const TEST_DEBUG bool = false
+func loadTestConfig() *config.Config {
+	defaults := config.Default()
+	defaults.Database.Name = "test_lms_db"
 
-const SERVER_PORT = "8090"
+	testConfig, err := config.Load(config.TEST_ENV_PREFIX, defaults)
+	if err != nil {
+		panic(err)
+	}
+	return testConfig
+}
 
-func SetUpTestDb() (*database.Connection, *controllers.Controller) {
+// testServerUrl is the base url of the server started by the latest call to SetUpTestServer
+var testServerUrl string
 
-	connection := database.NewConnection(&database.Credentials{
-		Username: TEST_USERNAME,
-		Password: TEST_PASSWORD,
-		Host:     TEST_HOST,
-		Name:     TEST_NAME,
-		Port:     TEST_PORT,
-		Debug:    TEST_DEBUG,
-	})
+// SetUpTestDb connects to the given test database and clears it, the returned connection holds a nil db
+// when the database is not reachable
+func SetUpTestDb(credentials *database.Credentials) *database.Connection {
+
+	connection := database.NewConnection(credentials)
 
-	connection.GetDb().AutoMigrate(&models.Teacher{}, &models.Student{}, &models.RegisterRelationship{})
-	router := gin.Default()
-	controller := controllers.NewController(connection)
+	if connection.GetDb() == nil {
+		return connection
+	}
+
+	models.Migrate(connection.GetDb())
 
-	transactionManager := transaction_managers.NewTransactionManager()
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewSqlRepositories())
 	transactionManager.ClearDatabase(connection)
 
-	router.POST("/api/register", controller.RegisterStudentsToTeacher)
-	router.GET("/api/commonstudents", controller.RetrieveCommonStudents)
-	router.POST("/api/suspend", controller.SuspendStudent)
-	router.POST("/api/retrievefornotifications", controller.RetrieveStudentRecipients)
-	router.DELETE("/api/clear", controller.ClearDatabase)
-	router.POST("/api/populateteachers", controller.PopulateTeachers)
-	router.POST("/api/populatestudents", controller.PopulateStudents)
-	go router.Run(":" + SERVER_PORT)
+	return connection
+}
 
-	return connection, controller
+// SetUpTestServer starts a server backed by fresh in-memory repositories, so system tests need no database
+func SetUpTestServer() *controllers.Controller {
+	return SetUpTestServerWithAuth(nil)
+}
+
+// SetUpTestServerWithAuth starts a test server whose routes require tokens validated by the authenticator, like the
+// server does when auth is enabled. A nil authenticator leaves every route open.
+func SetUpTestServerWithAuth(authenticator *auth.Authenticator) *controllers.Controller {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	controller := controllers.NewController(&database.Connection{}, transactionManager)
+
+	var authenticate gin.HandlerFunc
+	if authenticator != nil {
+		authenticate = authenticator.Middleware()
+	}
+	controller.RegisterRoutes(router, authenticate, true)
+
+	testServerUrl = httptest.NewServer(router).URL
+
+	return controller
+}
+
+// forEachRepositoryBackend runs the test against the in-memory repositories, the sql repositories on an
+// in-memory sqlite database and, when it is reachable, the sql repositories on the configured test database
+func forEachRepositoryBackend(t *testing.T, test func(t *testing.T, repos *repositories.Repositories, db *gorm.DB)) {
+	t.Run("in-memory", func(t *testing.T) {
+		test(t, repositories.NewInMemoryRepositories(), nil)
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		connection := SetUpTestDb(&database.Credentials{
+			Driver: database.SQLITE,
+			Name:   database.SQLITE_IN_MEMORY,
+			Debug:  testConfig.Database.Debug,
+		})
+		if connection.GetDb() == nil {
+			t.Fatal("could not open in-memory sqlite database")
+		}
+		test(t, repositories.NewSqlRepositories(), connection.GetDb())
+	})
+	t.Run(testConfig.Database.Driver, func(t *testing.T) {
+		connection := SetUpTestDb(testConfig.Database.Credentials())
+		if connection.GetDb() == nil {
+			t.Skipf("%s test database is not reachable", testConfig.Database.Driver)
+		}
+		test(t, repositories.NewSqlRepositories(), connection.GetDb())
+	})
 }
 
 func testPost(jsonString string, relativePath string, expectedStatusCode int, expectedBody string, t *testing.T) {
 	var jsonData = []byte(jsonString)
 	responseBody := bytes.NewBuffer(jsonData)
-	resp, err := http.Post("http://"+TEST_HOST+":"+SERVER_PORT+relativePath, "application/json", responseBody)
+	resp, err := http.Post(testServerUrl+relativePath, "application/json", responseBody)
 
 	if err != nil {
 		t.Errorf(err.Error())
@@ -75,7 +129,7 @@ func testPost(jsonString string, relativePath string, expectedStatusCode int, ex
 		t.Errorf(err.Error())
 	}
 
-	if string(body) != expectedBody {
+	if !bodyMatches(body, expectedBody) {
 		t.Errorf("wrong response body expected: " + expectedBody + " got: " + string(body))
 	}
 
@@ -84,7 +138,7 @@ func testPost(jsonString string, relativePath string, expectedStatusCode int, ex
 func testPostWithContentTypeHeaderHeader(jsonString string, relativePath string, expectedStatusCode int, expectedBody string, contentType string, t *testing.T) {
 	var jsonData = []byte(jsonString)
 	responseBody := bytes.NewBuffer(jsonData)
-	resp, err := http.Post("http://"+TEST_HOST+":"+SERVER_PORT+relativePath, contentType, responseBody)
+	resp, err := http.Post(testServerUrl+relativePath, contentType, responseBody)
 
 	if err != nil {
 		t.Errorf(err.Error())
@@ -101,14 +155,14 @@ func testPostWithContentTypeHeaderHeader(jsonString string, relativePath string,
 		t.Errorf(err.Error())
 	}
 
-	if string(body) != expectedBody {
+	if !bodyMatches(body, expectedBody) {
 		t.Errorf("wrong response body expected: " + expectedBody + " got: " + string(body))
 	}
 
 }
 
 func testGet(relativePathWithParams string, expectedStatusCode int, expectedBody string, t *testing.T) {
-	resp, err := http.Get("http://" + TEST_HOST + ":" + SERVER_PORT + relativePathWithParams)
+	resp, err := http.Get(testServerUrl + relativePathWithParams)
 
 	if err != nil {
 		t.Errorf(err.Error())
@@ -125,15 +179,195 @@ func testGet(relativePathWithParams string, expectedStatusCode int, expectedBody
 		t.Errorf(err.Error())
 	}
 
-	if string(body) != expectedBody {
+	if !bodyMatches(body, expectedBody) {
 
 		t.Errorf("wrong response body expected: " + expectedBody + " got: " + string(body))
 
 	}
 }
 
+func testGetJson[T any](relativePathWithParams string, expectedStatusCode int, response *T, t *testing.T) {
+	resp, err := http.Get(testServerUrl + relativePathWithParams)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Errorf("could not decode response body: " + err.Error())
+	}
+}
+
+func testPostJson[T any](jsonString string, relativePath string, expectedStatusCode int, response *T, t *testing.T) {
+	resp, err := http.Post(testServerUrl+relativePath, "application/json", bytes.NewBufferString(jsonString))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Errorf("could not decode response body: " + err.Error())
+	}
+}
+
+func testRequestJson[T any](method string, relativePath string, jsonString string, expectedStatusCode int, response *T, t *testing.T) {
+	req, err := http.NewRequest(method, testServerUrl+relativePath, bytes.NewBufferString(jsonString))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code expected: %d got: %d", expectedStatusCode, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Errorf("could not decode response body: " + err.Error())
+	}
+}
+
+// testPostCsv uploads the csv content as the file field of a multipart form
+func testPostCsv[T any](csvContent string, relativePath string, expectedStatusCode int, response *T, t *testing.T) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	file, _ := form.CreateFormFile("file", "roster.csv")
+	file.Write([]byte(csvContent))
+	form.Close()
+
+	resp, err := http.Post(testServerUrl+relativePath, form.FormDataContentType(), body)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Errorf("could not decode response body: " + err.Error())
+	}
+}
+
+// testRequestWithToken sends a request with the token as bearer token, an empty token sends no Authorization header
+func testRequestWithToken(method string, relativePath string, jsonString string, token string, expectedStatusCode int, expectedBody string, t *testing.T) {
+	req, err := http.NewRequest(method, testServerUrl+relativePath, bytes.NewBufferString(jsonString))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code expected: %d got: %d", expectedStatusCode, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bodyMatches(body, expectedBody) {
+		t.Errorf("wrong response body expected: " + expectedBody + " got: " + string(body))
+	}
+}
+
+// testRequestJsonWithToken sends a request with the token as bearer token and decodes the json response
+func testRequestJsonWithToken[T any](method string, relativePath string, jsonString string, token string, expectedStatusCode int, response *T, t *testing.T) {
+	req, err := http.NewRequest(method, testServerUrl+relativePath, bytes.NewBufferString(jsonString))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code expected: %d got: %d", expectedStatusCode, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Errorf("could not decode response body: " + err.Error())
+	}
+}
+
+// testProblem sends a json request and checks that it is answered with a problem+json response whose first problem has
+// the expected code and detail, the token is left out when empty
+func testProblem(method string, relativePath string, jsonString string, token string, expectedStatusCode int, expectedCode string, expectedDetail string, t *testing.T) *types.ProblemResponse {
+	headers := map[string]string{}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return testProblemWithHeaders(method, relativePath, jsonString, headers, expectedStatusCode, expectedCode, expectedDetail, t)
+}
+
+func testProblemWithHeaders(method string, relativePath string, jsonString string, headers map[string]string, expectedStatusCode int, expectedCode string, expectedDetail string, t *testing.T) *types.ProblemResponse {
+	req, err := http.NewRequest(method, testServerUrl+relativePath, bytes.NewBufferString(jsonString))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code expected: %d got: %d", expectedStatusCode, resp.StatusCode)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != problems.CONTENT_TYPE {
+		t.Errorf("wrong content type expected: " + problems.CONTENT_TYPE + " got: " + contentType)
+	}
+
+	problem := &types.ProblemResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(problem); err != nil {
+		t.Fatalf("could not decode response body: " + err.Error())
+	}
+
+	if problem.Status != expectedStatusCode || problem.Code != expectedCode || problem.Detail != expectedDetail {
+		t.Errorf("wrong problem expected: %d %s %s got: %d %s %s", expectedStatusCode, expectedCode, expectedDetail, problem.Status, problem.Code, problem.Detail)
+	}
+	return problem
+}
+
 func testDelete(t *testing.T) {
-	req, err := http.NewRequest("DELETE", "http://"+TEST_HOST+":"+SERVER_PORT+"/api/clear", nil)
+	req, err := http.NewRequest("DELETE", testServerUrl+"/api/clear", nil)
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -159,6 +393,52 @@ func testDelete(t *testing.T) {
 	}
 }
 
+func testDeletePath(relativePath string, expectedStatusCode int, expectedBody string, t *testing.T) {
+	req, err := http.NewRequest("DELETE", testServerUrl+relativePath, nil)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		t.Errorf("wrong response code")
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bodyMatches(body, expectedBody) {
+		t.Errorf("wrong response body expected: " + expectedBody + " got: " + string(body))
+	}
+}
+
+// legacyErrorResponse is the error body of v1 from before errors were problems
+type legacyErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// bodyMatches tells whether the body is the expected one. A problem of v1 also matches the legacy error body holding
+// its message, so the assertions written against the legacy bodies keep checking what v1 clients read.
+func bodyMatches(body []byte, expectedBody string) bool {
+	if string(body) == expectedBody {
+		return true
+	}
+
+	legacy, problem := &legacyErrorResponse{}, &types.ProblemResponse{}
+	if json.Unmarshal([]byte(expectedBody), legacy) != nil || json.Unmarshal(body, problem) != nil {
+		return false
+	}
+	legacyBody, _ := json.Marshal(legacy)
+	return string(legacyBody) == expectedBody && problem.Status != 0 && problem.Message == legacy.Message
+}
+
 func assertEquals[T any](t *testing.T, expected T, actual T) {
 	// we use reflect.DeepEqual to compare the two values
 	if !reflect.DeepEqual(expected, actual) {
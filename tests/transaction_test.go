@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/repositories"
+	"learning-management-system/transaction_managers"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithTxCommits(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		transactionManager := transaction_managers.NewTransactionManager(repos)
+		connection := database.WrapDb(db)
+
+		err := transactionManager.WithTx(connection, func(tx *database.Connection) error {
+			if err := transactionManager.PopulateTeachers([]string{"test@gmail.com"}, tx); err != nil {
+				return err
+			}
+			if err := transactionManager.PopulateStudents([]string{"test1@gmail.com"}, tx); err != nil {
+				return err
+			}
+			if userError, dbError := transactionManager.ValidateStudentsExists([]string{"test1@gmail.com"}, tx); userError != nil || dbError != nil {
+				t.Errorf("the student should be visible within the transaction")
+			}
+			return transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com"}, tx)
+		})
+		if err != nil {
+			t.Fatalf("Error throwned: %s", err.Error())
+		}
+
+		relationships, _ := repos.Registrations.GetRelationshipsByTeacherEmail("test@gmail.com", db)
+		assertEquals(t, 1, len(relationships))
+	})
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		transactionManager := transaction_managers.NewTransactionManager(repos)
+		connection := database.WrapDb(db)
+		transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+		transactionManager.PopulateStudents([]string{"test1@gmail.com"}, connection)
+
+		rejected := errors.New("rejected")
+		err := transactionManager.WithTx(connection, func(tx *database.Connection) error {
+			if err := transactionManager.PopulateStudents([]string{"test2@gmail.com"}, tx); err != nil {
+				return err
+			}
+			// registering runs a transaction of its own, which is nested in the unit of work
+			if err := transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com"}, tx); err != nil {
+				return err
+			}
+			return rejected
+		})
+		if err != rejected {
+			t.Errorf("the error of the unit of work should be returned as is, got %v", err)
+		}
+
+		student, _ := repos.Students.GetStudentByEmail("test2@gmail.com", db)
+		assertEquals(t, true, student == nil)
+		relationships, _ := repos.Registrations.GetRelationshipsByTeacherEmail("test@gmail.com", db)
+		assertEquals(t, 0, len(relationships))
+	})
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		transactionManager := transaction_managers.NewTransactionManager(repos)
+		connection := database.WrapDb(db)
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("the panic should be propagated")
+				}
+			}()
+			transactionManager.WithTx(connection, func(tx *database.Connection) error {
+				transactionManager.PopulateStudents([]string{"test1@gmail.com"}, tx)
+				panic("unit of work failed")
+			})
+		}()
+
+		student, _ := repos.Students.GetStudentByEmail("test1@gmail.com", db)
+		assertEquals(t, true, student == nil)
+	})
+}
+
+func TestInMemoryTransactionKeepsConcurrentWrites(t *testing.T) {
+	repos := repositories.NewInMemoryRepositories()
+	var written sync.WaitGroup
+
+	rejected := errors.New("rejected")
+	err := repos.Transactor.Transaction(nil, func(tx *gorm.DB) error {
+		repos.Students.CreateStudentsIfNotExist([]string{"test1@gmail.com"}, tx)
+
+		// a write of another goroutine waits for the transaction, so that rolling it back cannot wipe the write out
+		written.Add(1)
+		go func() {
+			defer written.Done()
+			repos.Teachers.CreateTeachersIfNotExist([]string{"test@gmail.com"}, nil)
+		}()
+		time.Sleep(20 * time.Millisecond)
+		return rejected
+	})
+	if err != rejected {
+		t.Errorf("the error of the unit of work should be returned as is, got %v", err)
+	}
+	written.Wait()
+
+	teacher, _ := repos.Teachers.GetTeacherByEmail("test@gmail.com", nil)
+	assertEquals(t, true, teacher != nil)
+	student, _ := repos.Students.GetStudentByEmail("test1@gmail.com", nil)
+	assertEquals(t, true, student == nil)
+}
+
+// unreadableStudentRepository fails to read students by email, like a database that went away
+type unreadableStudentRepository struct {
+	repositories.StudentRepository
+}
+
+func (*unreadableStudentRepository) GetStudentsByEmails(studentEmails []string, db *gorm.DB) ([]*models.Student, error) {
+	return nil, errors.New("connection lost")
+}
+
+func TestRecipientsFailWhenMentionedStudentsCannotBeRead(t *testing.T) {
+	repos := repositories.NewInMemoryRepositories()
+	repos.Students = &unreadableStudentRepository{repos.Students}
+	transactionManager := transaction_managers.NewTransactionManager(repos)
+	connection := &database.Connection{}
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+
+	_, recipientEmails, err := transactionManager.RetrieveStudentRecipients("test@gmail.com", "hello @test1@gmail.com", []string{"test1@gmail.com"}, connection)
+	if err == nil {
+		t.Errorf("the error reading the mentioned students should be returned, got the recipients %v", recipientEmails)
+	}
+}
+
+func TestExportRosterTakesSuspendedFromActiveSuspensions(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, repos *repositories.Repositories, db *gorm.DB) {
+		transactionManager := transaction_managers.NewTransactionManager(repos)
+		connection := database.WrapDb(db)
+		transactionManager.PopulateStudents([]string{"test1@gmail.com", "test2@gmail.com", "test3@gmail.com"}, connection)
+
+		// test1 only has the legacy flag, test3 only a suspension that is over
+		repos.Students.UpdateStudentSuspensionStatus("test1@gmail.com", true, db)
+		repos.Suspensions.CreateSuspension(&models.StudentSuspension{StudentEmail: "test2@gmail.com", SuspendedAt: time.Now()}, db)
+		until := time.Now().Add(-time.Hour)
+		repos.Suspensions.CreateSuspension(&models.StudentSuspension{StudentEmail: "test3@gmail.com", SuspendedAt: until.Add(-time.Hour), Until: &until}, db)
+
+		var export bytes.Buffer
+		writer, _ := helpers.NewRosterWriter(helpers.EXPORT_CSV, &export)
+		if err := transactionManager.ExportRoster(writer, connection); err != nil {
+			t.Fatalf("the roster should be exported, got %v", err)
+		}
+		assertEquals(t, "record,teacher,student,suspended\n"+
+			"student,,test1@gmail.com,false\n"+
+			"student,,test2@gmail.com,true\n"+
+			"student,,test3@gmail.com,false\n", export.String())
+	})
+}
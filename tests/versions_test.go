@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"learning-management-system/helpers"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// TestV1Parity checks that /api/v1 answers like the unversioned routes, which are only marked as deprecated
+func TestV1Parity(t *testing.T) {
+	SetUpTestServer()
+
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		testDeletePath(prefix+"/clear", 204, "", t)
+		testRequestWithToken("POST", prefix+"/populateteachers", `{"teachers": ["teacherken@gmail.com"]}`, "", 204, "", t)
+		testRequestWithToken("POST", prefix+"/populatestudents", `{"students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "", 204, "", t)
+		testRequestWithToken("POST", prefix+"/register", `{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "", 204, "", t)
+
+		testGet(prefix+"/commonstudents?teacher=teacherken%40gmail.com", 200, `{"students":["studenthon@gmail.com","studentjon@gmail.com"]}`, t)
+		testRequestWithToken("POST", prefix+"/retrievefornotifications", `{"teacher": "teacherken@gmail.com", "notification": "Hello students!"}`, "", 200,
+			`{"recipients":["studenthon@gmail.com","studentjon@gmail.com"]}`, t)
+		testProblem("POST", prefix+"/register", `{"teacher": "teacherken@gmail.com", "students": ["studentbob@gmail.com"]}`, "", 400,
+			problems.STUDENT_NOT_FOUND, "Student with email studentbob@gmail.com does not exist in the database", t)
+	}
+
+	resp, err := http.Get(testServerUrl + "/api/commonstudents?teacher=teacherken%40gmail.com")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	resp.Body.Close()
+	assertEquals(t, "true", resp.Header.Get("Deprecation"))
+	assertEquals(t, `</api/v1>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	resp, err = http.Get(testServerUrl + "/api/v1/commonstudents?teacher=teacherken%40gmail.com")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	resp.Body.Close()
+	assertEquals(t, "", resp.Header.Get("Deprecation"))
+}
+
+func TestV2Registrations(t *testing.T) {
+	SetUpTestServer()
+
+	testDeletePath("/api/v2/database", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/teachers", `{"teachers": ["teacherken@gmail.com"]}`, "", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/students", `{"students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "", 204, "", t)
+
+	testRequestWithToken("POST", "/api/v2/teachers/teacherken@gmail.com/students", `{"students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`, "", 204, "", t)
+	testGet("/api/v2/students?teacher=teacherken%40gmail.com", 200, `{"students":["studenthon@gmail.com","studentjon@gmail.com"]}`, t)
+
+	testRequestWithToken("DELETE", "/api/v2/teachers/teacherken@gmail.com/students", `{"students": ["studentjon@gmail.com"]}`, "", 200,
+		`{"unregistered":["studentjon@gmail.com"],"not_registered":[]}`, t)
+	testGet("/api/v2/students?teacher=teacherken%40gmail.com", 200, `{"students":["studenthon@gmail.com"]}`, t)
+
+	// the problems of the teacher in the path point at the email parameter
+	problem := testProblem("POST", "/api/v2/teachers/teacherken/students", `{"students": ["studentjon@gmail.com"]}`, "", 400,
+		problems.INVALID_EMAIL, "The email address teacherken has an invalid format", t)
+	assertEquals(t, "email", problem.Errors[0].Field)
+	testProblem("POST", "/api/v2/teachers/teacherbob@gmail.com/students", `{"students": ["studentjon@gmail.com"]}`, "", 400,
+		problems.TEACHER_NOT_FOUND, "Teacher with email teacherbob@gmail.com does not exist in the database", t)
+	testProblem("POST", "/api/v2/teachers/teacherken@gmail.com/students", `{}`, "", 400,
+		problems.MISSING_FIELD, "The required field students is not supplied", t)
+}
+
+func TestV2Suspensions(t *testing.T) {
+	SetUpTestServer()
+
+	testDeletePath("/api/v2/database", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/students", `{"students": ["studentjon@gmail.com"]}`, "", 204, "", t)
+
+	testRequestWithToken("POST", "/api/v2/students/studentjon@gmail.com/suspension", `{"reason": "late homework"}`, "", 204, "", t)
+	suspensions := &types.RetrieveStudentSuspensionsResponse{}
+	testGetJson("/api/v2/students/studentjon@gmail.com/suspensions", 200, suspensions, t)
+	assertEquals(t, 1, len(suspensions.Suspensions))
+	assertEquals(t, "late homework", suspensions.Suspensions[0].Reason)
+
+	testDeletePath("/api/v2/students/studentjon@gmail.com/suspension", 204, "", t)
+	testGetJson("/api/v2/students/studentjon@gmail.com/suspensions", 200, suspensions, t)
+	assertEquals(t, true, suspensions.Suspensions[0].LiftedAt != nil)
+
+	testProblem("DELETE", "/api/v2/students/studentbob@gmail.com/suspension", "", "", 400,
+		problems.STUDENT_NOT_FOUND, "Student with email studentbob@gmail.com does not exist in the database", t)
+}
+
+func TestV2Notifications(t *testing.T) {
+	SetUpTestServer()
+
+	testDeletePath("/api/v2/database", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/teachers", `{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`, "", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/students", `{"students": ["studentamy@gmail.com", "studentbob@gmail.com", "studentcat@gmail.com"]}`, "", 204, "", t)
+	testRequestWithToken("POST", "/api/v2/teachers/teacherken@gmail.com/students", `{"students": ["studentamy@gmail.com", "studentbob@gmail.com", "studentcat@gmail.com"]}`, "", 204, "", t)
+
+	sent := &types.RetrieveNotificationRecipientsResponse{}
+	testPostJson(`{"notification": "Hello students!"}`, "/api/v2/teachers/teacherken@gmail.com/notifications?limit=2", 201, sent, t)
+	assertEquals(t, []string{"studentamy@gmail.com", "studentbob@gmail.com"}, sent.StudentEmails)
+	assertEquals(t, true, sent.NotificationId != 0 && sent.NextCursor != "")
+
+	recipientsPath := "/api/v2/notifications/" + strconv.FormatUint(uint64(sent.NotificationId), 10) + "/recipients"
+
+	// the recipients are paged from the first one, or from the cursor returned when the notification was sent
+	page := &types.RetrieveNotificationRecipientsResponse{}
+	testGetJson(recipientsPath, 200, page, t)
+	assertEquals(t, []string{"studentamy@gmail.com", "studentbob@gmail.com", "studentcat@gmail.com"}, page.StudentEmails)
+	assertEquals(t, sent.NotificationId, page.NotificationId)
+
+	page = &types.RetrieveNotificationRecipientsResponse{}
+	testGetJson(recipientsPath+"?limit=2&cursor="+sent.NextCursor, 200, page, t)
+	assertEquals(t, []string{"studentcat@gmail.com"}, page.StudentEmails)
+	assertEquals(t, "", page.NextCursor)
+
+	otherCursor := helpers.EncodeCursor(&helpers.Cursor{NotificationId: sent.NotificationId + 1, After: "studentamy@gmail.com"})
+	testProblem("GET", recipientsPath+"?cursor="+otherCursor, "", "", 400,
+		problems.INVALID_CURSOR, "The cursor "+otherCursor+" is invalid", t)
+	testProblem("GET", "/api/v2/notifications/999/recipients", "", "", 400,
+		problems.NOTIFICATION_NOT_FOUND, "Notification with id 999 does not exist in the database", t)
+}
@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"learning-management-system/database"
+	"learning-management-system/models"
+	"learning-management-system/repositories"
+	"learning-management-system/schedulers"
+	"learning-management-system/transaction_managers"
+	"learning-management-system/types"
+	"learning-management-system/webhooks"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver records the webhook requests it receives and answers them with the configured status
+type webhookReceiver struct {
+	mutex    sync.Mutex
+	status   int
+	requests []*receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func startWebhookReceiver(t *testing.T, status int) (*webhookReceiver, string) {
+	receiver := &webhookReceiver{status: status}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		receiver.mutex.Lock()
+		defer receiver.mutex.Unlock()
+		receiver.requests = append(receiver.requests, &receivedWebhook{header: request.Header, body: body})
+		writer.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(server.Close)
+	return receiver, server.URL
+}
+
+func (receiver *webhookReceiver) received() []*receivedWebhook {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	return append([]*receivedWebhook{}, receiver.requests...)
+}
+
+func (receiver *webhookReceiver) respondWith(status int) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	receiver.status = status
+}
+
+func TestHttpSenderSignsPayload(t *testing.T) {
+	receiver, url := startWebhookReceiver(t, http.StatusOK)
+	payload := []byte(`{"event":"students.registered"}`)
+
+	statusCode, err := webhooks.NewHttpSender(time.Second).Send(url, "secret", models.EVENT_STUDENTS_REGISTERED, 7, payload)
+	if err != nil {
+		t.Fatalf("Error throwned: %s", err.Error())
+	}
+	assertEquals(t, http.StatusOK, statusCode)
+
+	requests := receiver.received()
+	assertEquals(t, 1, len(requests))
+	assertEquals(t, payload, requests[0].body)
+	assertEquals(t, "students.registered", requests[0].header.Get(webhooks.EVENT_HEADER))
+	assertEquals(t, "7", requests[0].header.Get(webhooks.DELIVERY_HEADER))
+	assertEquals(t, true, webhooks.Verify("secret", requests[0].body, requests[0].header.Get(webhooks.SIGNATURE_HEADER)))
+	assertEquals(t, false, webhooks.Verify("other secret", requests[0].body, requests[0].header.Get(webhooks.SIGNATURE_HEADER)))
+}
+
+func TestHttpSenderReportsErrorStatus(t *testing.T) {
+	_, url := startWebhookReceiver(t, http.StatusServiceUnavailable)
+
+	statusCode, err := webhooks.NewHttpSender(time.Second).Send(url, "secret", models.EVENT_STUDENTS_REGISTERED, 1, []byte(`{}`))
+	assertEquals(t, http.StatusServiceUnavailable, statusCode)
+	assertEquals(t, "webhook responded with status 503", err.Error())
+}
+
+func TestWebhookEventsAreQueuedForSubscribedEvents(t *testing.T) {
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com", "test2@gmail.com"}, connection)
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+
+	registrations, _ := transactionManager.CreateWebhookSubscription("http://localhost/registrations", []string{models.EVENT_STUDENTS_REGISTERED}, "s1", connection)
+	everything, _ := transactionManager.CreateWebhookSubscription("http://localhost/everything", models.WebhookEventTypes, "s2", connection)
+
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com", "test2@gmail.com"}, connection)
+	// registering students that are already registered creates no relationship, so no event is queued
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com"}, connection)
+	transactionManager.SuspendStudent("test2@gmail.com", "test@gmail.com", "late", nil, connection)
+	// suspending a suspended student until the same time changes nothing, so no event is queued
+	transactionManager.SuspendStudent("test2@gmail.com", "test@gmail.com", "late", nil, connection)
+	transactionManager.RetrieveStudentRecipients("test@gmail.com", "hello", nil, connection)
+
+	deliveries, _, total, _, _ := transactionManager.RetrieveWebhookDeliveries(registrations.ID, 0, 10, connection)
+	assertEquals(t, int64(1), total)
+	assertEquals(t, models.EVENT_STUDENTS_REGISTERED, deliveries[0].EventType)
+
+	event := &types.WebhookEvent{Data: &types.StudentsRegisteredEvent{}}
+	json.Unmarshal([]byte(deliveries[0].Payload), event)
+	assertEquals(t, models.EVENT_STUDENTS_REGISTERED, event.Event)
+	assertEquals(t, &types.StudentsRegisteredEvent{TeacherEmail: "test@gmail.com", StudentEmails: []string{"test1@gmail.com", "test2@gmail.com"}}, event.Data.(*types.StudentsRegisteredEvent))
+
+	deliveries, _, total, _, _ = transactionManager.RetrieveWebhookDeliveries(everything.ID, 0, 10, connection)
+	assertEquals(t, int64(3), total)
+	assertEquals(t, models.EVENT_NOTIFICATION_SENT, deliveries[0].EventType)
+	assertEquals(t, models.EVENT_STUDENT_SUSPENDED, deliveries[1].EventType)
+
+	notificationEvent := &types.WebhookEvent{Data: &types.NotificationSentEvent{}}
+	json.Unmarshal([]byte(deliveries[0].Payload), notificationEvent)
+	assertEquals(t, &types.NotificationSentEvent{NotificationId: 1, TeacherEmail: "test@gmail.com", Message: "hello", RecipientEmails: []string{"test1@gmail.com"}}, notificationEvent.Data.(*types.NotificationSentEvent))
+}
+
+func TestResuspendingOnlyExtendsTheSuspension(t *testing.T) {
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com"}, connection)
+	subscription, _ := transactionManager.CreateWebhookSubscription("http://localhost/suspensions", []string{models.EVENT_STUDENT_SUSPENDED}, "s1", connection)
+
+	firstUntil := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	earlierUntil := firstUntil.Add(-24 * time.Hour)
+	laterUntil := firstUntil.Add(24 * time.Hour)
+	transactionManager.SuspendStudent("test1@gmail.com", "test@gmail.com", "late", &firstUntil, connection)
+	// an earlier end would shorten the suspension, so it changes nothing and queues no event
+	transactionManager.SuspendStudent("test1@gmail.com", "admin@gmail.com", "rude", &earlierUntil, connection)
+	transactionManager.SuspendStudent("test1@gmail.com", "admin@gmail.com", "rude", &laterUntil, connection)
+
+	suspensions, _ := transactionManager.RetrieveStudentSuspensions("test1@gmail.com", connection)
+	assertEquals(t, 1, len(suspensions))
+	assertEquals(t, true, suspensions[0].Until.Equal(laterUntil))
+	assertEquals(t, "late", suspensions[0].Reason)
+
+	deliveries, _, total, _, _ := transactionManager.RetrieveWebhookDeliveries(subscription.ID, 0, 10, connection)
+	assertEquals(t, int64(2), total)
+	event := &types.WebhookEvent{Data: &types.StudentSuspendedEvent{}}
+	json.Unmarshal([]byte(deliveries[0].Payload), event)
+	data := event.Data.(*types.StudentSuspendedEvent)
+	assertEquals(t, []string{"test@gmail.com", "late"}, []string{data.SuspendedBy, data.Reason})
+	assertEquals(t, true, data.Until.Equal(laterUntil))
+}
+
+func TestFailedWebhooksAreRetriedUntilMaxAttempts(t *testing.T) {
+	receiver, url := startWebhookReceiver(t, http.StatusInternalServerError)
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com", "test2@gmail.com"}, connection)
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+	subscription, _ := transactionManager.CreateWebhookSubscription(url, []string{models.EVENT_STUDENTS_REGISTERED}, "secret", connection)
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test1@gmail.com"}, connection)
+	transactionManager.RegisterStudentsToTeacher("test@gmail.com", []string{"test2@gmail.com"}, connection)
+
+	sender := webhooks.NewHttpSender(time.Second)
+	now := time.Now()
+	backoff := time.Minute
+
+	transactionManager.DeliverPendingWebhooks(sender, 2, backoff, now, connection)
+	assertEquals(t, 2, len(receiver.received()))
+
+	// the first delivery succeeds on its retry while the second one fails for the last time
+	receiver.respondWith(http.StatusOK)
+	transactionManager.DeliverPendingWebhooks(sender, 2, backoff, now.Add(backoff-time.Second), connection)
+	assertEquals(t, 2, len(receiver.received()))
+	transactionManager.DeliverPendingWebhooks(&failingSecondDeliverySender{sender: sender}, 2, backoff, now.Add(backoff), connection)
+	transactionManager.DeliverPendingWebhooks(sender, 2, backoff, now.Add(time.Hour), connection)
+
+	deliveries, _, _, _, _ := transactionManager.RetrieveWebhookDeliveries(subscription.ID, 0, 10, connection)
+	assertEquals(t, models.DELIVERY_FAILED, deliveries[0].Status)
+	assertEquals(t, 2, deliveries[0].Attempts)
+	assertEquals(t, "webhook responded with status 502", deliveries[0].LastError)
+	assertEquals(t, models.DELIVERY_SENT, deliveries[1].Status)
+	assertEquals(t, 2, deliveries[1].Attempts)
+	assertEquals(t, 200, deliveries[1].ResponseStatus)
+	assertEquals(t, 3, len(receiver.received()))
+}
+
+// failingSecondDeliverySender answers the delivery with id 2 with a bad gateway and sends the others
+type failingSecondDeliverySender struct {
+	sender webhooks.Sender
+}
+
+func (sender *failingSecondDeliverySender) Send(url string, secret string, eventType string, deliveryId uint, payload []byte) (int, error) {
+	if deliveryId == 2 {
+		return http.StatusBadGateway, errors.New("webhook responded with status 502")
+	}
+	return sender.sender.Send(url, secret, eventType, deliveryId, payload)
+}
+
+func TestQueuedWebhooksAreDeliveredByScheduler(t *testing.T) {
+	receiver, url := startWebhookReceiver(t, http.StatusNoContent)
+	transactionManager := transaction_managers.NewTransactionManager(repositories.NewInMemoryRepositories())
+	connection := &database.Connection{}
+	transactionManager.PopulateStudents([]string{"test1@gmail.com"}, connection)
+	transactionManager.PopulateTeachers([]string{"test@gmail.com"}, connection)
+	transactionManager.CreateWebhookSubscription(url, []string{models.EVENT_STUDENT_SUSPENDED}, "secret", connection)
+
+	// the interval is long enough that only the queued signal can trigger the delivery
+	scheduler := schedulers.NewWebhookDeliveryScheduler(connection, transactionManager, webhooks.NewHttpSender(time.Second), time.Hour, 3, time.Minute)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	transactionManager.SuspendStudent("test1@gmail.com", "test@gmail.com", "late", nil, connection)
+
+	deadline := time.Now().Add(time.Second)
+	for len(receiver.received()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook was not delivered by the scheduler")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	request := receiver.received()[0]
+	assertEquals(t, models.EVENT_STUDENT_SUSPENDED, request.header.Get(webhooks.EVENT_HEADER))
+	assertEquals(t, true, webhooks.Verify("secret", request.body, request.header.Get(webhooks.SIGNATURE_HEADER)))
+}
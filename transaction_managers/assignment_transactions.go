@@ -0,0 +1,178 @@
+package transaction_managers
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"sort"
+	"time"
+)
+
+// AssignmentWork is the work of a student on an assignment, the submission is nil when the student has not submitted
+// anything yet
+type AssignmentWork struct {
+	Assignment   *models.Assignment
+	StudentEmail string
+	Submission   *models.Submission
+	Status       string
+}
+
+func (transactionManager *TransactionManager) CreateAssignment(assignment *models.Assignment, connection *database.Connection) error {
+	db := connection.GetDb()
+	assignment.CreatedAt = time.Now()
+	return transactionManager.assignmentRepo.CreateAssignment(assignment, db)
+}
+
+func (transactionManager *TransactionManager) RetrieveAssignment(assignmentId uint, connection *database.Connection) (assignment *models.Assignment, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if assignment, dbError = transactionManager.assignmentRepo.GetAssignmentById(assignmentId, db); dbError != nil {
+		return nil, nil, dbError
+	} else if assignment == nil {
+		return nil, generateNonExistentAssignmentError(assignmentId), nil
+	}
+	return assignment, nil, nil
+}
+
+func (transactionManager *TransactionManager) RetrieveTeacherAssignments(teacherEmail string, connection *database.Connection) ([]*models.Assignment, error) {
+	db := connection.GetDb()
+	return transactionManager.assignmentRepo.GetAssignmentsByTeacherEmail(teacherEmail, db)
+}
+
+// SubmitAssignment stores the work of the student, replacing what they submitted before. Only the students registered
+// to the teacher of the assignment can submit it, a submission after the due date is accepted and reported as late.
+func (transactionManager *TransactionManager) SubmitAssignment(assignmentId uint, studentEmail string, text string, fileReference string, connection *database.Connection) (work *AssignmentWork, userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		assignment, err := transactionManager.assignmentRepo.GetAssignmentById(assignmentId, tx)
+		if err != nil {
+			return err
+		} else if assignment == nil {
+			userError = generateNonExistentAssignmentError(assignmentId)
+			return nil
+		}
+
+		studentEmails, err := transactionManager.registeredStudentEmails(assignment.TeacherEmail, tx)
+		if err != nil {
+			return err
+		} else if !studentEmails[studentEmail] {
+			userError = problems.Newf(problems.ASSIGNMENT_NOT_FOUND, "student", assignmentId, studentEmail).WithEmails(studentEmail)
+			return nil
+		}
+
+		submission := &models.Submission{
+			AssignmentID:  assignmentId,
+			StudentEmail:  studentEmail,
+			Text:          text,
+			FileReference: fileReference,
+			SubmittedAt:   time.Now(),
+		}
+		if err := transactionManager.assignmentRepo.SaveSubmission(submission, tx); err != nil {
+			return err
+		}
+
+		work = &AssignmentWork{
+			Assignment:   assignment,
+			StudentEmail: studentEmail,
+			Submission:   submission,
+			Status:       assignment.WorkStatus(submission, submission.SubmittedAt),
+		}
+		return nil
+	})
+	return work, userError, dbError
+}
+
+// RetrieveAssignmentWork returns the work of every student on the assignment whose status is the given one, or of
+// every student when the status is empty
+func (transactionManager *TransactionManager) RetrieveAssignmentWork(assignmentId uint, status string, connection *database.Connection) (works []*AssignmentWork, userError error, dbError error) {
+	db := connection.GetDb()
+
+	assignment, userError, dbError := transactionManager.RetrieveAssignment(assignmentId, connection)
+	if userError != nil || dbError != nil {
+		return nil, userError, dbError
+	}
+
+	works, dbError = transactionManager.workOn([]*models.Assignment{assignment}, assignment.TeacherEmail, status, db)
+	return works, nil, dbError
+}
+
+// RetrieveTeacherWork returns the work of the students on every assignment of the teacher whose status is the given
+// one, or of every student when the status is empty
+func (transactionManager *TransactionManager) RetrieveTeacherWork(teacherEmail string, status string, connection *database.Connection) ([]*AssignmentWork, error) {
+	db := connection.GetDb()
+
+	assignments, err := transactionManager.assignmentRepo.GetAssignmentsByTeacherEmail(teacherEmail, db)
+	if err != nil {
+		return nil, err
+	}
+	return transactionManager.workOn(assignments, teacherEmail, status, db)
+}
+
+// workOn lists the work on the assignments of the teacher, in the order of the assignments and then by student. The
+// students currently registered to the teacher are expected to submit, and the students who submitted before they
+// were unregistered keep their submission.
+func (transactionManager *TransactionManager) workOn(assignments []*models.Assignment, teacherEmail string, status string, db *gorm.DB) ([]*AssignmentWork, error) {
+	registeredStudentEmails, err := transactionManager.registeredStudentEmails(teacherEmail, db)
+	if err != nil {
+		return nil, err
+	}
+
+	assignmentIds := make([]uint, len(assignments))
+	for i, assignment := range assignments {
+		assignmentIds[i] = assignment.ID
+	}
+	submissions, err := transactionManager.assignmentRepo.GetSubmissionsByAssignmentIds(assignmentIds, db)
+	if err != nil {
+		return nil, err
+	}
+
+	submissionsByAssignment := make(map[uint]map[string]*models.Submission)
+	for _, submission := range submissions {
+		if submissionsByAssignment[submission.AssignmentID] == nil {
+			submissionsByAssignment[submission.AssignmentID] = make(map[string]*models.Submission)
+		}
+		submissionsByAssignment[submission.AssignmentID][submission.StudentEmail] = submission
+	}
+
+	now := time.Now()
+	works := make([]*AssignmentWork, 0)
+	for _, assignment := range assignments {
+		studentEmails := make([]string, 0, len(registeredStudentEmails))
+		for studentEmail := range registeredStudentEmails {
+			studentEmails = append(studentEmails, studentEmail)
+		}
+		for studentEmail := range submissionsByAssignment[assignment.ID] {
+			if !registeredStudentEmails[studentEmail] {
+				studentEmails = append(studentEmails, studentEmail)
+			}
+		}
+		sort.Strings(studentEmails)
+
+		for _, studentEmail := range studentEmails {
+			submission := submissionsByAssignment[assignment.ID][studentEmail]
+			workStatus := assignment.WorkStatus(submission, now)
+			if status == "" || status == workStatus {
+				works = append(works, &AssignmentWork{Assignment: assignment, StudentEmail: studentEmail, Submission: submission, Status: workStatus})
+			}
+		}
+	}
+	return works, nil
+}
+
+func (transactionManager *TransactionManager) registeredStudentEmails(teacherEmail string, db *gorm.DB) (map[string]bool, error) {
+	relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsByTeacherEmail(teacherEmail, db)
+	if err != nil {
+		return nil, err
+	}
+
+	studentEmails := make(map[string]bool, len(relationships))
+	for _, relationship := range relationships {
+		studentEmails[relationship.StudentEmail] = true
+	}
+	return studentEmails, nil
+}
+
+func generateNonExistentAssignmentError(assignmentId uint) error {
+	return problems.Newf(problems.ASSIGNMENT_NOT_FOUND, "", assignmentId).WithField("id")
+}
@@ -0,0 +1,155 @@
+package transaction_managers
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"strings"
+	"time"
+)
+
+// RollCall is the attendance a teacher recorded on a date, sorted by student, together with the students registered
+// to the teacher whose attendance is not recorded yet
+type RollCall struct {
+	TeacherEmail            string
+	Date                    string
+	Attendances             []*models.Attendance
+	UnrecordedStudentEmails []string
+}
+
+// AttendanceSummary counts the attendances of a student with a teacher by status. The rate is the percentage of the
+// days the student attended, nil when every attendance is excused or there is none.
+type AttendanceSummary struct {
+	StudentEmail string
+	Attendances  []*models.Attendance
+	Counts       map[string]int
+	Rate         *float64
+}
+
+// RecordRollCall records the status of each student registered to the teacher on the date, replacing the status
+// already recorded for them. The students left out of the roll call keep the status they have.
+func (transactionManager *TransactionManager) RecordRollCall(teacherEmail string, date string, statuses map[string]string, connection *database.Connection) (rollCall *RollCall, userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		registeredStudentEmails, err := transactionManager.registeredStudentEmails(teacherEmail, tx)
+		if err != nil {
+			return err
+		}
+
+		studentEmails := helpers.SortedKeys(statuses)
+		notRegisteredStudentEmails := helpers.Filter(studentEmails, func(studentEmail string) bool { return !registeredStudentEmails[studentEmail] })
+		if userError = generateNotRegisteredError(teacherEmail, notRegisteredStudentEmails); userError != nil {
+			return nil
+		}
+
+		recordedAt := time.Now()
+		attendances := helpers.Map(studentEmails, func(studentEmail string) *models.Attendance {
+			return &models.Attendance{TeacherEmail: teacherEmail, StudentEmail: studentEmail, Date: date, Status: statuses[studentEmail], RecordedAt: recordedAt}
+		})
+		if err := transactionManager.attendanceRepo.SaveAttendances(attendances, tx); err != nil {
+			return err
+		}
+
+		rollCall, err = transactionManager.rollCallOn(teacherEmail, date, tx)
+		return err
+	})
+	return rollCall, userError, dbError
+}
+
+func (transactionManager *TransactionManager) RetrieveRollCall(teacherEmail string, date string, connection *database.Connection) (*RollCall, error) {
+	db := connection.GetDb()
+	return transactionManager.rollCallOn(teacherEmail, date, db)
+}
+
+// RetrieveStudentAttendance sums up the attendances the teacher recorded for the student between the dates, a student
+// who was unregistered keeps the attendances recorded before
+func (transactionManager *TransactionManager) RetrieveStudentAttendance(teacherEmail string, studentEmail string, fromDate string, toDate string, connection *database.Connection) (*AttendanceSummary, error) {
+	db := connection.GetDb()
+
+	attendances, err := transactionManager.attendanceRepo.GetStudentAttendancesBetween(teacherEmail, studentEmail, fromDate, toDate, db)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeAttendances(studentEmail, attendances), nil
+}
+
+// RetrieveAttendanceRates sums up the attendances between the dates of the students registered to the teacher, and
+// of the students with attendances recorded between the dates, sorted by student. When a threshold is given, only
+// the students with a rate below it are kept.
+func (transactionManager *TransactionManager) RetrieveAttendanceRates(teacherEmail string, fromDate string, toDate string, below *float64, connection *database.Connection) ([]*AttendanceSummary, error) {
+	db := connection.GetDb()
+
+	registeredStudentEmails, err := transactionManager.registeredStudentEmails(teacherEmail, db)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := transactionManager.attendanceRepo.GetAttendancesBetween(teacherEmail, fromDate, toDate, db)
+	if err != nil {
+		return nil, err
+	}
+
+	attendancesByStudent := make(map[string][]*models.Attendance, len(registeredStudentEmails))
+	for studentEmail := range registeredStudentEmails {
+		attendancesByStudent[studentEmail] = make([]*models.Attendance, 0)
+	}
+	for _, attendance := range attendances {
+		attendancesByStudent[attendance.StudentEmail] = append(attendancesByStudent[attendance.StudentEmail], attendance)
+	}
+
+	summaries := make([]*AttendanceSummary, 0, len(attendancesByStudent))
+	for _, studentEmail := range helpers.SortedKeys(attendancesByStudent) {
+		summary := summarizeAttendances(studentEmail, attendancesByStudent[studentEmail])
+		if below == nil || (summary.Rate != nil && *summary.Rate < *below) {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
+// rollCallOn lists the attendances on the date, the students who were unregistered keep the attendance recorded
+// for them
+func (transactionManager *TransactionManager) rollCallOn(teacherEmail string, date string, db *gorm.DB) (*RollCall, error) {
+	registeredStudentEmails, err := transactionManager.registeredStudentEmails(teacherEmail, db)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := transactionManager.attendanceRepo.GetAttendancesByDate(teacherEmail, date, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attendance := range attendances {
+		delete(registeredStudentEmails, attendance.StudentEmail)
+	}
+	unrecordedStudentEmails := helpers.SortedKeys(registeredStudentEmails)
+	return &RollCall{TeacherEmail: teacherEmail, Date: date, Attendances: attendances, UnrecordedStudentEmails: unrecordedStudentEmails}, nil
+}
+
+func summarizeAttendances(studentEmail string, attendances []*models.Attendance) *AttendanceSummary {
+	counts := make(map[string]int, len(models.AttendanceStatuses))
+	for _, status := range models.AttendanceStatuses {
+		counts[status] = 0
+	}
+	for _, attendance := range attendances {
+		counts[attendance.Status]++
+	}
+
+	rate := helpers.AttendanceRate(counts[models.ATTENDANCE_PRESENT], counts[models.ATTENDANCE_LATE], counts[models.ATTENDANCE_ABSENT])
+	return &AttendanceSummary{StudentEmail: studentEmail, Attendances: attendances, Counts: counts, Rate: rate}
+}
+
+func generateNotRegisteredError(teacherEmail string, notRegisteredStudentEmails []string) error {
+	if len(notRegisteredStudentEmails) == 0 {
+		return nil
+	}
+
+	if len(notRegisteredStudentEmails) == 1 {
+		return problems.Newf(problems.REGISTRATION_NOT_FOUND, "", notRegisteredStudentEmails[0], teacherEmail).
+			WithEmails(notRegisteredStudentEmails...)
+	}
+
+	return problems.Newf(problems.REGISTRATION_NOT_FOUND, "plural", strings.Join(notRegisteredStudentEmails, ", "), teacherEmail).
+		WithEmails(notRegisteredStudentEmails...)
+}
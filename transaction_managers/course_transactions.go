@@ -0,0 +1,175 @@
+package transaction_managers
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+)
+
+// SectionRoster is a section together with the emails of its teachers and students, sorted by email
+type SectionRoster struct {
+	Section       *models.Section
+	TeacherEmails []string
+	StudentEmails []string
+}
+
+// SaveCourse creates the course, or renames it if it already exists
+func (transactionManager *TransactionManager) SaveCourse(courseCode string, name string, connection *database.Connection) (*models.Course, error) {
+	db := connection.GetDb()
+	course := &models.Course{Code: courseCode, Name: name}
+	if err := transactionManager.courseRepo.SaveCourse(course, db); err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+func (transactionManager *TransactionManager) RetrieveCourses(connection *database.Connection) ([]*models.Course, error) {
+	db := connection.GetDb()
+	return transactionManager.courseRepo.GetAllCourses(db)
+}
+
+// RetrieveCourse returns the course together with the rosters of its sections, sorted by name
+func (transactionManager *TransactionManager) RetrieveCourse(courseCode string, connection *database.Connection) (course *models.Course, sectionRosters []*SectionRoster, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if course, dbError = transactionManager.courseRepo.GetCourseByCode(courseCode, db); dbError != nil {
+		return nil, nil, nil, dbError
+	} else if course == nil {
+		return nil, nil, generateNonExistentCourseError(courseCode), nil
+	}
+
+	sections, dbError := transactionManager.courseRepo.GetSectionsByCourseCode(courseCode, db)
+	if dbError != nil {
+		return nil, nil, nil, dbError
+	}
+
+	sectionRosters = make([]*SectionRoster, 0, len(sections))
+	for _, section := range sections {
+		sectionRoster, dbError := transactionManager.retrieveSectionRoster(section, db)
+		if dbError != nil {
+			return nil, nil, nil, dbError
+		}
+		sectionRosters = append(sectionRosters, sectionRoster)
+	}
+	return course, sectionRosters, nil, nil
+}
+
+// SaveSection creates the section of the course if it does not exist yet and assigns the teachers to it, the teachers
+// already assigned to it stay assigned
+func (transactionManager *TransactionManager) SaveSection(courseCode string, sectionName string, teacherEmails []string, connection *database.Connection) (sectionRoster *SectionRoster, userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if course, err := transactionManager.courseRepo.GetCourseByCode(courseCode, tx); err != nil {
+			return err
+		} else if course == nil {
+			userError = generateNonExistentCourseError(courseCode)
+			return nil
+		}
+
+		section, err := transactionManager.courseRepo.CreateSectionIfNotExists(courseCode, sectionName, tx)
+		if err != nil {
+			return err
+		}
+		if err := transactionManager.courseRepo.AssignTeachersToSectionIfNotAssigned(section.ID, teacherEmails, tx); err != nil {
+			return err
+		}
+
+		sectionRoster, err = transactionManager.retrieveSectionRoster(section, tx)
+		return err
+	})
+	return sectionRoster, userError, dbError
+}
+
+// RetrieveSection returns the section of the course with the name, together with its teachers and students
+func (transactionManager *TransactionManager) RetrieveSection(courseCode string, sectionName string, connection *database.Connection) (sectionRoster *SectionRoster, userError error, dbError error) {
+	section, userError, dbError := transactionManager.ResolveSection(courseCode, sectionName, connection)
+	if userError != nil || dbError != nil {
+		return nil, userError, dbError
+	}
+
+	sectionRoster, dbError = transactionManager.retrieveSectionRoster(section, connection.GetDb())
+	return sectionRoster, nil, dbError
+}
+
+// ResolveSection returns the section of the course with the name, reporting a missing course or section
+func (transactionManager *TransactionManager) ResolveSection(courseCode string, sectionName string, connection *database.Connection) (section *models.Section, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if userError, dbError = transactionManager.ValidateCourseExists(courseCode, connection); userError != nil || dbError != nil {
+		return nil, userError, dbError
+	}
+
+	if section, dbError = transactionManager.courseRepo.GetSection(courseCode, sectionName, db); dbError != nil {
+		return nil, nil, dbError
+	} else if section == nil {
+		return nil, problems.Newf(problems.SECTION_NOT_FOUND, "", sectionName, courseCode).WithField("section"), nil
+	}
+	return section, nil, nil
+}
+
+// ResolveTeacherSection returns the section of the course the teacher is assigned to. The name of the section may be
+// left out when the teacher is assigned to a single section of the course.
+func (transactionManager *TransactionManager) ResolveTeacherSection(teacherEmail string, courseCode string, sectionName string, connection *database.Connection) (section *models.Section, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if userError, dbError = transactionManager.ValidateCourseExists(courseCode, connection); userError != nil || dbError != nil {
+		return nil, userError, dbError
+	}
+
+	sections, dbError := transactionManager.courseRepo.GetSectionsByTeacherEmail(teacherEmail, courseCode, db)
+	if dbError != nil {
+		return nil, nil, dbError
+	}
+
+	if sectionName == "" {
+		switch len(sections) {
+		case 0:
+			return nil, problems.Newf(problems.SECTION_NOT_FOUND, "teacher", teacherEmail, courseCode).WithField("course"), nil
+		case 1:
+			return sections[0], nil, nil
+		default:
+			return nil, problems.Newf(problems.MISSING_FIELD, "", "section").WithField("section"), nil
+		}
+	}
+
+	for _, section := range sections {
+		if section.Name == sectionName {
+			return section, nil, nil
+		}
+	}
+	return nil, problems.Newf(problems.SECTION_NOT_FOUND, "assigned", teacherEmail, sectionName, courseCode).WithField("section"), nil
+}
+
+func (transactionManager *TransactionManager) ValidateCourseExists(courseCode string, connection *database.Connection) (userError error, dbError error) {
+	db := connection.GetDb()
+
+	if course, dbError := transactionManager.courseRepo.GetCourseByCode(courseCode, db); dbError != nil {
+		return nil, dbError
+	} else if course == nil {
+		return generateNonExistentCourseError(courseCode), nil
+	}
+	return nil, nil
+}
+
+// EnrollStudentsInSection enrolls the students into the section, the students already enrolled in it stay enrolled
+func (transactionManager *TransactionManager) EnrollStudentsInSection(sectionId uint, studentEmails []string, connection *database.Connection) error {
+	db := connection.GetDb()
+	return transactionManager.courseRepo.EnrollStudentsIfNotEnrolled(sectionId, studentEmails, db)
+}
+
+func (transactionManager *TransactionManager) retrieveSectionRoster(section *models.Section, db *gorm.DB) (*SectionRoster, error) {
+	teacherEmails, err := transactionManager.courseRepo.GetTeacherEmailsBySectionId(section.ID, db)
+	if err != nil {
+		return nil, err
+	}
+	studentEmails, err := transactionManager.courseRepo.GetStudentEmailsBySectionId(section.ID, db)
+	if err != nil {
+		return nil, err
+	}
+	return &SectionRoster{Section: section, TeacherEmails: teacherEmails, StudentEmails: studentEmails}, nil
+}
+
+func generateNonExistentCourseError(courseCode string) error {
+	return problems.Newf(problems.COURSE_NOT_FOUND, "", courseCode).WithField("course")
+}
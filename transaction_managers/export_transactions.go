@@ -0,0 +1,85 @@
+package transaction_managers
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"time"
+)
+
+// EXPORT_BATCH_SIZE is the number of records read from the database at once while exporting
+const EXPORT_BATCH_SIZE = 1000
+
+// ExportRoster streams every teacher, then every student and then every registration to the writer, reading them in
+// batches within a single transaction so that the export is consistent. Students are suspended when they have a
+// suspension active at the start of the export.
+func (transactionManager *TransactionManager) ExportRoster(writer helpers.RosterWriter, connection *database.Connection) error {
+	db := connection.GetDb()
+	now := time.Now()
+	err := transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		for afterEmail := ""; ; {
+			teachers, err := transactionManager.teacherRepo.GetTeachersAfterEmail(afterEmail, EXPORT_BATCH_SIZE, tx)
+			if err != nil {
+				return err
+			}
+			for _, teacher := range teachers {
+				if err := writer.WriteTeacher(teacher); err != nil {
+					return err
+				}
+			}
+			if len(teachers) < EXPORT_BATCH_SIZE {
+				break
+			}
+			afterEmail = teachers[len(teachers)-1].Email
+		}
+
+		for afterEmail := ""; ; {
+			students, err := transactionManager.studentRepo.GetStudentsAfterEmail(afterEmail, EXPORT_BATCH_SIZE, tx)
+			if err != nil {
+				return err
+			}
+			suspendedStudentEmails, err := transactionManager.studentSuspensionRepo.GetActivelySuspendedStudentEmails(
+				helpers.Map(students, func(student *models.Student) string { return student.Email }), now, tx)
+			if err != nil {
+				return err
+			}
+			suspended := map[string]bool{}
+			for _, studentEmail := range suspendedStudentEmails {
+				suspended[studentEmail] = true
+			}
+			for _, student := range students {
+				if err := writer.WriteStudent(student, suspended[student.Email]); err != nil {
+					return err
+				}
+			}
+			if len(students) < EXPORT_BATCH_SIZE {
+				break
+			}
+			afterEmail = students[len(students)-1].Email
+		}
+
+		for afterTeacherEmail, afterStudentEmail := "", ""; ; {
+			relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsAfter(afterTeacherEmail, afterStudentEmail, EXPORT_BATCH_SIZE, tx)
+			if err != nil {
+				return err
+			}
+			for _, relationship := range relationships {
+				if err := writer.WriteRegistration(relationship); err != nil {
+					return err
+				}
+			}
+			if len(relationships) < EXPORT_BATCH_SIZE {
+				break
+			}
+			last := relationships[len(relationships)-1]
+			afterTeacherEmail, afterStudentEmail = last.TeacherEmail, last.StudentEmail
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,184 @@
+package transaction_managers
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"time"
+)
+
+// CategoryGrades are the grades of a gradebook in one of the categories of the teacher, the average is the percentage
+// of the points scored out of the points available, nil when there is no grade in the category
+type CategoryGrades struct {
+	Category *models.GradeCategory
+	Grades   []*models.Grade
+	Average  *float64
+}
+
+// Gradebook holds the grades a teacher gave a student by category. Its average weighs the averages of the categories,
+// leaving out the categories without grades, and is nil when the student has no grade at all.
+type Gradebook struct {
+	TeacherEmail string
+	StudentEmail string
+	Categories   []*CategoryGrades
+	Average      *float64
+}
+
+// SaveGradeCategory creates the category of the teacher, or changes its weight if it already exists
+func (transactionManager *TransactionManager) SaveGradeCategory(teacherEmail string, name string, weight float64, connection *database.Connection) (*models.GradeCategory, error) {
+	db := connection.GetDb()
+	category := &models.GradeCategory{TeacherEmail: teacherEmail, Name: name, Weight: weight}
+	if err := transactionManager.gradebookRepo.SaveCategory(category, db); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (transactionManager *TransactionManager) RetrieveGradeCategories(teacherEmail string, connection *database.Connection) ([]*models.GradeCategory, error) {
+	db := connection.GetDb()
+	return transactionManager.gradebookRepo.GetCategoriesByTeacherEmail(teacherEmail, db)
+}
+
+// GradeStudent stores the grade the teacher gives the student registered to them in the category with the name. A
+// grade for an assignment of the teacher takes its title and max score from the assignment unless they are given.
+func (transactionManager *TransactionManager) GradeStudent(grade *models.Grade, categoryName string, connection *database.Connection) (userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		var err error
+		if userError, err = transactionManager.validateRegistration(grade.TeacherEmail, grade.StudentEmail, tx); userError != nil || err != nil {
+			return err
+		}
+
+		category, err := transactionManager.gradebookRepo.GetCategory(grade.TeacherEmail, categoryName, tx)
+		if err != nil {
+			return err
+		} else if category == nil {
+			userError = problems.Newf(problems.CATEGORY_NOT_FOUND, "", categoryName, grade.TeacherEmail).WithField("category")
+			return nil
+		}
+		grade.CategoryID = category.ID
+
+		if grade.AssignmentID != nil {
+			assignment, err := transactionManager.assignmentRepo.GetAssignmentById(*grade.AssignmentID, tx)
+			if err != nil {
+				return err
+			} else if assignment == nil {
+				userError = problems.Newf(problems.ASSIGNMENT_NOT_FOUND, "", *grade.AssignmentID).WithField("assignment")
+				return nil
+			} else if assignment.TeacherEmail != grade.TeacherEmail {
+				userError = problems.Newf(problems.ASSIGNMENT_NOT_FOUND, "teacher", *grade.AssignmentID, grade.TeacherEmail).WithField("assignment")
+				return nil
+			}
+
+			if grade.Title == "" {
+				grade.Title = assignment.Title
+			}
+			if grade.MaxScore == 0 {
+				grade.MaxScore = float64(assignment.MaxScore)
+			}
+		}
+
+		// without an assignment to take them from, the title and max score must be given
+		if userError = problems.Collect(requiredGradeField("title", grade.Title != ""), requiredGradeField("max_score", grade.MaxScore != 0)); userError != nil {
+			return nil
+		}
+
+		grade.GradedAt = time.Now()
+		return transactionManager.gradebookRepo.CreateGrade(grade, tx)
+	})
+	return userError, dbError
+}
+
+// RetrieveGradebook returns the grades the teacher gave the student registered to them, by category
+func (transactionManager *TransactionManager) RetrieveGradebook(teacherEmail string, studentEmail string, connection *database.Connection) (gradebook *Gradebook, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if userError, dbError = transactionManager.validateRegistration(teacherEmail, studentEmail, db); userError != nil || dbError != nil {
+		return nil, userError, dbError
+	}
+
+	gradebook, dbError = transactionManager.gradebookOf(teacherEmail, studentEmail, db)
+	return gradebook, nil, dbError
+}
+
+// RetrieveReportCard returns the gradebooks of the student with every teacher they are registered to, sorted by
+// teacher, and the average of the averages of these gradebooks
+func (transactionManager *TransactionManager) RetrieveReportCard(studentEmail string, connection *database.Connection) (gradebooks []*Gradebook, average *float64, err error) {
+	db := connection.GetDb()
+
+	teacherEmails, err := transactionManager.registerRelationshipRepo.GetTeacherEmailsByStudentEmail(studentEmail, db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gradebooks = make([]*Gradebook, 0, len(teacherEmails))
+	var averages, weights []float64
+	for _, teacherEmail := range teacherEmails {
+		gradebook, err := transactionManager.gradebookOf(teacherEmail, studentEmail, db)
+		if err != nil {
+			return nil, nil, err
+		}
+		gradebooks = append(gradebooks, gradebook)
+
+		if gradebook.Average != nil {
+			averages = append(averages, *gradebook.Average)
+			weights = append(weights, 1)
+		}
+	}
+	return gradebooks, helpers.WeightedAverage(averages, weights), nil
+}
+
+func (transactionManager *TransactionManager) gradebookOf(teacherEmail string, studentEmail string, db *gorm.DB) (*Gradebook, error) {
+	categories, err := transactionManager.gradebookRepo.GetCategoriesByTeacherEmail(teacherEmail, db)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := transactionManager.gradebookRepo.GetGrades(teacherEmail, studentEmail, db)
+	if err != nil {
+		return nil, err
+	}
+
+	gradebook := &Gradebook{TeacherEmail: teacherEmail, StudentEmail: studentEmail, Categories: make([]*CategoryGrades, 0, len(categories))}
+	var categoryAverages, categoryWeights []float64
+	for _, category := range categories {
+		categoryGrades := &CategoryGrades{Category: category, Grades: make([]*models.Grade, 0)}
+		var percentages, maxScores []float64
+		for _, grade := range grades {
+			if grade.CategoryID == category.ID {
+				categoryGrades.Grades = append(categoryGrades.Grades, grade)
+				percentages = append(percentages, grade.Score/grade.MaxScore*100)
+				maxScores = append(maxScores, grade.MaxScore)
+			}
+		}
+
+		// weighing the percentages by their max score averages the points scored out of the points available
+		if categoryGrades.Average = helpers.WeightedAverage(percentages, maxScores); categoryGrades.Average != nil {
+			categoryAverages = append(categoryAverages, *categoryGrades.Average)
+			categoryWeights = append(categoryWeights, category.Weight)
+		}
+		gradebook.Categories = append(gradebook.Categories, categoryGrades)
+	}
+	gradebook.Average = helpers.WeightedAverage(categoryAverages, categoryWeights)
+	return gradebook, nil
+}
+
+func (transactionManager *TransactionManager) validateRegistration(teacherEmail string, studentEmail string, db *gorm.DB) (userError error, dbError error) {
+	teacherEmails, dbError := transactionManager.registerRelationshipRepo.GetTeacherEmailsByStudentEmail(studentEmail, db)
+	if dbError != nil {
+		return nil, dbError
+	}
+
+	if len(helpers.Filter(teacherEmails, func(registeredTeacherEmail string) bool { return registeredTeacherEmail == teacherEmail })) == 0 {
+		return generateNotRegisteredError(teacherEmail, []string{studentEmail}), nil
+	}
+	return nil, nil
+}
+
+func requiredGradeField(field string, supplied bool) error {
+	if supplied {
+		return nil
+	}
+	return problems.Newf(problems.MISSING_FIELD, "", field).WithField(field)
+}
@@ -0,0 +1,205 @@
+package transaction_managers
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"io"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+)
+
+// IMPORT_BATCH_SIZE is the number of roster lines written to the database at once while importing
+const IMPORT_BATCH_SIZE = 500
+
+// MAX_IMPORT_ROW_ERRORS is the number of invalid lines reported by an import, the lines after it are still counted
+const MAX_IMPORT_ROW_ERRORS = 100
+
+// errImportRolledBack rolls back the transaction of an import that is a dry run or has invalid lines
+var errImportRolledBack = errors.New("import rolled back")
+
+// RosterImport reports the outcome of an import, the teachers, students and registrations are counted once each
+// however many lines they are on. Registrations counts the registrations the import creates, or would create when it
+// is not committed, and ExistingRegistrations those of the roster that already exist.
+type RosterImport struct {
+	Rows                  int
+	Teachers              int
+	Students              int
+	Registrations         int
+	ExistingRegistrations int
+	InvalidRows           int
+	RowErrors             []*helpers.RowError
+	Committed             bool
+}
+
+// ImportRoster creates the teachers and students of the roster and registers them in a single transaction, streaming
+// the roster into the database in batches. Nothing is committed if the roster has an invalid line or it is a dry run.
+func (transactionManager *TransactionManager) ImportRoster(rows *helpers.RosterCsvReader, dryRun bool, connection *database.Connection) (rosterImport *RosterImport, err error) {
+	db := connection.GetDb()
+	rosterImport = &RosterImport{RowErrors: make([]*helpers.RowError, 0)}
+	teachers := make(map[string]bool)
+	students := make(map[string]bool)
+	registrations := make(map[string]map[string]bool)
+	createdRegistrations := make(map[string]map[string]bool)
+
+	err = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		batch := make([]*helpers.RosterRow, 0, IMPORT_BATCH_SIZE)
+
+		for {
+			row, err := rows.Next()
+			if err == io.EOF {
+				break
+			}
+
+			var rowError *helpers.RowError
+			if errors.As(err, &rowError) {
+				rosterImport.Rows++
+				rosterImport.InvalidRows++
+				if len(rosterImport.RowErrors) < MAX_IMPORT_ROW_ERRORS {
+					rosterImport.RowErrors = append(rosterImport.RowErrors, rowError)
+				}
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			rosterImport.Rows++
+			countRosterRow(row, teachers, students, registrations)
+
+			// once a line is invalid nothing will be committed, so the remaining lines are only validated
+			if rosterImport.InvalidRows > 0 {
+				continue
+			}
+			if batch = append(batch, row); len(batch) == IMPORT_BATCH_SIZE {
+				if err := transactionManager.importRosterBatch(batch, createdRegistrations, tx); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+
+		if rosterImport.InvalidRows == 0 {
+			if err := transactionManager.importRosterBatch(batch, createdRegistrations, tx); err != nil {
+				return err
+			}
+		}
+
+		existingRegistrations, err := transactionManager.countExistingRegistrations(registrations, createdRegistrations, tx)
+		if err != nil {
+			return err
+		}
+		rosterImport.ExistingRegistrations = existingRegistrations
+
+		if rosterImport.InvalidRows > 0 || dryRun {
+			return errImportRolledBack
+		}
+		return nil
+	})
+
+	rosterImport.Teachers = len(teachers)
+	rosterImport.Students = len(students)
+	for _, studentEmails := range registrations {
+		rosterImport.Registrations += len(studentEmails)
+	}
+	rosterImport.Registrations -= rosterImport.ExistingRegistrations
+
+	if err == errImportRolledBack {
+		return rosterImport, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rosterImport.Committed = true
+	if rosterImport.Registrations > 0 {
+		signal(transactionManager.webhooksQueued)
+	}
+	return rosterImport, nil
+}
+
+// importRosterBatch creates the teachers and students of the lines before registering the students of each teacher,
+// the registrations it creates are added to createdRegistrations
+func (transactionManager *TransactionManager) importRosterBatch(batch []*helpers.RosterRow, createdRegistrations map[string]map[string]bool, tx *gorm.DB) error {
+	teacherEmails := make([]string, 0)
+	studentEmails := make([]string, 0)
+	registrations := make(map[string][]string)
+	for _, row := range batch {
+		if row.TeacherEmail != "" {
+			teacherEmails = append(teacherEmails, row.TeacherEmail)
+		}
+		if row.StudentEmail != "" {
+			studentEmails = append(studentEmails, row.StudentEmail)
+		}
+		if row.TeacherEmail != "" && row.StudentEmail != "" {
+			registrations[row.TeacherEmail] = append(registrations[row.TeacherEmail], row.StudentEmail)
+		}
+	}
+
+	if len(teacherEmails) > 0 {
+		if err := transactionManager.teacherRepo.CreateTeachersIfNotExist(helpers.RemoveDuplicatesInStringSlice(teacherEmails), tx); err != nil {
+			return err
+		}
+	}
+	if len(studentEmails) > 0 {
+		if err := transactionManager.studentRepo.CreateStudentsIfNotExist(helpers.RemoveDuplicatesInStringSlice(studentEmails), tx); err != nil {
+			return err
+		}
+	}
+
+	for _, teacherEmail := range helpers.RemoveDuplicatesInStringSlice(teacherEmails) {
+		registeredStudentEmails, ok := registrations[teacherEmail]
+		if !ok {
+			continue
+		}
+
+		newStudentEmails, err := transactionManager.registerNewStudents(teacherEmail, registeredStudentEmails, tx)
+		if err != nil {
+			return err
+		}
+		for _, studentEmail := range newStudentEmails {
+			addRegistration(createdRegistrations, teacherEmail, studentEmail)
+		}
+	}
+	return nil
+}
+
+// countExistingRegistrations counts the registrations of the roster that exist but were not created by the import
+func (transactionManager *TransactionManager) countExistingRegistrations(registrations map[string]map[string]bool, createdRegistrations map[string]map[string]bool, tx *gorm.DB) (int, error) {
+	teacherEmails := make([]string, 0, len(registrations))
+	for teacherEmail := range registrations {
+		teacherEmails = append(teacherEmails, teacherEmail)
+	}
+	if len(teacherEmails) == 0 {
+		return 0, nil
+	}
+
+	relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsByTeacherEmails(teacherEmails, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	existingRegistrations := 0
+	for _, relationship := range relationships {
+		if registrations[relationship.TeacherEmail][relationship.StudentEmail] && !createdRegistrations[relationship.TeacherEmail][relationship.StudentEmail] {
+			existingRegistrations++
+		}
+	}
+	return existingRegistrations, nil
+}
+
+func countRosterRow(row *helpers.RosterRow, teachers map[string]bool, students map[string]bool, registrations map[string]map[string]bool) {
+	if row.TeacherEmail != "" {
+		teachers[row.TeacherEmail] = true
+	}
+	if row.StudentEmail != "" {
+		students[row.StudentEmail] = true
+	}
+	if row.TeacherEmail != "" && row.StudentEmail != "" {
+		addRegistration(registrations, row.TeacherEmail, row.StudentEmail)
+	}
+}
+
+func addRegistration(registrations map[string]map[string]bool, teacherEmail string, studentEmail string) {
+	if registrations[teacherEmail] == nil {
+		registrations[teacherEmail] = make(map[string]bool)
+	}
+	registrations[teacherEmail][studentEmail] = true
+}
@@ -0,0 +1,147 @@
+package transaction_managers
+
+import (
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/models"
+)
+
+// ProfileUpdate holds the profile fields to change, the nil fields and a nil metadata are kept
+type ProfileUpdate struct {
+	Name        *string
+	DisplayName *string
+	GradeLevel  *string
+	Homeroom    *string
+	Phone       *string
+	Metadata    map[string]string
+}
+
+func (update *ProfileUpdate) applyTo(profile *models.Profile) {
+	for _, field := range []struct {
+		value  *string
+		target *string
+	}{
+		{update.Name, &profile.Name},
+		{update.DisplayName, &profile.DisplayName},
+		{update.GradeLevel, &profile.GradeLevel},
+		{update.Homeroom, &profile.Homeroom},
+		{update.Phone, &profile.Phone},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+	if update.Metadata != nil {
+		profile.SetMetadata(update.Metadata)
+	}
+}
+
+func (transactionManager *TransactionManager) RetrieveStudent(studentEmail string, connection *database.Connection) (student *models.Student, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if student, dbError = transactionManager.studentRepo.GetStudentByEmail(studentEmail, db); dbError != nil {
+		return nil, nil, dbError
+	} else if student == nil {
+		return nil, generateNonExistentStudentsError([]string{studentEmail}), nil
+	}
+	return student, nil, nil
+}
+
+// UpdateStudentProfile changes the profile fields of the update and returns the student with their new profile
+func (transactionManager *TransactionManager) UpdateStudentProfile(studentEmail string, update *ProfileUpdate, connection *database.Connection) (student *models.Student, userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		var err error
+		if student, err = transactionManager.studentRepo.GetStudentByEmail(studentEmail, tx); err != nil {
+			return err
+		} else if student == nil {
+			userError = generateNonExistentStudentsError([]string{studentEmail})
+			return nil
+		}
+
+		update.applyTo(&student.Profile)
+		return transactionManager.studentRepo.UpdateStudentProfile(studentEmail, &student.Profile, tx)
+	})
+	return student, userError, dbError
+}
+
+// SaveStudentProfiles replaces the profiles of the students, keyed by their email
+func (transactionManager *TransactionManager) SaveStudentProfiles(profiles map[string]*models.Profile, connection *database.Connection) error {
+	db := connection.GetDb()
+	for studentEmail, profile := range profiles {
+		if err := transactionManager.studentRepo.UpdateStudentProfile(studentEmail, profile, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteStudent deletes the student together with everything recorded for them
+func (transactionManager *TransactionManager) DeleteStudent(studentEmail string, connection *database.Connection) (userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if student, err := transactionManager.studentRepo.GetStudentByEmail(studentEmail, tx); err != nil {
+			return err
+		} else if student == nil {
+			userError = generateNonExistentStudentsError([]string{studentEmail})
+			return nil
+		}
+		return transactionManager.studentRepo.DeleteStudent(studentEmail, tx)
+	})
+	return userError, dbError
+}
+
+func (transactionManager *TransactionManager) RetrieveTeacher(teacherEmail string, connection *database.Connection) (teacher *models.Teacher, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if teacher, dbError = transactionManager.teacherRepo.GetTeacherByEmail(teacherEmail, db); dbError != nil {
+		return nil, nil, dbError
+	} else if teacher == nil {
+		return nil, generateNonExistentTeachersError([]string{teacherEmail}), nil
+	}
+	return teacher, nil, nil
+}
+
+// UpdateTeacherProfile changes the profile fields of the update and returns the teacher with their new profile
+func (transactionManager *TransactionManager) UpdateTeacherProfile(teacherEmail string, update *ProfileUpdate, connection *database.Connection) (teacher *models.Teacher, userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		var err error
+		if teacher, err = transactionManager.teacherRepo.GetTeacherByEmail(teacherEmail, tx); err != nil {
+			return err
+		} else if teacher == nil {
+			userError = generateNonExistentTeachersError([]string{teacherEmail})
+			return nil
+		}
+
+		update.applyTo(&teacher.Profile)
+		return transactionManager.teacherRepo.UpdateTeacherProfile(teacherEmail, &teacher.Profile, tx)
+	})
+	return teacher, userError, dbError
+}
+
+// SaveTeacherProfiles replaces the profiles of the teachers, keyed by their email
+func (transactionManager *TransactionManager) SaveTeacherProfiles(profiles map[string]*models.Profile, connection *database.Connection) error {
+	db := connection.GetDb()
+	for teacherEmail, profile := range profiles {
+		if err := transactionManager.teacherRepo.UpdateTeacherProfile(teacherEmail, profile, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTeacher deletes the teacher together with everything they recorded
+func (transactionManager *TransactionManager) DeleteTeacher(teacherEmail string, connection *database.Connection) (userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if teacher, err := transactionManager.teacherRepo.GetTeacherByEmail(teacherEmail, tx); err != nil {
+			return err
+		} else if teacher == nil {
+			userError = generateNonExistentTeachersError([]string{teacherEmail})
+			return nil
+		}
+		return transactionManager.teacherRepo.DeleteTeacher(teacherEmail, tx)
+	})
+	return userError, dbError
+}
@@ -1,68 +1,438 @@
 package transaction_managers
 
 import (
-	"fmt"
+	"gorm.io/gorm"
 	"learning-management-system/database"
 	"learning-management-system/helpers"
 	"learning-management-system/models"
+	"learning-management-system/notifiers"
+	"learning-management-system/problems"
 	"learning-management-system/repositories"
+	"learning-management-system/types"
+	"sort"
 	"strings"
+	"time"
 )
 
+// DELIVERY_BATCH_SIZE is the maximum number of deliveries attempted by one call to DeliverPendingNotifications
+const DELIVERY_BATCH_SIZE = 100
+
+const MAX_RETRY_BACKOFF_DOUBLINGS = 6
+
 type TransactionManager struct {
-	studentRepo              *repositories.StudentRepo
-	teacherRepo              *repositories.TeacherRepo
-	registerRelationshipRepo *repositories.RegisterRelationshipRepo
+	studentRepo              repositories.StudentRepository
+	teacherRepo              repositories.TeacherRepository
+	registerRelationshipRepo repositories.RegistrationRepository
+	studentSuspensionRepo    repositories.StudentSuspensionRepository
+	notificationRepo         repositories.NotificationRepository
+	notificationDeliveryRepo repositories.NotificationDeliveryRepository
+	webhookSubscriptionRepo  repositories.WebhookSubscriptionRepository
+	webhookDeliveryRepo      repositories.WebhookDeliveryRepository
+	courseRepo               repositories.CourseRepository
+	assignmentRepo           repositories.AssignmentRepository
+	gradebookRepo            repositories.GradebookRepository
+	attendanceRepo           repositories.AttendanceRepository
+	transactor               repositories.Transactor
+	notificationsQueued      chan struct{}
+	webhooksQueued           chan struct{}
 }
 
-func NewTransactionManager() *TransactionManager {
+func NewTransactionManager(repos *repositories.Repositories) *TransactionManager {
 	return &TransactionManager{
-		studentRepo:              repositories.NewStudentRepo(),
-		teacherRepo:              repositories.NewTeacherRepo(),
-		registerRelationshipRepo: repositories.NewRegisterRelationshipRepo(),
+		studentRepo:              repos.Students,
+		teacherRepo:              repos.Teachers,
+		registerRelationshipRepo: repos.Registrations,
+		studentSuspensionRepo:    repos.Suspensions,
+		notificationRepo:         repos.Notifications,
+		notificationDeliveryRepo: repos.Deliveries,
+		webhookSubscriptionRepo:  repos.Webhooks,
+		webhookDeliveryRepo:      repos.WebhookDeliveries,
+		courseRepo:               repos.Courses,
+		assignmentRepo:           repos.Assignments,
+		gradebookRepo:            repos.Gradebooks,
+		attendanceRepo:           repos.Attendances,
+		transactor:               repos.Transactor,
+		notificationsQueued:      make(chan struct{}, 1),
+		webhooksQueued:           make(chan struct{}, 1),
 	}
 }
 
+// NotificationsQueued receives a value whenever notification deliveries have been queued, so that they can be
+// delivered right away instead of waiting for the next periodic run
+func (transactionManager *TransactionManager) NotificationsQueued() <-chan struct{} {
+	return transactionManager.notificationsQueued
+}
+
 func (transactionManager *TransactionManager) RegisterStudentsToTeacher(teacherEmail string, studentEmails []string, connection *database.Connection) error {
 	db := connection.GetDb()
-	return transactionManager.registerRelationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists(teacherEmail, studentEmails, db)
+	err := transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		_, err := transactionManager.registerNewStudents(teacherEmail, studentEmails, tx)
+		return err
+	})
+
+	if err == nil {
+		signal(transactionManager.webhooksQueued)
+	}
+	return err
 }
 
-func (transactionManager *TransactionManager) SuspendStudent(studentEmail string, connection *database.Connection) error {
-	db := connection.GetDb()
-	studentToUpdate := models.Student{Email: studentEmail, IsSuspended: true}
-	return transactionManager.studentRepo.UpdateStudent(&studentToUpdate, db)
+// registerNewStudents registers the students that are not registered to the teacher yet and returns them. The
+// students.registered event is only queued for them, as the others were announced when they were registered.
+func (transactionManager *TransactionManager) registerNewStudents(teacherEmail string, studentEmails []string, tx *gorm.DB) (newStudentEmails []string, err error) {
+	relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsByTeacherEmail(teacherEmail, tx)
+	if err != nil {
+		return nil, err
+	}
+	registeredStudentEmails := helpers.Map(relationships, func(relationship *models.RegisterRelationship) string {
+		return relationship.StudentEmail
+	})
+
+	newStudentEmails = helpers.RemoveAllStringsInSlice(helpers.RemoveDuplicatesInStringSlice(studentEmails), registeredStudentEmails)
+	if len(newStudentEmails) == 0 {
+		return newStudentEmails, nil
+	}
+
+	if err := transactionManager.registerRelationshipRepo.CreateOneTeacherToManyStudentsRegisterRelationshipsIfNotExists(teacherEmail, newStudentEmails, tx); err != nil {
+		return nil, err
+	}
+	return newStudentEmails, transactionManager.enqueueWebhookEvent(models.EVENT_STUDENTS_REGISTERED, &types.StudentsRegisteredEvent{
+		TeacherEmail:  teacherEmail,
+		StudentEmails: newStudentEmails,
+	}, time.Now(), tx)
 }
 
-func (transactionManager *TransactionManager) RetrieveCommonStudentEmails(teacherEmails []string, connection *database.Connection) ([]string, error) {
+// UnregisterStudentsFromTeacher removes the relationships between the teacher and the students, returning the
+// students that were unregistered and the students that were not registered to the teacher in the first place
+func (transactionManager *TransactionManager) UnregisterStudentsFromTeacher(teacherEmail string, studentEmails []string, connection *database.Connection) (unregisteredStudentEmails []string, notRegisteredStudentEmails []string, err error) {
 	db := connection.GetDb()
+	err = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsByTeacherEmail(teacherEmail, tx)
+		if err != nil {
+			return err
+		}
+
+		registeredStudentEmails := helpers.Map(relationships, func(relationship *models.RegisterRelationship) string {
+			return relationship.StudentEmail
+		})
+
+		notRegisteredStudentEmails = helpers.RemoveAllStringsInSlice(studentEmails, registeredStudentEmails)
+		unregisteredStudentEmails = helpers.RemoveAllStringsInSlice(studentEmails, notRegisteredStudentEmails)
 
-	relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsByTeacherEmails(teacherEmails, db)
+		if len(unregisteredStudentEmails) == 0 {
+			return nil
+		}
+		return transactionManager.registerRelationshipRepo.DeleteRegisterRelationships(teacherEmail, unregisteredStudentEmails, tx)
+	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return unregisteredStudentEmails, notRegisteredStudentEmails, nil
+}
+
+// SuspendStudent records a new suspension that lasts until it is lifted or, if until is given, until then.
+// Suspending an already suspended student only ever extends their suspension: it is made to last until the later
+// of both ends, and keeps who suspended them and why. A suspension that is not extended changes nothing.
+func (transactionManager *TransactionManager) SuspendStudent(studentEmail string, suspendedBy string, reason string, until *time.Time, connection *database.Connection) error {
+	db := connection.GetDb()
+	err := transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		now := time.Now()
+		activeSuspension, err := transactionManager.studentSuspensionRepo.GetActiveSuspensionByStudentEmail(studentEmail, now, tx)
+		if err != nil {
+			return err
+		}
+
+		if activeSuspension != nil {
+			if !extends(activeSuspension.Until, until) {
+				return nil
+			}
+			if err := transactionManager.studentSuspensionRepo.UpdateSuspensionUntil(activeSuspension.ID, until, tx); err != nil {
+				return err
+			}
+			suspendedBy = activeSuspension.SuspendedBy
+			reason = activeSuspension.Reason
+		} else if err := transactionManager.studentSuspensionRepo.CreateSuspension(&models.StudentSuspension{
+			StudentEmail: studentEmail,
+			SuspendedBy:  suspendedBy,
+			Reason:       reason,
+			SuspendedAt:  now,
+			Until:        until,
+		}, tx); err != nil {
+			return err
+		}
+
+		studentToUpdate := models.Student{Email: studentEmail, IsSuspended: true}
+		if err := transactionManager.studentRepo.UpdateStudent(&studentToUpdate, tx); err != nil {
+			return err
+		}
+
+		return transactionManager.enqueueWebhookEvent(models.EVENT_STUDENT_SUSPENDED, &types.StudentSuspendedEvent{
+			StudentEmail: studentEmail,
+			SuspendedBy:  suspendedBy,
+			Reason:       reason,
+			Until:        until,
+		}, now, tx)
+	})
+
+	if err == nil {
+		signal(transactionManager.webhooksQueued)
+	}
+	return err
+}
+
+// UnsuspendStudent lifts the active suspension of a student, unsuspending a student who is not suspended changes nothing
+func (transactionManager *TransactionManager) UnsuspendStudent(studentEmail string, connection *database.Connection) error {
+	db := connection.GetDb()
+	return transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if err := transactionManager.studentSuspensionRepo.LiftActiveSuspensions(studentEmail, time.Now(), tx); err != nil {
+			return err
+		}
+		return transactionManager.studentRepo.UpdateStudentSuspensionStatus(studentEmail, false, tx)
+	})
+}
+
+// LiftExpiredSuspensions lifts the suspensions that have ended at the given time and clears the suspension flag of
+// students who have no other active suspension
+func (transactionManager *TransactionManager) LiftExpiredSuspensions(at time.Time, connection *database.Connection) error {
+	db := connection.GetDb()
+	return transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		expiredSuspensions, err := transactionManager.studentSuspensionRepo.GetExpiredSuspensions(at, tx)
+		if err != nil || len(expiredSuspensions) == 0 {
+			return err
+		}
+
+		for _, suspension := range expiredSuspensions {
+			if err := transactionManager.studentSuspensionRepo.LiftSuspension(suspension.ID, *suspension.Until, tx); err != nil {
+				return err
+			}
+		}
 
-	// create a map of student emails to their count
-	studentEmailCountMap := make(map[string]int)
-	for _, relationship := range relationships {
-		studentEmailCountMap[relationship.StudentEmail]++
+		studentEmails := helpers.RemoveDuplicatesInStringSlice(helpers.Map(expiredSuspensions, func(suspension *models.StudentSuspension) string {
+			return suspension.StudentEmail
+		}))
+
+		stillSuspendedStudentEmails, err := transactionManager.studentSuspensionRepo.GetActivelySuspendedStudentEmails(studentEmails, at, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, studentEmail := range helpers.RemoveAllStringsInSlice(studentEmails, stillSuspendedStudentEmails) {
+			if err := transactionManager.studentRepo.UpdateStudentSuspensionStatus(studentEmail, false, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (transactionManager *TransactionManager) RetrieveStudentSuspensions(studentEmail string, connection *database.Connection) ([]*models.StudentSuspension, error) {
+	db := connection.GetDb()
+	return transactionManager.studentSuspensionRepo.GetSuspensionsByStudentEmail(studentEmail, db)
+}
+
+// WithTx runs the unit of work in a single transaction. The connection passed to it is bound to the transaction and
+// should be passed to the other methods of the transaction manager, so that their reads and writes are all committed
+// together, or all rolled back if the unit of work returns an error or panics. The error is returned as is.
+func (transactionManager *TransactionManager) WithTx(connection *database.Connection, unitOfWork func(tx *database.Connection) error) error {
+	db := connection.GetDb()
+	err := transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		return unitOfWork(database.WrapDb(tx))
+	})
+
+	// the schedulers may have been woken up before the work queued in the transaction was visible to them
+	if err == nil {
+		signal(transactionManager.notificationsQueued)
+		signal(transactionManager.webhooksQueued)
 	}
+	return err
+}
+
+// RetrieveCommonStudentEmails returns a page of at most limit students of the distinct teachers selected by the mode,
+// sorted by email and starting after afterStudentEmail, and whether there are more students after the page:
+//   - all lists the students registered to every teacher
+//   - any lists the students registered to any of the teachers
+//   - only lists the students of the first teacher who are registered to none of the others
+//   - atleast lists the students registered to at least mode.AtLeast of the teachers
+//
+// When a course is given, a student is only counted for the teachers assigned to a section of the course the student
+// is enrolled in. A limit that is not positive returns every student.
+func (transactionManager *TransactionManager) RetrieveCommonStudentEmails(teacherEmails []string, courseCode string, mode *helpers.CommonStudentsMode, afterStudentEmail string, limit int, connection *database.Connection) (studentEmails []string, hasMore bool, err error) {
+	db := connection.GetDb()
+	registeredToAtLeast := transactionManager.registerRelationshipRepo.GetStudentEmailsRegisteredToAtLeast
+	registeredOnlyTo := transactionManager.registerRelationshipRepo.GetStudentEmailsRegisteredOnlyTo
 
-	// fetch all students with count equal to number of teachers
-	commonStudents := make([]string, 0)
-	for studentEmail, count := range studentEmailCountMap {
-		if count == len(teacherEmails) {
-			commonStudents = append(commonStudents, studentEmail)
+	if courseCode != "" {
+		registeredToAtLeast = func(teacherEmails []string, minTeacherCount int, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+			return transactionManager.courseRepo.GetStudentEmailsTaughtByAtLeast(teacherEmails, courseCode, minTeacherCount, afterStudentEmail, limit, db)
 		}
+		registeredOnlyTo = func(teacherEmail string, otherTeacherEmails []string, afterStudentEmail string, limit int, db *gorm.DB) ([]string, error) {
+			return transactionManager.courseRepo.GetStudentEmailsTaughtOnlyBy(teacherEmail, otherTeacherEmails, courseCode, afterStudentEmail, limit, db)
+		}
+	}
+
+	// one more student than the page tells whether there are more
+	pageLimit := 0
+	if limit > 0 {
+		pageLimit = limit + 1
 	}
 
-	return commonStudents, nil
+	switch mode.Name {
+	case helpers.MODE_ANY:
+		studentEmails, err = registeredToAtLeast(teacherEmails, 1, afterStudentEmail, pageLimit, db)
+	case helpers.MODE_ONLY:
+		studentEmails, err = registeredOnlyTo(teacherEmails[0], teacherEmails[1:], afterStudentEmail, pageLimit, db)
+	case helpers.MODE_AT_LEAST:
+		studentEmails, err = registeredToAtLeast(teacherEmails, mode.AtLeast, afterStudentEmail, pageLimit, db)
+	default:
+		studentEmails, err = registeredToAtLeast(teacherEmails, len(teacherEmails), afterStudentEmail, pageLimit, db)
+	}
+
+	if err != nil || limit <= 0 {
+		return studentEmails, false, err
+	}
+	studentEmails, hasMore = splitPage(studentEmails, limit)
+	return studentEmails, hasMore, nil
 }
 
-func (transactionManager *TransactionManager) RetrieveStudentRecipients(teacherEmail string, mentionedStudentEmails []string, connection *database.Connection) ([]string, error) {
+// RetrieveStudentRecipients resolves the recipients of a notification from the teacher, stores the notification
+// in the inbox of each recipient and queues its delivery to each recipient. The recipients are sorted by email.
+func (transactionManager *TransactionManager) RetrieveStudentRecipients(teacherEmail string, notificationMessage string, mentionedStudentEmails []string, connection *database.Connection) (notificationId uint, recipientEmails []string, err error) {
 	db := connection.GetDb()
+	err = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if recipientEmails, err = transactionManager.resolveStudentRecipients(teacherEmail, mentionedStudentEmails, tx); err != nil {
+			return err
+		}
+
+		notification := &models.Notification{TeacherEmail: teacherEmail, Message: notificationMessage, SentAt: time.Now()}
+		if err := transactionManager.notificationRepo.CreateNotification(notification, recipientEmails, tx); err != nil {
+			return err
+		}
+		notificationId = notification.ID
+		if err := transactionManager.notificationDeliveryRepo.CreateDeliveries(notification.ID, recipientEmails, notification.SentAt, tx); err != nil {
+			return err
+		}
+
+		return transactionManager.enqueueWebhookEvent(models.EVENT_NOTIFICATION_SENT, &types.NotificationSentEvent{
+			NotificationId:  notification.ID,
+			TeacherEmail:    teacherEmail,
+			Message:         notificationMessage,
+			RecipientEmails: recipientEmails,
+		}, notification.SentAt, tx)
+	})
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(recipientEmails) > 0 {
+		signal(transactionManager.notificationsQueued)
+	}
+	signal(transactionManager.webhooksQueued)
+	return notificationId, recipientEmails, nil
+}
 
+func (transactionManager *TransactionManager) RetrieveNotification(notificationId uint, connection *database.Connection) (notification *models.Notification, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if notification, dbError = transactionManager.notificationRepo.GetNotificationById(notificationId, db); dbError != nil {
+		return nil, nil, dbError
+	} else if notification == nil {
+		return nil, generateNonExistentNotificationError(notificationId), nil
+	}
+	return notification, nil, nil
+}
+
+// RetrieveNotificationRecipientEmails returns a page of at most limit recipients of a stored notification, sorted by
+// email and starting after afterStudentEmail, and whether there are more recipients after the page
+func (transactionManager *TransactionManager) RetrieveNotificationRecipientEmails(notificationId uint, afterStudentEmail string, limit int, connection *database.Connection) (recipientEmails []string, hasMore bool, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if notification, dbError := transactionManager.notificationRepo.GetNotificationById(notificationId, db); dbError != nil {
+		return nil, false, nil, dbError
+	} else if notification == nil {
+		return nil, false, generateNonExistentNotificationError(notificationId), nil
+	}
+
+	recipientEmails, dbError = transactionManager.notificationRepo.GetRecipientEmails(notificationId, afterStudentEmail, limit+1, db)
+	if dbError != nil {
+		return nil, false, nil, dbError
+	}
+	recipientEmails, hasMore = splitPage(recipientEmails, limit)
+	return recipientEmails, hasMore, nil, nil
+}
+
+// DeliverPendingNotifications attempts every delivery that is due at the given time. A failed delivery is retried
+// after retryBackoff, doubling for every further failure, until it has been attempted maxAttempts times.
+func (transactionManager *TransactionManager) DeliverPendingNotifications(notifier notifiers.Notifier, maxAttempts int, retryBackoff time.Duration, at time.Time, connection *database.Connection) error {
+	db := connection.GetDb()
+
+	deliveries, err := transactionManager.notificationDeliveryRepo.GetDueDeliveries(at, DELIVERY_BATCH_SIZE, db)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		delivery.Attempts++
+
+		if notifyErr := notifier.Notify(delivery.StudentEmail, &delivery.Notification); notifyErr == nil {
+			deliveredAt := time.Now()
+			delivery.Status = models.DELIVERY_SENT
+			delivery.LastError = ""
+			delivery.DeliveredAt = &deliveredAt
+		} else if delivery.Attempts >= maxAttempts {
+			delivery.Status = models.DELIVERY_FAILED
+			delivery.LastError = notifyErr.Error()
+		} else {
+			delivery.LastError = notifyErr.Error()
+			delivery.NextAttemptAt = at.Add(retryDelay(retryBackoff, delivery.Attempts))
+		}
+
+		if err := transactionManager.notificationDeliveryRepo.UpdateDelivery(delivery, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (transactionManager *TransactionManager) RetrieveNotificationDeliveries(notificationId uint, connection *database.Connection) (deliveries []*models.NotificationDelivery, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if notification, dbError := transactionManager.notificationRepo.GetNotificationById(notificationId, db); dbError != nil {
+		return nil, nil, dbError
+	} else if notification == nil {
+		return nil, generateNonExistentNotificationError(notificationId), nil
+	}
+
+	deliveries, dbError = transactionManager.notificationDeliveryRepo.GetDeliveriesByNotificationId(notificationId, db)
+	return deliveries, nil, dbError
+}
+
+// signal wakes up the scheduler receiving from the channel without blocking, a signal already pending is enough
+func signal(queued chan struct{}) {
+	select {
+	case queued <- struct{}{}:
+	default:
+	}
+}
+
+// splitPage cuts a page fetched with one extra row down to limit rows, reporting whether the extra row was there
+func splitPage[T any](entries []T, limit int) ([]T, bool) {
+	if len(entries) > limit {
+		return entries[:limit], true
+	}
+	return entries, false
+}
+
+// retryDelay doubles the backoff for every failed attempt after the first, up to MAX_RETRY_BACKOFF_DOUBLINGS times
+func retryDelay(retryBackoff time.Duration, attempts int) time.Duration {
+	doublings := attempts - 1
+	if doublings > MAX_RETRY_BACKOFF_DOUBLINGS {
+		doublings = MAX_RETRY_BACKOFF_DOUBLINGS
+	}
+	return retryBackoff << doublings
+}
+
+func (transactionManager *TransactionManager) resolveStudentRecipients(teacherEmail string, mentionedStudentEmails []string, db *gorm.DB) ([]string, error) {
 	relationships, err := transactionManager.registerRelationshipRepo.GetRelationshipsByTeacherEmail(teacherEmail, db)
 
 	if err != nil {
@@ -73,21 +443,15 @@ func (transactionManager *TransactionManager) RetrieveStudentRecipients(teacherE
 		return &relationship.RegisteredStudent
 	})
 
+	// get mentioned students
+	mentionedStudents, err := transactionManager.studentRepo.GetStudentsByEmails(mentionedStudentEmails, db)
 	if err != nil {
 		return nil, err
 	}
 
-	// get mentioned students
-	mentionedStudents, err := transactionManager.studentRepo.GetStudentsByEmails(mentionedStudentEmails, db)
-
 	// append mentioned students to students
 	students = append(students, mentionedStudents...)
 
-	// filter out suspended students
-	students = helpers.Filter(students, func(student *models.Student) bool {
-		return !student.IsSuspended
-	})
-
 	// map students to emails
 	studentEmails := helpers.Map(students, func(student *models.Student) string {
 		return student.Email
@@ -96,22 +460,86 @@ func (transactionManager *TransactionManager) RetrieveStudentRecipients(teacherE
 	// remove duplicates
 	studentEmails = helpers.RemoveDuplicatesInStringSlice(studentEmails)
 
+	// filter out students with a suspension that is active now, expired suspensions may not be lifted yet
+	suspendedStudentEmails, err := transactionManager.studentSuspensionRepo.GetActivelySuspendedStudentEmails(studentEmails, time.Now(), db)
+	if err != nil {
+		return nil, err
+	}
+	studentEmails = helpers.RemoveAllStringsInSlice(studentEmails, suspendedStudentEmails)
+	sort.Strings(studentEmails)
+
 	return studentEmails, nil
 }
 
-func (transactionManager *TransactionManager) ClearDatabase(connection *database.Connection) error {
+// RetrieveStudentInbox returns a page of at most limit entries of the inbox of a student, newest first and starting
+// before the notification with id beforeNotificationId unless it is 0, whether there are more entries after the page,
+// and the total number of notifications and the number of unread notifications in the inbox
+func (transactionManager *TransactionManager) RetrieveStudentInbox(studentEmail string, unreadOnly bool, beforeNotificationId uint, limit int, connection *database.Connection) (inbox []*models.NotificationRecipient, hasMore bool, total int64, unread int64, err error) {
 	db := connection.GetDb()
-	tx := db.Begin()
-	if err := transactionManager.registerRelationshipRepo.DeleteAllRegisterRelationships(tx); err != nil {
-		tx.Rollback()
+
+	if inbox, err = transactionManager.notificationRepo.GetInboxByStudentEmail(studentEmail, unreadOnly, beforeNotificationId, limit+1, db); err != nil {
+		return nil, false, 0, 0, err
 	}
-	if err := transactionManager.studentRepo.DeleteAllStudents(tx); err != nil {
-		tx.Rollback()
+	inbox, hasMore = splitPage(inbox, limit)
+	if total, err = transactionManager.notificationRepo.CountInboxByStudentEmail(studentEmail, false, db); err != nil {
+		return nil, false, 0, 0, err
 	}
-	if err := transactionManager.teacherRepo.DeleteAllTeachers(tx); err != nil {
-		tx.Rollback()
+	if unread, err = transactionManager.notificationRepo.CountInboxByStudentEmail(studentEmail, true, db); err != nil {
+		return nil, false, 0, 0, err
 	}
-	return tx.Commit().Error
+	return inbox, hasMore, total, unread, nil
+}
+
+func (transactionManager *TransactionManager) MarkNotificationAsRead(notificationId uint, studentEmail string, connection *database.Connection) (userError error, dbError error) {
+	db := connection.GetDb()
+
+	if entry, dbError := transactionManager.notificationRepo.GetInboxEntry(notificationId, studentEmail, db); dbError != nil {
+		return nil, dbError
+	} else if entry == nil {
+		return problems.Newf(problems.NOTIFICATION_NOT_FOUND, "inbox", notificationId, studentEmail).WithEmails(studentEmail), nil
+	}
+
+	return nil, transactionManager.notificationRepo.MarkInboxEntryAsRead(notificationId, studentEmail, time.Now(), db)
+}
+
+func (transactionManager *TransactionManager) ClearDatabase(connection *database.Connection) error {
+	db := connection.GetDb()
+	return transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if err := transactionManager.webhookDeliveryRepo.DeleteAllWebhookDeliveries(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.webhookSubscriptionRepo.DeleteAllSubscriptions(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.notificationDeliveryRepo.DeleteAllDeliveries(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.notificationRepo.DeleteAllNotifications(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.studentSuspensionRepo.DeleteAllSuspensions(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.courseRepo.DeleteAllCourses(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.attendanceRepo.DeleteAllAttendances(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.gradebookRepo.DeleteAllGrades(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.assignmentRepo.DeleteAllAssignments(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.registerRelationshipRepo.DeleteAllRegisterRelationships(tx); err != nil {
+			return err
+		}
+		if err := transactionManager.studentRepo.DeleteAllStudents(tx); err != nil {
+			return err
+		}
+		return transactionManager.teacherRepo.DeleteAllTeachers(tx)
+	})
 }
 
 func (transactionManager *TransactionManager) PopulateStudents(studentEmails []string, connection *database.Connection) error {
@@ -126,6 +554,12 @@ func (transactionManager *TransactionManager) PopulateTeachers(teacherEmails []s
 	return err
 }
 
+// RetrieveStudentTeacherEmails returns the emails of the teachers the student is registered to
+func (transactionManager *TransactionManager) RetrieveStudentTeacherEmails(studentEmail string, connection *database.Connection) ([]string, error) {
+	db := connection.GetDb()
+	return transactionManager.registerRelationshipRepo.GetTeacherEmailsByStudentEmail(studentEmail, db)
+}
+
 func (transactionManager *TransactionManager) ValidateStudentsExists(studentEmails []string, connection *database.Connection) (userError error, dbError error) {
 	db := connection.GetDb()
 
@@ -186,16 +620,22 @@ func (transactionManager *TransactionManager) ValidateTeachersExists(teacherEmai
 	return generateNonExistentTeachersError(nonExistentTeacherEmails), nil
 }
 
-func generateNonExistentStudentsError(nonExistentTeacherEmails []string) error {
-	if len(nonExistentTeacherEmails) == 0 {
+func generateNonExistentStudentsError(nonExistentStudentEmails []string) error {
+	if len(nonExistentStudentEmails) == 0 {
 		return nil
 	}
 
-	if len(nonExistentTeacherEmails) == 1 {
-		return fmt.Errorf("Student with email %s does not exist in the database", nonExistentTeacherEmails[0])
+	if len(nonExistentStudentEmails) == 1 {
+		return problems.Newf(problems.STUDENT_NOT_FOUND, "", nonExistentStudentEmails[0]).
+			WithEmails(nonExistentStudentEmails...)
 	}
 
-	return fmt.Errorf("Students with emails %s do not exist in the database", strings.Join(nonExistentTeacherEmails, ", "))
+	return problems.Newf(problems.STUDENT_NOT_FOUND, "plural", strings.Join(nonExistentStudentEmails, ", ")).
+		WithEmails(nonExistentStudentEmails...)
+}
+
+func generateNonExistentNotificationError(notificationId uint) error {
+	return problems.Newf(problems.NOTIFICATION_NOT_FOUND, "", notificationId)
 }
 
 func generateNonExistentTeachersError(nonExistentTeacherEmails []string) error {
@@ -204,8 +644,19 @@ func generateNonExistentTeachersError(nonExistentTeacherEmails []string) error {
 	}
 
 	if len(nonExistentTeacherEmails) == 1 {
-		return fmt.Errorf("Teacher with email %s does not exist in the database", nonExistentTeacherEmails[0])
+		return problems.Newf(problems.TEACHER_NOT_FOUND, "", nonExistentTeacherEmails[0]).
+			WithEmails(nonExistentTeacherEmails...)
 	}
 
-	return fmt.Errorf("Teachers with emails %s do not exist in the database", strings.Join(nonExistentTeacherEmails, ", "))
+	return problems.Newf(problems.TEACHER_NOT_FOUND, "plural", strings.Join(nonExistentTeacherEmails, ", ")).
+		WithEmails(nonExistentTeacherEmails...)
+}
+
+// extends tells whether a suspension until the second end would last longer than one until the first, a nil end
+// lasting forever
+func extends(first *time.Time, second *time.Time) bool {
+	if first == nil {
+		return false
+	}
+	return second == nil || second.After(*first)
 }
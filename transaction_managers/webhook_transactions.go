@@ -0,0 +1,152 @@
+package transaction_managers
+
+import (
+	"encoding/json"
+	"gorm.io/gorm"
+	"learning-management-system/database"
+	"learning-management-system/helpers"
+	"learning-management-system/models"
+	"learning-management-system/problems"
+	"learning-management-system/types"
+	"learning-management-system/webhooks"
+	"strings"
+	"time"
+)
+
+// WebhooksQueued receives a value whenever webhook deliveries may have been queued, so that they can be delivered
+// right away instead of waiting for the next periodic run
+func (transactionManager *TransactionManager) WebhooksQueued() <-chan struct{} {
+	return transactionManager.webhooksQueued
+}
+
+func (transactionManager *TransactionManager) CreateWebhookSubscription(url string, eventTypes []string, secret string, connection *database.Connection) (*models.WebhookSubscription, error) {
+	db := connection.GetDb()
+	subscription := &models.WebhookSubscription{
+		Url:        url,
+		EventTypes: strings.Join(helpers.RemoveDuplicatesInStringSlice(eventTypes), ","),
+		Secret:     secret,
+		CreatedAt:  time.Now(),
+	}
+	if err := transactionManager.webhookSubscriptionRepo.CreateSubscription(subscription, db); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (transactionManager *TransactionManager) RetrieveWebhookSubscriptions(connection *database.Connection) ([]*models.WebhookSubscription, error) {
+	db := connection.GetDb()
+	return transactionManager.webhookSubscriptionRepo.GetAllSubscriptions(db)
+}
+
+// DeleteWebhookSubscription deletes the subscription together with its delivery log, deliveries still queued are
+// dropped
+func (transactionManager *TransactionManager) DeleteWebhookSubscription(subscriptionId uint, connection *database.Connection) (userError error, dbError error) {
+	db := connection.GetDb()
+	dbError = transactionManager.transactor.Transaction(db, func(tx *gorm.DB) error {
+		if subscription, err := transactionManager.webhookSubscriptionRepo.GetSubscriptionById(subscriptionId, tx); err != nil {
+			return err
+		} else if subscription == nil {
+			userError = generateNonExistentWebhookError(subscriptionId)
+			return nil
+		}
+
+		if err := transactionManager.webhookDeliveryRepo.DeleteWebhookDeliveriesBySubscriptionId(subscriptionId, tx); err != nil {
+			return err
+		}
+		return transactionManager.webhookSubscriptionRepo.DeleteSubscription(subscriptionId, tx)
+	})
+	return userError, dbError
+}
+
+// RetrieveWebhookDeliveries returns a page of at most limit deliveries of the log of a subscription, newest first and
+// starting before the delivery with id beforeId unless it is 0, whether there are more deliveries after the page, and
+// the total number of deliveries in the log
+func (transactionManager *TransactionManager) RetrieveWebhookDeliveries(subscriptionId uint, beforeId uint, limit int, connection *database.Connection) (deliveries []*models.WebhookDelivery, hasMore bool, total int64, userError error, dbError error) {
+	db := connection.GetDb()
+
+	if subscription, dbError := transactionManager.webhookSubscriptionRepo.GetSubscriptionById(subscriptionId, db); dbError != nil {
+		return nil, false, 0, nil, dbError
+	} else if subscription == nil {
+		return nil, false, 0, generateNonExistentWebhookError(subscriptionId), nil
+	}
+
+	if deliveries, dbError = transactionManager.webhookDeliveryRepo.GetWebhookDeliveriesBySubscriptionId(subscriptionId, beforeId, limit+1, db); dbError != nil {
+		return nil, false, 0, nil, dbError
+	}
+	deliveries, hasMore = splitPage(deliveries, limit)
+	total, dbError = transactionManager.webhookDeliveryRepo.CountWebhookDeliveriesBySubscriptionId(subscriptionId, db)
+	return deliveries, hasMore, total, nil, dbError
+}
+
+// DeliverPendingWebhooks posts every webhook delivery that is due at the given time. A failed delivery is retried
+// after retryBackoff, doubling for every further failure, until it has been attempted maxAttempts times.
+func (transactionManager *TransactionManager) DeliverPendingWebhooks(sender webhooks.Sender, maxAttempts int, retryBackoff time.Duration, at time.Time, connection *database.Connection) error {
+	db := connection.GetDb()
+
+	deliveries, err := transactionManager.webhookDeliveryRepo.GetDueWebhookDeliveries(at, DELIVERY_BATCH_SIZE, db)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		delivery.Attempts++
+
+		statusCode, sendErr := sender.Send(delivery.Subscription.Url, delivery.Subscription.Secret, delivery.EventType, delivery.ID, []byte(delivery.Payload))
+		delivery.ResponseStatus = statusCode
+
+		if sendErr == nil {
+			deliveredAt := time.Now()
+			delivery.Status = models.DELIVERY_SENT
+			delivery.LastError = ""
+			delivery.DeliveredAt = &deliveredAt
+		} else if delivery.Attempts >= maxAttempts {
+			delivery.Status = models.DELIVERY_FAILED
+			delivery.LastError = sendErr.Error()
+		} else {
+			delivery.LastError = sendErr.Error()
+			delivery.NextAttemptAt = at.Add(retryDelay(retryBackoff, delivery.Attempts))
+		}
+
+		if err := transactionManager.webhookDeliveryRepo.UpdateWebhookDelivery(delivery, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueWebhookEvent queues a delivery of the event to every subscription of its type, in the transaction of the
+// change that caused the event so that the event is only delivered if the change is committed
+func (transactionManager *TransactionManager) enqueueWebhookEvent(eventType string, data interface{}, occurredAt time.Time, tx *gorm.DB) error {
+	subscriptions, err := transactionManager.webhookSubscriptionRepo.GetAllSubscriptions(tx)
+	if err != nil {
+		return err
+	}
+
+	subscriptions = helpers.Filter(subscriptions, func(subscription *models.WebhookSubscription) bool {
+		return subscription.IsSubscribedTo(eventType)
+	})
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(&types.WebhookEvent{Event: eventType, OccurredAt: occurredAt, Data: data})
+	if err != nil {
+		return err
+	}
+
+	deliveries := helpers.Map(subscriptions, func(subscription *models.WebhookSubscription) *models.WebhookDelivery {
+		return &models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         models.DELIVERY_PENDING,
+			NextAttemptAt:  occurredAt,
+			CreatedAt:      occurredAt,
+		}
+	})
+	return transactionManager.webhookDeliveryRepo.CreateWebhookDeliveries(deliveries, tx)
+}
+
+func generateNonExistentWebhookError(subscriptionId uint) error {
+	return problems.Newf(problems.WEBHOOK_NOT_FOUND, "", subscriptionId)
+}
@@ -1,27 +1,316 @@
 package types
 
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"time"
+)
+
 type RegisterStudentsToTeacherRequest struct {
 	TeacherEmail  string   `json:"teacher" binding:"required"`
 	StudentEmails []string `json:"students" binding:"required"`
+	CourseCode    string   `json:"course"`
+	SectionName   string   `json:"section"`
+}
+
+type UnregisterStudentsFromTeacherRequest struct {
+	TeacherEmail  string   `json:"teacher" binding:"required"`
+	StudentEmails []string `json:"students" binding:"required"`
 }
 
 type StudentSuspensionRequest struct {
+	StudentEmail string     `json:"student" binding:"required"`
+	SuspendedBy  string     `json:"suspended_by"`
+	Reason       string     `json:"reason"`
+	Until        *time.Time `json:"until"`
+}
+
+type StudentUnsuspensionRequest struct {
 	StudentEmail string `json:"student" binding:"required"`
 }
 
+type RetrieveStudentSuspensionsRequest struct {
+	StudentEmail string `uri:"email" binding:"required"`
+}
+
 type RetrieveCommonStudentsRequest struct {
 	TeacherEmails []string `form:"teacher" binding:"required"`
+	CourseCode    string   `form:"course"`
+	Mode          string   `form:"mode"`
+	Limit         int      `form:"limit"`
+	Cursor        string   `form:"cursor"`
 }
 
 type RetrieveStudentRecipientsRequest struct {
 	TeacherEmail        string `json:"teacher" binding:"required"`
 	NotificationMessage string `json:"notification" binding:"required"`
+	Limit               int    `json:"-" form:"limit"`
 }
 
-type PopulateStudentsRequest struct {
+type RetrieveMoreStudentRecipientsRequest struct {
+	Cursor string `form:"cursor" binding:"required"`
+	Limit  int    `form:"limit"`
+}
+
+type RetrieveStudentNotificationsRequest struct {
+	StudentEmail string `uri:"email" binding:"required"`
+	Limit        int    `form:"limit"`
+	Cursor       string `form:"cursor"`
+	UnreadOnly   bool   `form:"unread"`
+}
+
+type MarkNotificationAsReadRequest struct {
+	StudentEmail   string `uri:"email" binding:"required"`
+	NotificationId uint   `uri:"id" binding:"required"`
+}
+
+type RetrieveNotificationDeliveriesRequest struct {
+	NotificationId uint `uri:"id" binding:"required"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	Url        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"events" binding:"required"`
+	Secret     string   `json:"secret" binding:"required"`
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	SubscriptionId uint `uri:"id" binding:"required"`
+}
+
+type RetrieveWebhookDeliveriesRequest struct {
+	SubscriptionId uint   `uri:"id" binding:"required"`
+	Limit          int    `form:"limit"`
+	Cursor         string `form:"cursor"`
+}
+
+type ImportRosterRequest struct {
+	File   *multipart.FileHeader `form:"file" binding:"required"`
+	DryRun bool                  `form:"dry_run"`
+}
+
+type ExportRosterRequest struct {
+	Format string `form:"format"`
+}
+
+type SaveCourseRequest struct {
+	CourseCode string `json:"-" uri:"code"`
+	Name       string `json:"name" binding:"required"`
+}
+
+type RetrieveCourseRequest struct {
+	CourseCode string `uri:"code" binding:"required"`
+}
+
+type SaveSectionRequest struct {
+	CourseCode    string   `json:"-" uri:"code"`
+	SectionName   string   `json:"-" uri:"section"`
+	TeacherEmails []string `json:"teachers"`
+}
+
+type RetrieveSectionRequest struct {
+	CourseCode  string `uri:"code" binding:"required"`
+	SectionName string `uri:"section" binding:"required"`
+}
+
+type EnrollStudentsRequest struct {
+	CourseCode    string   `json:"-" uri:"code"`
+	SectionName   string   `json:"-" uri:"section"`
 	StudentEmails []string `json:"students" binding:"required"`
 }
 
+type CreateAssignmentRequest struct {
+	TeacherEmail string    `json:"-" uri:"email"`
+	Title        string    `json:"title" binding:"required"`
+	Description  string    `json:"description"`
+	DueAt        time.Time `json:"due_at" binding:"required"`
+	MaxScore     int       `json:"max_score" binding:"required"`
+}
+
+type RetrieveTeacherAssignmentsRequest struct {
+	TeacherEmail string `uri:"email" binding:"required"`
+}
+
+type RetrieveAssignmentRequest struct {
+	AssignmentId uint `uri:"id" binding:"required"`
+}
+
+// SubmitAssignmentRequest holds the work of the student, a text, a reference to a file stored elsewhere or both
+type SubmitAssignmentRequest struct {
+	AssignmentId  uint   `json:"-" uri:"id"`
+	StudentEmail  string `json:"-" uri:"email"`
+	Text          string `json:"text"`
+	FileReference string `json:"file"`
+}
+
+type RetrieveAssignmentSubmissionsRequest struct {
+	AssignmentId uint   `uri:"id" binding:"required"`
+	Status       string `form:"status"`
+}
+
+type RetrieveTeacherSubmissionsRequest struct {
+	TeacherEmail string `uri:"email" binding:"required"`
+	Status       string `form:"status"`
+}
+
+type SaveGradeCategoryRequest struct {
+	TeacherEmail string  `json:"-" uri:"email"`
+	Name         string  `json:"-" uri:"name"`
+	Weight       float64 `json:"weight" binding:"required"`
+}
+
+type RetrieveGradeCategoriesRequest struct {
+	TeacherEmail string `uri:"email" binding:"required"`
+}
+
+// GradeStudentRequest gives a grade in a category of the teacher, a grade for an assignment defaults to its title and
+// max score
+type GradeStudentRequest struct {
+	TeacherEmail string   `json:"-" uri:"email"`
+	StudentEmail string   `json:"-" uri:"student"`
+	CategoryName string   `json:"category" binding:"required"`
+	Title        string   `json:"title"`
+	Score        *float64 `json:"score" binding:"required"`
+	MaxScore     float64  `json:"max_score"`
+	AssignmentId *uint    `json:"assignment"`
+}
+
+type RetrieveGradebookRequest struct {
+	TeacherEmail string `uri:"email" binding:"required"`
+	StudentEmail string `uri:"student" binding:"required"`
+}
+
+type RetrieveReportCardRequest struct {
+	StudentEmail string `uri:"email" binding:"required"`
+}
+
+// RecordRollCallRequest records the status of each student on the date of the path, keyed by the email of the student
+type RecordRollCallRequest struct {
+	TeacherEmail string            `json:"-" uri:"email"`
+	Date         string            `json:"-" uri:"date"`
+	Statuses     map[string]string `json:"students" binding:"required"`
+}
+
+type RetrieveRollCallRequest struct {
+	TeacherEmail string `uri:"email" binding:"required"`
+	Date         string `uri:"date" binding:"required"`
+}
+
+type RetrieveStudentAttendanceRequest struct {
+	TeacherEmail string `uri:"email" binding:"required"`
+	StudentEmail string `uri:"student" binding:"required"`
+	FromDate     string `form:"from"`
+	ToDate       string `form:"to"`
+}
+
+// RetrieveAttendanceRatesRequest keeps the students whose attendance rate is below the percentage when one is given
+type RetrieveAttendanceRatesRequest struct {
+	TeacherEmail string   `uri:"email" binding:"required"`
+	FromDate     string   `form:"from"`
+	ToDate       string   `form:"to"`
+	Below        *float64 `form:"below"`
+}
+
+type PopulateStudentsRequest struct {
+	Students []PopulateEntry `json:"students" binding:"required"`
+}
+
 type PopulateTeachersRequest struct {
-	TeacherEmails []string `json:"teachers" binding:"required"`
-}
\ No newline at end of file
+	Teachers []PopulateEntry `json:"teachers" binding:"required"`
+}
+
+// PopulateEntry is a teacher or a student to populate, given either as an email string or as an object holding the
+// email and the fields of their profile
+type PopulateEntry struct {
+	Email       string            `json:"email" binding:"required"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	GradeLevel  string            `json:"grade_level"`
+	Homeroom    string            `json:"homeroom"`
+	Phone       string            `json:"phone"`
+	Metadata    map[string]string `json:"metadata"`
+	// HasProfile is set for the entries given as objects, whose profile replaces the profile already saved
+	HasProfile bool `json:"-"`
+}
+
+func (entry *PopulateEntry) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		*entry = PopulateEntry{}
+		err := json.Unmarshal(data, &entry.Email)
+		// the decoder of the request does not add the path to the errors of this method, the field is named instead
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			typeErr.Field = "email"
+		}
+		return err
+	}
+
+	// the object is decoded into a type without this method, which would otherwise call itself
+	type object PopulateEntry
+	if err := json.Unmarshal(data, (*object)(entry)); err != nil {
+		return err
+	}
+	entry.HasProfile = true
+	return nil
+}
+
+type RetrieveSwaggerUiAssetRequest struct {
+	File string `uri:"file" binding:"required"`
+}
+
+type RetrieveProfileRequest struct {
+	Email string `uri:"email" binding:"required"`
+}
+
+// UpdateProfileRequest changes the profile fields it holds and keeps the fields left out. An empty string clears a
+// field, and an empty object clears the metadata.
+type UpdateProfileRequest struct {
+	Email       string            `json:"-" uri:"email"`
+	Name        *string           `json:"name"`
+	DisplayName *string           `json:"display_name"`
+	GradeLevel  *string           `json:"grade_level"`
+	Homeroom    *string           `json:"homeroom"`
+	Phone       *string           `json:"phone"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+type DeleteProfileRequest struct {
+	Email string `uri:"email" binding:"required"`
+}
+
+// The requests of the v2 api take the email or id they act on from the path, the other fields keep their v1 names
+
+type RegisterStudentsToTeacherV2Request struct {
+	TeacherEmail  string   `json:"-" uri:"email"`
+	StudentEmails []string `json:"students" binding:"required"`
+	CourseCode    string   `json:"course"`
+	SectionName   string   `json:"section"`
+}
+
+type UnregisterStudentsFromTeacherV2Request struct {
+	TeacherEmail  string   `json:"-" uri:"email"`
+	StudentEmails []string `json:"students" binding:"required"`
+}
+
+type StudentSuspensionV2Request struct {
+	StudentEmail string     `json:"-" uri:"email"`
+	SuspendedBy  string     `json:"suspended_by"`
+	Reason       string     `json:"reason"`
+	Until        *time.Time `json:"until"`
+}
+
+type StudentUnsuspensionV2Request struct {
+	StudentEmail string `uri:"email" binding:"required"`
+}
+
+type SendNotificationV2Request struct {
+	TeacherEmail        string `json:"-" uri:"email"`
+	NotificationMessage string `json:"notification" binding:"required"`
+	Limit               int    `json:"-" form:"limit"`
+}
+
+type RetrieveNotificationRecipientsV2Request struct {
+	NotificationId uint   `uri:"id" binding:"required"`
+	Cursor         string `form:"cursor"`
+	Limit          int    `form:"limit"`
+}
@@ -1,13 +1,334 @@
 package types
 
+import "time"
+
 type RetrieveRegisteredStudentsResponse struct {
 	StudentEmails []string `json:"students"`
+	NextCursor    string   `json:"next_cursor,omitempty"`
 }
 
 type RetrieveCommonStudentsResponse struct {
 	StudentEmails []string `json:"recipients"`
+	NextCursor    string   `json:"next_cursor,omitempty"`
+}
+
+// RetrieveNotificationRecipientsResponse is the v2 page of the recipients of a notification
+type RetrieveNotificationRecipientsResponse struct {
+	NotificationId uint     `json:"id"`
+	StudentEmails  []string `json:"recipients"`
+	NextCursor     string   `json:"next_cursor,omitempty"`
+}
+
+type UnregisterStudentsFromTeacherResponse struct {
+	UnregisteredStudentEmails  []string `json:"unregistered"`
+	NotRegisteredStudentEmails []string `json:"not_registered"`
+}
+
+type StudentSuspensionResponse struct {
+	SuspendedBy string     `json:"suspended_by"`
+	Reason      string     `json:"reason"`
+	SuspendedAt time.Time  `json:"suspended_at"`
+	Until       *time.Time `json:"until"`
+	LiftedAt    *time.Time `json:"lifted_at"`
+}
+
+type RetrieveStudentSuspensionsResponse struct {
+	StudentEmail string                       `json:"student"`
+	Suspensions  []*StudentSuspensionResponse `json:"suspensions"`
+}
+
+type NotificationResponse struct {
+	NotificationId uint       `json:"id"`
+	TeacherEmail   string     `json:"teacher"`
+	Message        string     `json:"notification"`
+	SentAt         time.Time  `json:"sent_at"`
+	Read           bool       `json:"read"`
+	ReadAt         *time.Time `json:"read_at"`
+}
+
+type RetrieveStudentNotificationsResponse struct {
+	StudentEmail  string                  `json:"student"`
+	Notifications []*NotificationResponse `json:"notifications"`
+	Total         int64                   `json:"total"`
+	Unread        int64                   `json:"unread"`
+	NextCursor    string                  `json:"next_cursor,omitempty"`
+}
+
+type NotificationDeliveryResponse struct {
+	StudentEmail string     `json:"student"`
+	Status       string     `json:"status"`
+	Attempts     int        `json:"attempts"`
+	LastError    string     `json:"last_error"`
+	DeliveredAt  *time.Time `json:"delivered_at"`
+}
+
+type RetrieveNotificationDeliveriesResponse struct {
+	NotificationId uint                            `json:"id"`
+	Deliveries     []*NotificationDeliveryResponse `json:"deliveries"`
+}
+
+type WebhookSubscriptionResponse struct {
+	SubscriptionId uint      `json:"id"`
+	Url            string    `json:"url"`
+	EventTypes     []string  `json:"events"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type RetrieveWebhookSubscriptionsResponse struct {
+	Subscriptions []*WebhookSubscriptionResponse `json:"webhooks"`
 }
 
-type ErrorResponse struct {
+type WebhookDeliveryResponse struct {
+	DeliveryId     uint       `json:"id"`
+	EventType      string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+type RetrieveWebhookDeliveriesResponse struct {
+	SubscriptionId uint                       `json:"id"`
+	Deliveries     []*WebhookDeliveryResponse `json:"deliveries"`
+	Total          int64                      `json:"total"`
+	NextCursor     string                     `json:"next_cursor,omitempty"`
+}
+
+// ProblemResponse is an RFC 7807 problem details object, whose code and detail are those of the first of its errors.
+// The problems of v1 also describe their first problem in message, the member of the error bodies that predate problems.
+type ProblemResponse struct {
+	Type    string                   `json:"type"`
+	Title   string                   `json:"title"`
+	Status  int                      `json:"status"`
+	Code    string                   `json:"code"`
+	Detail  string                   `json:"detail"`
+	Errors  []*ProblemDetailResponse `json:"errors"`
+	Message string                   `json:"message,omitempty"`
+}
+
+type ProblemDetailResponse struct {
+	Code   string   `json:"code"`
+	Field  string   `json:"field,omitempty"`
+	Emails []string `json:"emails,omitempty"`
+	Detail string   `json:"detail"`
+}
+
+type ImportRowErrorResponse struct {
+	Line    int    `json:"line"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+// ImportRosterResponse counts the registrations the import creates apart from those of the roster that already exist
+type ImportRosterResponse struct {
+	DryRun                bool                      `json:"dry_run"`
+	Committed             bool                      `json:"committed"`
+	Rows                  int                       `json:"rows"`
+	Teachers              int                       `json:"teachers"`
+	Students              int                       `json:"students"`
+	Registrations         int                       `json:"registrations"`
+	ExistingRegistrations int                       `json:"existing_registrations"`
+	InvalidRows           int                       `json:"invalid_rows"`
+	Errors                []*ImportRowErrorResponse `json:"errors"`
+}
+
+// ExportedTeacher, ExportedStudent and ExportedRegistration are the records of a roster export, the type is only
+// set in ndjson exports where the records of every kind share one stream
+type ExportedTeacher struct {
+	Type  string `json:"type,omitempty"`
+	Email string `json:"email"`
+}
+
+type ExportedStudent struct {
+	Type        string `json:"type,omitempty"`
+	Email       string `json:"email"`
+	IsSuspended bool   `json:"suspended"`
+}
+
+type ExportedRegistration struct {
+	Type         string `json:"type,omitempty"`
+	TeacherEmail string `json:"teacher"`
+	StudentEmail string `json:"student"`
+}
+
+// ExportedRoster is the shape of a json export, which is streamed record by record rather than marshalled from it
+type ExportedRoster struct {
+	Teachers      []*ExportedTeacher      `json:"teachers"`
+	Students      []*ExportedStudent      `json:"students"`
+	Registrations []*ExportedRegistration `json:"registrations"`
+}
+
+type SectionResponse struct {
+	CourseCode    string   `json:"course"`
+	Name          string   `json:"name"`
+	TeacherEmails []string `json:"teachers"`
+	StudentEmails []string `json:"students"`
+}
+
+// CourseResponse lists the sections of the course when a single course is retrieved
+type CourseResponse struct {
+	Code     string             `json:"code"`
+	Name     string             `json:"name"`
+	Sections []*SectionResponse `json:"sections,omitempty"`
+}
+
+type RetrieveCoursesResponse struct {
+	Courses []*CourseResponse `json:"courses"`
+}
+
+type AssignmentResponse struct {
+	AssignmentId uint      `json:"id"`
+	TeacherEmail string    `json:"teacher"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	DueAt        time.Time `json:"due_at"`
+	MaxScore     int       `json:"max_score"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RetrieveAssignmentsResponse struct {
+	TeacherEmail string                `json:"teacher"`
+	Assignments  []*AssignmentResponse `json:"assignments"`
+}
+
+// SubmissionResponse is the work of a student on an assignment, only the work that was submitted has a text, a file
+// and a submission time
+type SubmissionResponse struct {
+	AssignmentId  uint       `json:"assignment"`
+	StudentEmail  string     `json:"student"`
+	Status        string     `json:"status"`
+	Text          string     `json:"text,omitempty"`
+	FileReference string     `json:"file,omitempty"`
+	SubmittedAt   *time.Time `json:"submitted_at"`
+}
+
+type RetrieveSubmissionsResponse struct {
+	Submissions []*SubmissionResponse `json:"submissions"`
+}
+
+type GradeCategoryResponse struct {
+	TeacherEmail string  `json:"teacher"`
+	Name         string  `json:"name"`
+	Weight       float64 `json:"weight"`
+}
+
+type RetrieveGradeCategoriesResponse struct {
+	TeacherEmail string                   `json:"teacher"`
+	Categories   []*GradeCategoryResponse `json:"categories"`
+}
+
+type GradeResponse struct {
+	GradeId      uint      `json:"id"`
+	TeacherEmail string    `json:"teacher"`
+	StudentEmail string    `json:"student"`
+	CategoryName string    `json:"category"`
+	AssignmentId *uint     `json:"assignment"`
+	Title        string    `json:"title"`
+	Score        float64   `json:"score"`
+	MaxScore     float64   `json:"max_score"`
+	GradedAt     time.Time `json:"graded_at"`
+}
+
+// CategoryGradesResponse holds the grades of a category, the average is a percentage and is null without grades
+type CategoryGradesResponse struct {
+	Name    string           `json:"name"`
+	Weight  float64          `json:"weight"`
+	Average *float64         `json:"average"`
+	Grades  []*GradeResponse `json:"grades"`
+}
+
+// GradebookResponse holds the grades of a student with a teacher, the average weighs the averages of the categories
+// and the letter grade is left out without grades
+type GradebookResponse struct {
+	TeacherEmail string                    `json:"teacher"`
+	StudentEmail string                    `json:"student"`
+	Categories   []*CategoryGradesResponse `json:"categories"`
+	Average      *float64                  `json:"average"`
+	LetterGrade  string                    `json:"letter_grade,omitempty"`
+}
+
+type ReportCardEntryResponse struct {
+	TeacherEmail string   `json:"teacher"`
+	Average      *float64 `json:"average"`
+	LetterGrade  string   `json:"letter_grade,omitempty"`
+}
+
+// ReportCardResponse holds the grades of a student with every teacher they are registered to, the average is the
+// average of the averages of the teachers
+type ReportCardResponse struct {
+	StudentEmail string                     `json:"student"`
+	Teachers     []*ReportCardEntryResponse `json:"teachers"`
+	Average      *float64                   `json:"average"`
+	LetterGrade  string                     `json:"letter_grade,omitempty"`
+}
+
+type AttendanceResponse struct {
+	StudentEmail string `json:"student"`
+	Status       string `json:"status"`
+}
+
+// RollCallResponse holds the attendance recorded on a date, and the registered students whose attendance is not
+type RollCallResponse struct {
+	TeacherEmail            string                `json:"teacher"`
+	Date                    string                `json:"date"`
+	Students                []*AttendanceResponse `json:"students"`
+	UnrecordedStudentEmails []string              `json:"unrecorded"`
+}
+
+type AttendanceRecordResponse struct {
+	Date   string `json:"date"`
+	Status string `json:"status"`
+}
+
+// AttendanceRateResponse counts the attendances of a student by status, the rate is a percentage that leaves out the
+// excused absences and is null without any other attendance
+type AttendanceRateResponse struct {
+	StudentEmail string   `json:"student"`
+	Present      int      `json:"present"`
+	Absent       int      `json:"absent"`
+	Late         int      `json:"late"`
+	Excused      int      `json:"excused"`
+	Rate         *float64 `json:"rate"`
+}
+
+type StudentAttendanceResponse struct {
+	TeacherEmail string                      `json:"teacher"`
+	StudentEmail string                      `json:"student"`
+	Present      int                         `json:"present"`
+	Absent       int                         `json:"absent"`
+	Late         int                         `json:"late"`
+	Excused      int                         `json:"excused"`
+	Rate         *float64                    `json:"rate"`
+	Records      []*AttendanceRecordResponse `json:"records"`
+}
+
+type RetrieveAttendanceRatesResponse struct {
+	TeacherEmail string                    `json:"teacher"`
+	Students     []*AttendanceRateResponse `json:"students"`
+}
+
+// StudentResponse is a student with their profile, the profile fields that are not set are left out
+type StudentResponse struct {
+	Email       string            `json:"email"`
+	IsSuspended bool              `json:"suspended"`
+	Name        string            `json:"name,omitempty"`
+	DisplayName string            `json:"display_name,omitempty"`
+	GradeLevel  string            `json:"grade_level,omitempty"`
+	Homeroom    string            `json:"homeroom,omitempty"`
+	Phone       string            `json:"phone,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// TeacherResponse is a teacher with their profile, the profile fields that are not set are left out
+type TeacherResponse struct {
+	Email       string            `json:"email"`
+	Name        string            `json:"name,omitempty"`
+	DisplayName string            `json:"display_name,omitempty"`
+	GradeLevel  string            `json:"grade_level,omitempty"`
+	Homeroom    string            `json:"homeroom,omitempty"`
+	Phone       string            `json:"phone,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
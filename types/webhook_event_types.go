@@ -0,0 +1,29 @@
+package types
+
+import "time"
+
+// WebhookEvent is the signed json body posted to webhook subscriptions
+type WebhookEvent struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type StudentsRegisteredEvent struct {
+	TeacherEmail  string   `json:"teacher"`
+	StudentEmails []string `json:"students"`
+}
+
+type StudentSuspendedEvent struct {
+	StudentEmail string     `json:"student"`
+	SuspendedBy  string     `json:"suspended_by"`
+	Reason       string     `json:"reason"`
+	Until        *time.Time `json:"until"`
+}
+
+type NotificationSentEvent struct {
+	NotificationId  uint     `json:"id"`
+	TeacherEmail    string   `json:"teacher"`
+	Message         string   `json:"notification"`
+	RecipientEmails []string `json:"recipients"`
+}
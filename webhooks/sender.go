@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers set on every webhook request
+
+const EVENT_HEADER = "X-LMS-Event"
+const DELIVERY_HEADER = "X-LMS-Delivery"
+const SIGNATURE_HEADER = "X-LMS-Signature"
+
+const SIGNATURE_PREFIX = "sha256="
+
+// Sender posts a signed event payload to a webhook url and returns the http status of the response, a status
+// outside of 2xx is reported as an error
+type Sender interface {
+	Send(url string, secret string, eventType string, deliveryId uint, payload []byte) (statusCode int, err error)
+}
+
+type HttpSender struct {
+	client *http.Client
+}
+
+// NewHttpSender returns a sender that gives up on a request after the timeout
+func NewHttpSender(timeout time.Duration) *HttpSender {
+	return &HttpSender{client: &http.Client{Timeout: timeout}}
+}
+
+func (sender *HttpSender) Send(url string, secret string, eventType string, deliveryId uint, payload []byte) (statusCode int, err error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EVENT_HEADER, eventType)
+	request.Header.Set(DELIVERY_HEADER, strconv.FormatUint(uint64(deliveryId), 10))
+	request.Header.Set(SIGNATURE_HEADER, Sign(secret, payload))
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// Sign returns the signature header value of a payload, the hex encoded HMAC-SHA256 of the payload keyed with the
+// subscription secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature header value matches the payload, receivers can use it to authenticate webhooks
+func Verify(secret string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}